@@ -3,20 +3,28 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Toggly/core/internal/server"
 	"github.com/Toggly/core/internal/server/rest"
 
+	"github.com/Toggly/core/internal/pkg/auditapi"
 	"github.com/Toggly/core/internal/pkg/cache"
 	"github.com/Toggly/core/internal/pkg/cache/cachedapi"
+	"github.com/Toggly/core/internal/pkg/cluster"
 	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/eventbus"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/bolt"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
 	"github.com/Toggly/core/internal/pkg/storage/mongo"
+	"github.com/Toggly/core/internal/pkg/webhook"
 	flags "github.com/jessevdk/go-flags"
 )
 
@@ -25,14 +33,20 @@ var revision = "development" //revision assigned on build
 // Opts describes application command line arguments
 type Opts struct {
 	Toggly struct {
-		Version  bool   `short:"v" long:"version"`
-		Port     int    `short:"p" long:"port" env:"API_PORT" default:"8080" description:"Port"`
-		BasePath string `long:"base-path" env:"API_BASE_PATH" default:"/api" description:"Base API Path"`
-		NoLogo   bool   `long:"no-logo" description:"Do not show application logo"`
-		Store    struct {
+		Version         bool   `short:"v" long:"version"`
+		Port            int    `short:"p" long:"port" env:"API_PORT" default:"8080" description:"Port"`
+		BasePath        string `long:"base-path" env:"API_BASE_PATH" default:"/api" description:"Base API Path"`
+		NoLogo          bool   `long:"no-logo" description:"Do not show application logo"`
+		DisabledProject string `long:"disabled-project" choice:"read-only" choice:"unavailable" env:"DISABLED_PROJECT" default:"read-only" description:"Disabled project mode"`
+		MasterKey       string `long:"master-key" env:"MASTER_KEY" description:"Master API key"`
+		Store           struct {
+			Type  string `long:"type" choice:"mongo" choice:"memory" choice:"bolt" env:"TYPE" default:"mongo" description:"Storage type"`
 			Mongo struct {
 				URL string `long:"url" env:"URL" description:"Mongo connection url"`
 			} `group:"mongo" namespace:"mongo" env-namespace:"MONGO"`
+			Bolt struct {
+				Path string `long:"path" env:"PATH" default:"toggly.db" description:"Bolt database file path"`
+			} `group:"bolt" namespace:"bolt" env-namespace:"BOLT"`
 		} `group:"store" namespace:"store" env-namespace:"STORE"`
 		Cache struct {
 			Type  string `long:"type" choice:"memory" choice:"redis" env:"TYPE" description:"Cache type"`
@@ -40,6 +54,24 @@ type Opts struct {
 				URL string `long:"url" env:"URL" description:"Redis connection url"`
 			} `group:"redis" namespace:"redis" env-namespace:"REDIS"`
 		} `group:"cache" namespace:"cache" env-namespace:"CACHE"`
+		Audit struct {
+			Sinks []string `long:"sink" choice:"storage" choice:"file" choice:"stdout" env:"SINK" env-delim:"," description:"Audit log sink, can be repeated"`
+			File  struct {
+				Path string `long:"path" env:"PATH" default:"audit.log" description:"Audit log file path"`
+			} `group:"file" namespace:"file" env-namespace:"FILE"`
+		} `group:"audit" namespace:"audit" env-namespace:"AUDIT"`
+		Webhook struct {
+			Attempts int           `long:"attempts" env:"ATTEMPTS" default:"5" description:"Webhook delivery attempts"`
+			Backoff  time.Duration `long:"backoff" env:"BACKOFF" default:"1s" description:"Delay before the first delivery retry, doubled on every retry"`
+		} `group:"webhook" namespace:"webhook" env-namespace:"WEBHOOK"`
+		Stream struct {
+			History int `long:"history" env:"HISTORY" default:"1000" description:"Number of events kept to resume streams"`
+			Buffer  int `long:"buffer" env:"BUFFER" default:"64" description:"Stream buffer size, slow clients are disconnected on overflow"`
+		} `group:"stream" namespace:"stream" env-namespace:"STREAM"`
+		Cluster struct {
+			Type    string `long:"type" choice:"redis" env:"TYPE" description:"Change propagation between server instances, Redis uses cache connection url"`
+			Channel string `long:"channel" env:"CHANNEL" default:"toggly" description:"Redis pub/sub channel"`
+		} `group:"cluster" namespace:"cluster" env-namespace:"CLUSTER"`
 	} `group:"toggly" env-namespace:"TOGGLY"`
 }
 
@@ -92,17 +124,90 @@ func main() {
 		log.Print("[WARN] No cache type specified. Cache disabled.")
 	}
 
-	if dataStorage, err = mongo.NewMongoStorage(opts.Toggly.Store.Mongo.URL); err != nil {
-		log.Fatalf("[FATAL] Can't connect to storage: %+v", err)
+	switch opts.Toggly.Store.Type {
+	case "memory":
+		log.Print("[WARN] In-memory storage is used. Data will be lost on exit.")
+		dataStorage = memory.NewMemoryStorage()
+	case "bolt":
+		if dataStorage, err = bolt.NewBoltStorage(opts.Toggly.Store.Bolt.Path); err != nil {
+			log.Fatalf("[FATAL] Can't open storage: %+v", err)
+		}
+	default:
+		if dataStorage, err = mongo.NewMongoStorage(opts.Toggly.Store.Mongo.URL); err != nil {
+			log.Fatalf("[FATAL] Can't connect to storage: %+v", err)
+		}
+	}
+
+	dispatcher := webhook.NewDispatcher(&dataStorage, opts.Toggly.Webhook.Attempts, opts.Toggly.Webhook.Backoff)
+	bus := eventbus.New(opts.Toggly.Stream.History, opts.Toggly.Stream.Buffer)
+	events := engine.MultiPublisher(dispatcher, bus)
+
+	var node *cluster.Node
+	switch opts.Toggly.Cluster.Type {
+	case "redis":
+		transport, err := cluster.NewRedisTransport(opts.Toggly.Cache.Redis.URL, opts.Toggly.Cluster.Channel)
+		if err != nil {
+			log.Fatalf("[FATAL] Can't connect to cluster: %+v", err)
+		}
+		// shared Redis cache doesn't need invalidation of other instances
+		var localCache cache.DataCache
+		if opts.Toggly.Cache.Type == "memory" {
+			localCache = dataCache
+		}
+		node = cluster.NewNode(transport, localCache, bus)
+		if localCache != nil {
+			dataCache = node.Cache()
+		}
+		events = engine.MultiPublisher(dispatcher, bus, node)
+	default:
+		if opts.Toggly.Cache.Type == "memory" {
+			log.Print("[WARN] No cluster type specified. In-memory cache is not shared with other instances.")
+		}
+	}
+
+	togglyAPI := &engine.Engine{
+		Storage:             &dataStorage,
+		DryRunStorage:       memory.NewMemoryStorage,
+		DisabledProjectMode: engine.DisabledProjectMode(opts.Toggly.DisabledProject),
+		Events:              events,
+		Webhooks:            dispatcher,
+	}
+
+	var auditSink auditapi.Sink
+	var auditFile *auditapi.FileSink
+	sinks := make([]auditapi.Sink, 0)
+	for _, s := range opts.Toggly.Audit.Sinks {
+		switch s {
+		case "storage":
+			sinks = append(sinks, auditapi.NewStorageSink(&dataStorage))
+		case "file":
+			if auditFile, err = auditapi.NewFileSink(opts.Toggly.Audit.File.Path); err != nil {
+				log.Fatalf("[FATAL] Can't open audit log: %+v", err)
+			}
+			sinks = append(sinks, auditFile)
+		case "stdout":
+			sinks = append(sinks, auditapi.NewWriterSink(os.Stdout))
+		}
+	}
+	if len(sinks) > 0 {
+		auditSink = auditapi.MultiSink(sinks...)
+	} else {
+		log.Print("[WARN] No audit sink specified. Audit log disabled.")
+	}
+
+	if opts.Toggly.MasterKey == "" {
+		log.Print("[WARN] No master key specified. Only owner API keys are accepted.")
 	}
 
 	server := &server.Application{
 		Router: &rest.APIRouter{
-			Version:  revision,
-			API:      cachedapi.NewCachedAPI(engine.NewTogglyAPI(&dataStorage), dataCache),
-			BasePath: opts.Toggly.BasePath,
-			Port:     opts.Toggly.Port,
-			IsDebug:  false,
+			Version:   revision,
+			API:       auditapi.NewAuditAPI(cachedapi.NewCachedAPI(togglyAPI, dataCache), auditSink),
+			Events:    bus,
+			BasePath:  opts.Toggly.BasePath,
+			MasterKey: opts.Toggly.MasterKey,
+			Port:      opts.Toggly.Port,
+			IsDebug:   false,
 		},
 	}
 
@@ -113,6 +218,22 @@ func main() {
 	log.Print("[INFO] API server started")
 
 	server.Run(ctx)
+	if node != nil {
+		if err := node.Close(); err != nil {
+			log.Printf("[WARN] Can't close cluster connection: %+v", err)
+		}
+	}
+	dispatcher.Close()
+	if auditFile != nil {
+		if err := auditFile.Close(); err != nil {
+			log.Printf("[WARN] Can't close audit log: %+v", err)
+		}
+	}
+	if closer, ok := dataStorage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("[WARN] Can't close storage: %+v", err)
+		}
+	}
 	log.Print("[INFO] application terminated")
 	log.Print("[INFO] Bye!")
 }
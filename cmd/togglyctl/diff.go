@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/Toggly/core/pkg/client"
+)
+
+// diffRow is a parameter with different values, nil value means the parameter or object is missing
+type diffRow struct {
+	Object    client.ObjectCode    `json:"object"`
+	Parameter client.ParameterCode `json:"parameter"`
+	Left      interface{}          `json:"left"`
+	Right     interface{}          `json:"right"`
+}
+
+type diffCommand struct {
+	base
+	projectOption
+	Args struct {
+		Left  client.EnvironmentCode `positional-arg-name:"ENV1" required:"yes"`
+		Right client.EnvironmentCode `positional-arg-name:"ENV2" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+// values returns parameter values of environment objects
+func (c *diffCommand) values(env client.EnvironmentCode) (map[client.ObjectCode]map[client.ParameterCode]interface{}, error) {
+	list, err := c.app.client.Projects().For(c.Project).Environments().For(env).Objects().List()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[client.ObjectCode]map[client.ParameterCode]interface{}, len(list))
+	for _, o := range list {
+		params := make(map[client.ParameterCode]interface{}, len(o.Parameters))
+		for _, p := range o.Parameters {
+			params[p.Code] = p.Value
+		}
+		res[o.Code] = params
+	}
+	return res, nil
+}
+
+func (c *diffCommand) Execute(args []string) error {
+	left, err := c.values(c.Args.Left)
+	if err != nil {
+		return err
+	}
+	right, err := c.values(c.Args.Right)
+	if err != nil {
+		return err
+	}
+	type key struct {
+		object    client.ObjectCode
+		parameter client.ParameterCode
+	}
+	keys := make(map[key]bool)
+	for _, values := range []map[client.ObjectCode]map[client.ParameterCode]interface{}{left, right} {
+		for o, params := range values {
+			for p := range params {
+				keys[key{o, p}] = true
+			}
+		}
+	}
+	diff := make([]*diffRow, 0)
+	for k := range keys {
+		l, r := left[k.object][k.parameter], right[k.object][k.parameter]
+		if value(l) != value(r) {
+			diff = append(diff, &diffRow{Object: k.object, Parameter: k.parameter, Left: l, Right: r})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		if diff[i].Object != diff[j].Object {
+			return diff[i].Object < diff[j].Object
+		}
+		return diff[i].Parameter < diff[j].Parameter
+	})
+	rows := make([][]string, len(diff))
+	for i, d := range diff {
+		rows[i] = []string{string(d.Object), string(d.Parameter), diffValue(d.Left), diffValue(d.Right)}
+	}
+	return c.app.print(diff, []string{"OBJECT", "PARAMETER", string(c.Args.Left), string(c.Args.Right)}, rows)
+}
+
+// diffValue formats value, missing value is shown as `-`
+func diffValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	if s, ok := v.(string); ok {
+		data, _ := json.Marshal(s)
+		return string(data)
+	}
+	return value(v)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Toggly/core/pkg/client"
+)
+
+type envCommand struct {
+	List   envList   `command:"list" description:"List environments"`
+	Get    envGet    `command:"get" description:"Show environment"`
+	Create envCreate `command:"create" description:"Create environment"`
+	Update envUpdate `command:"update" description:"Update environment"`
+	Delete envDelete `command:"delete" description:"Delete environment"`
+}
+
+// projectOption selects project of the command
+type projectOption struct {
+	Project client.ProjectCode `short:"p" long:"project" required:"yes" description:"Project code"`
+}
+
+type envArgs struct {
+	Code client.EnvironmentCode `positional-arg-name:"CODE" required:"yes"`
+}
+
+func (a *app) printEnvironments(v interface{}, list ...*client.Environment) error {
+	rows := make([][]string, len(list))
+	for i, e := range list {
+		rows[i] = []string{string(e.Code), strconv.FormatBool(e.Protected), e.Description}
+	}
+	return a.print(v, []string{"CODE", "PROTECTED", "DESCRIPTION"}, rows)
+}
+
+type envList struct {
+	base
+	projectOption
+}
+
+func (c *envList) Execute(args []string) error {
+	list, err := c.app.client.Projects().For(c.Project).Environments().List()
+	if err != nil {
+		return err
+	}
+	return c.app.printEnvironments(list, list...)
+}
+
+type envGet struct {
+	base
+	projectOption
+	Args envArgs `positional-args:"yes"`
+}
+
+func (c *envGet) Execute(args []string) error {
+	e, err := c.app.client.Projects().For(c.Project).Environments().Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	return c.app.printEnvironments(e, e)
+}
+
+type envCreate struct {
+	base
+	projectOption
+	Description string  `short:"d" long:"description" description:"Description"`
+	Protected   bool    `long:"protected" description:"Protect objects from changes"`
+	Args        envArgs `positional-args:"yes"`
+}
+
+func (c *envCreate) Execute(args []string) error {
+	info := &client.EnvironmentInfo{Code: c.Args.Code, Description: c.Description, Protected: c.Protected}
+	e, err := c.app.client.Projects().For(c.Project).Environments().Create(info)
+	if err != nil {
+		return err
+	}
+	return c.app.printEnvironments(e, e)
+}
+
+type envUpdate struct {
+	base
+	projectOption
+	Description *string `short:"d" long:"description" description:"Description"`
+	Protected   bool    `long:"protected" description:"Protect objects from changes"`
+	Unprotected bool    `long:"unprotected" description:"Remove protection"`
+	Args        envArgs `positional-args:"yes"`
+}
+
+func (c *envUpdate) Execute(args []string) error {
+	if c.Protected && c.Unprotected {
+		return fmt.Errorf("--protected and --unprotected can't be used together")
+	}
+	envs := c.app.client.Projects().For(c.Project).Environments()
+	e, err := envs.Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	info := &client.EnvironmentInfo{Code: e.Code, Description: e.Description, Protected: e.Protected, Version: e.Version}
+	if c.Description != nil {
+		info.Description = *c.Description
+	}
+	if c.Protected || c.Unprotected {
+		info.Protected = c.Protected
+	}
+	if e, err = envs.Update(info); err != nil {
+		return err
+	}
+	return c.app.printEnvironments(e, e)
+}
+
+type envDelete struct {
+	base
+	projectOption
+	Args envArgs `positional-args:"yes"`
+}
+
+func (c *envDelete) Execute(args []string) error {
+	return c.app.client.Projects().For(c.Project).Environments().Delete(c.Args.Code)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/client"
+	flags "github.com/jessevdk/go-flags"
+)
+
+var revision = "development" //revision assigned on build
+
+// Opts describes command line arguments, connection options fall back to the profile file
+type Opts struct {
+	Version bool   `short:"v" long:"version" description:"Show version"`
+	URL     string `long:"url" env:"TOGGLY_URL" description:"API url including base path and version, e.g. http://localhost:8080/api/v1"`
+	Key     string `long:"key" env:"TOGGLY_KEY" description:"API key"`
+	Owner   string `long:"owner" env:"TOGGLY_OWNER" description:"Owner id, required for the master key"`
+	Profile string `long:"profile" env:"TOGGLY_PROFILE" default:"default" description:"Profile name"`
+	Config  string `long:"config" env:"TOGGLY_CONFIG" description:"Profile file path (default: ~/.togglyctl.yaml)"`
+	Output  string `short:"o" long:"output" choice:"table" choice:"json" choice:"yaml" default:"table" description:"Output format"`
+
+	Project projectCommand `command:"project" description:"Manage projects"`
+	Env     envCommand     `command:"env" description:"Manage environments"`
+	Object  objectCommand  `command:"object" description:"Manage objects"`
+	Diff    diffCommand    `command:"diff" description:"Show parameter differences of two environments"`
+	Export  exportCommand  `command:"export" description:"Export project document"`
+	Import  importCommand  `command:"import" description:"Import project document"`
+}
+
+// Profile describes connection of the profile file
+type Profile struct {
+	URL   string `json:"url"`
+	Key   string `json:"key"`
+	Owner string `json:"owner"`
+}
+
+// Config is the profile file with named profiles
+type Config struct {
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// app is the command execution context
+type app struct {
+	opts   *Opts
+	out    io.Writer
+	client *client.Client
+}
+
+// base is embedded into commands, it keeps the execution context
+type base struct {
+	app *app
+}
+
+func (b *base) setApp(a *app) {
+	b.app = a
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes command and returns exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	opts := &Opts{}
+	a := &app{opts: opts, out: stdout}
+	parser := flags.NewParser(opts, flags.HelpFlag|flags.PassDoubleDash)
+	parser.SubcommandsOptional = true
+	parser.CommandHandler = func(cmd flags.Commander, args []string) error {
+		if opts.Version {
+			fmt.Fprintf(stdout, "togglyctl ver: %s\n", revision)
+			return nil
+		}
+		if cmd == nil {
+			return &flags.Error{Type: flags.ErrCommandRequired, Message: "Please specify command"}
+		}
+		if err := a.connect(); err != nil {
+			return err
+		}
+		cmd.(interface{ setApp(*app) }).setApp(a)
+		return cmd.Execute(args)
+	}
+	if _, err := parser.ParseArgs(args); err != nil {
+		if e, ok := err.(*flags.Error); ok && e.Type == flags.ErrHelp {
+			fmt.Fprintln(stdout, e.Message)
+			return 0
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// connect creates API client, options missing in flags and environment are taken from the profile
+func (a *app) connect() error {
+	opts := a.opts
+	if opts.URL == "" || opts.Key == "" || opts.Owner == "" {
+		profile, err := loadProfile(opts.Config, opts.Profile)
+		if err != nil {
+			return err
+		}
+		if opts.URL == "" {
+			opts.URL = profile.URL
+		}
+		if opts.Key == "" {
+			opts.Key = profile.Key
+		}
+		if opts.Owner == "" {
+			opts.Owner = profile.Owner
+		}
+	}
+	if opts.URL == "" {
+		return fmt.Errorf("API url is not specified")
+	}
+	a.client = client.New(opts.URL, opts.Key)
+	a.client.Owner = opts.Owner
+	return nil
+}
+
+// loadProfile reads profile from the file. Missing default file gives empty profile.
+func loadProfile(path, name string) (*Profile, error) {
+	explicit := path != ""
+	if !explicit {
+		home := os.Getenv("HOME")
+		if home == "" {
+			return &Profile{}, nil
+		}
+		path = filepath.Join(home, ".togglyctl.yaml")
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return &Profile{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := transfer.UnmarshalYAML(data, config); err != nil {
+		return nil, fmt.Errorf("can't read profile file %s: %v", path, err)
+	}
+	profile, ok := config.Profiles[name]
+	if !ok {
+		if explicit || name != "default" {
+			return nil, fmt.Errorf("profile `%s` is not found in %s", name, path)
+		}
+		return &Profile{}, nil
+	}
+	return profile, nil
+}
+
+// readFile decodes JSON or YAML file, `-` reads standard input
+func readFile(path string, v interface{}) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	return transfer.UnmarshalYAML(data, v)
+}
+
+// print writes value in the output format, table is built from header and rows
+func (a *app) print(v interface{}, header []string, rows [][]string) error {
+	switch a.opts.Output {
+	case "json":
+		return a.encode(v, "json")
+	case "yaml":
+		return a.encode(v, "yaml")
+	}
+	w := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// encode writes value as JSON or YAML
+func (a *app) encode(v interface{}, format string) error {
+	var data []byte
+	var err error
+	if format == "json" {
+		if data, err = json.MarshalIndent(v, "", "  "); err == nil {
+			data = append(data, '\n')
+		}
+	} else {
+		data, err = transfer.MarshalYAML(v)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = a.out.Write(data)
+	return err
+}
+
+// value formats parameter value for table output
+func value(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/internal/server/rest"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const masterKey = "TestToken"
+
+func newServer() *httptest.Server {
+	var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+	router := &rest.APIRouter{
+		Version:   "test",
+		API:       &engine.Engine{Storage: &dataStorage, DryRunStorage: memory.NewMemoryStorage},
+		BasePath:  "/api",
+		MasterKey: masterKey,
+	}
+	return httptest.NewServer(router.Router())
+}
+
+// togglyctl runs command against the server and returns exit code, standard output and error
+func togglyctl(rs *httptest.Server, args ...string) (int, string, string) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	args = append([]string{"--url", rs.URL + "/api/v1", "--key", masterKey, "--owner", "ow1"}, args...)
+	code := run(args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeFile(dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		panic(err)
+	}
+	return path
+}
+
+const baseObject = `code: base
+description: Base object
+parameters:
+- code: enabled
+  type: bool
+  value: false
+- code: title
+  type: string
+  value: Hello
+`
+
+const childObject = `code: child
+inherits:
+  project_code: p1
+  env_code: dev
+  object_code: base
+parameters:
+- code: enabled
+  type: bool
+  value: true
+`
+
+func TestCommands(t *testing.T) {
+	assert := asserts.New(t)
+	rs := newServer()
+	defer rs.Close()
+	dir, err := ioutil.TempDir("", "togglyctl")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	code, out, _ := togglyctl(rs, "project", "create", "-d", "First project", "p1")
+	assert.Equal(0, code)
+	assert.Equal("CODE  STATUS  DESCRIPTION\np1    active  First project\n", out)
+
+	code, out, _ = togglyctl(rs, "project", "update", "--status", "disabled", "p1")
+	assert.Equal(0, code)
+	assert.Contains(out, "p1    disabled  First project")
+	togglyctl(rs, "project", "update", "--status", "active", "p1")
+
+	code, out, _ = togglyctl(rs, "-o", "json", "project", "list")
+	assert.Equal(0, code)
+	var projects []map[string]interface{}
+	assert.Nil(json.Unmarshal([]byte(out), &projects))
+	assert.Len(projects, 1)
+
+	code, _, _ = togglyctl(rs, "env", "create", "-p", "p1", "dev")
+	assert.Equal(0, code)
+	code, out, _ = togglyctl(rs, "env", "create", "-p", "p1", "--protected", "prod")
+	assert.Equal(0, code)
+	assert.Contains(out, "prod  true")
+
+	code, _, _ = togglyctl(rs, "object", "create", "-p", "p1", "-e", "dev", "-f", writeFile(dir, "base.yaml", baseObject))
+	assert.Equal(0, code)
+	child := writeFile(dir, "child.yaml", childObject)
+	code, _, errOut := togglyctl(rs, "object", "create", "-p", "p1", "-e", "prod", "-f", child)
+	assert.Equal(1, code)
+	assert.Contains(errOut, "Environment is protected")
+	code, out, _ = togglyctl(rs, "object", "create", "-p", "p1", "-e", "prod", "--override", "-f", child)
+	assert.Equal(0, code)
+	assert.Contains(out, "child  dev/base  2")
+
+	code, out, _ = togglyctl(rs, "object", "get", "-p", "p1", "-e", "prod", "child")
+	assert.Equal(0, code)
+	assert.Equal("PARAMETER  TYPE    VALUE  DESCRIPTION\nenabled    bool    true   \ntitle      string  Hello  \n", out)
+
+	code, out, _ = togglyctl(rs, "object", "tree", "-p", "p1", "-e", "dev", "base")
+	assert.Equal(0, code)
+	assert.Equal("dev/base\n  prod/child\n", out)
+
+	code, out, _ = togglyctl(rs, "diff", "-p", "p1", "dev", "prod")
+	assert.Equal(0, code)
+	assert.Equal("OBJECT  PARAMETER  dev      prod\nbase    enabled    false    -\nbase    title      \"Hello\"  -\n"+
+		"child   enabled    -        true\nchild   title      -        \"Hello\"\n", out)
+
+	code, _, _ = togglyctl(rs, "env", "update", "-p", "p1", "--unprotected", "prod")
+	assert.Equal(0, code)
+	code, _, _ = togglyctl(rs, "object", "delete", "-p", "p1", "-e", "prod", "child")
+	assert.Equal(0, code)
+	code, _, errOut = togglyctl(rs, "object", "get", "-p", "p1", "-e", "prod", "child")
+	assert.Equal(1, code)
+	assert.Contains(errOut, "Object not found")
+}
+
+func TestExportImport(t *testing.T) {
+	assert := asserts.New(t)
+	source, target := newServer(), newServer()
+	defer source.Close()
+	defer target.Close()
+	dir, err := ioutil.TempDir("", "togglyctl")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	togglyctl(source, "project", "create", "p1")
+	togglyctl(source, "env", "create", "-p", "p1", "dev")
+	togglyctl(source, "env", "create", "-p", "p1", "--protected", "prod")
+	togglyctl(source, "object", "create", "-p", "p1", "-e", "dev", "-f", writeFile(dir, "base.yaml", baseObject))
+	togglyctl(source, "object", "create", "-p", "p1", "-e", "prod", "--override", "-f", writeFile(dir, "child.yaml", childObject))
+
+	doc := filepath.Join(dir, "p1.yaml")
+	code, out, _ := togglyctl(source, "export", "-p", "p1", "-f", doc)
+	assert.Equal(0, code)
+	assert.Empty(out)
+	data, err := ioutil.ReadFile(doc)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(data), "version: 1\nproject:\n  code: p1\n"))
+
+	code, _, errOut := togglyctl(target, "import", "--dry-run", "-f", doc)
+	assert.Equal(1, code)
+	assert.Contains(errOut, "Environment is protected")
+	code, out, _ = togglyctl(target, "import", "--dry-run", "--override", "-f", doc)
+	assert.Equal(0, code)
+	assert.Contains(out, "object       prod  child  create\n")
+	code, _, errOut = togglyctl(target, "project", "get", "p1")
+	assert.Equal(1, code)
+	assert.Contains(errOut, "Project not found")
+
+	code, out, _ = togglyctl(target, "import", "--override", "-f", doc)
+	assert.Equal(0, code)
+	assert.Equal("ENTITY       ENV   CODE   ACTION\nproject            p1     create\nenvironment        dev    create\n"+
+		"environment        prod   create\nobject       dev   base   create\nobject       prod  child  create\n", out)
+
+	code, out, _ = togglyctl(target, "-o", "yaml", "import", "-f", doc)
+	assert.Equal(0, code)
+	assert.NotContains(out, "create")
+	assert.Contains(out, "action: unchanged")
+
+	code, out, _ = togglyctl(target, "-o", "json", "export", "-p", "p1")
+	assert.Equal(0, code)
+	code, exported, _ := togglyctl(source, "-o", "json", "export", "-p", "p1")
+	assert.Equal(0, code)
+	assert.Equal(exported, out)
+}
+
+func TestProfile(t *testing.T) {
+	assert := asserts.New(t)
+	rs := newServer()
+	defer rs.Close()
+	dir, err := ioutil.TempDir("", "togglyctl")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	config := writeFile(dir, "config.yaml", "profiles:\n  local:\n    url: "+rs.URL+"/api/v1\n    key: "+masterKey+"\n    owner: ow1\n"+
+		"  wrong:\n    url: "+rs.URL+"/api/v1\n    key: wrong\n")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(0, run([]string{"--config", config, "--profile", "local", "project", "list"}, stdout, stderr))
+	assert.Equal("CODE  STATUS  DESCRIPTION\n", stdout.String())
+
+	os.Setenv("TOGGLY_KEY", masterKey)
+	defer os.Unsetenv("TOGGLY_KEY")
+	os.Setenv("TOGGLY_OWNER", "ow1")
+	defer os.Unsetenv("TOGGLY_OWNER")
+	stdout.Reset()
+	assert.Equal(0, run([]string{"--config", config, "--profile", "wrong", "project", "list"}, stdout, stderr), "environment overrides profile")
+
+	stderr.Reset()
+	assert.Equal(1, run([]string{"--config", config, "--profile", "none", "project", "list"}, stdout, stderr))
+	assert.Equal("Error: profile `none` is not found in "+config+"\n", stderr.String())
+
+	stdout.Reset()
+	assert.Equal(0, run([]string{"--help"}, stdout, stderr))
+	assert.Contains(stdout.String(), "Usage:")
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Toggly/core/pkg/client"
+)
+
+type objectCommand struct {
+	List   objectList   `command:"list" description:"List objects"`
+	Get    objectGet    `command:"get" description:"Show object parameters"`
+	Create objectCreate `command:"create" description:"Create object from file"`
+	Update objectUpdate `command:"update" description:"Update object from file"`
+	Delete objectDelete `command:"delete" description:"Delete object"`
+	Tree   objectTree   `command:"tree" description:"Show object inheritance tree"`
+}
+
+// envOption selects environment of the command
+type envOption struct {
+	projectOption
+	Env client.EnvironmentCode `short:"e" long:"env" required:"yes" description:"Environment code"`
+}
+
+// overrideOption allows changes of protected environment objects
+type overrideOption struct {
+	Override bool `long:"override" description:"Override environment protection"`
+}
+
+type objectArgs struct {
+	Code client.ObjectCode `positional-arg-name:"CODE" required:"yes"`
+}
+
+func (c *envOption) objects(a *app, override bool) client.ObjectAPI {
+	env := a.client.Projects().For(c.Project).Environments().For(c.Env)
+	if override {
+		env = env.OverrideProtection()
+	}
+	return env.Objects()
+}
+
+// inherits formats parent reference, project is omitted for the same project
+func inherits(project client.ProjectCode, i *client.ObjectInheritance) string {
+	if i == nil {
+		return ""
+	}
+	if i.ProjectCode == project {
+		return fmt.Sprintf("%s/%s", i.EnvCode, i.ObjectCode)
+	}
+	return fmt.Sprintf("%s/%s/%s", i.ProjectCode, i.EnvCode, i.ObjectCode)
+}
+
+func (a *app) printObjects(v interface{}, list ...*client.Object) error {
+	rows := make([][]string, len(list))
+	for i, o := range list {
+		rows[i] = []string{string(o.Code), inherits(o.ProjectCode, o.Inherits), strconv.Itoa(len(o.Parameters)), o.Description}
+	}
+	return a.print(v, []string{"CODE", "INHERITS", "PARAMETERS", "DESCRIPTION"}, rows)
+}
+
+type objectList struct {
+	base
+	envOption
+}
+
+func (c *objectList) Execute(args []string) error {
+	list, err := c.objects(c.app, false).List()
+	if err != nil {
+		return err
+	}
+	return c.app.printObjects(list, list...)
+}
+
+type objectGet struct {
+	base
+	envOption
+	Args objectArgs `positional-args:"yes"`
+}
+
+func (c *objectGet) Execute(args []string) error {
+	o, err := c.objects(c.app, false).Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(o.Parameters))
+	for i, p := range o.Parameters {
+		rows[i] = []string{string(p.Code), string(p.Type), value(p.Value), p.Description}
+	}
+	return c.app.print(o, []string{"PARAMETER", "TYPE", "VALUE", "DESCRIPTION"}, rows)
+}
+
+// fileOption reads object from JSON or YAML file
+type fileOption struct {
+	File string `short:"f" long:"file" required:"yes" description:"Object file, JSON or YAML, - reads standard input"`
+}
+
+func (c *fileOption) object() (*client.ObjectInfo, error) {
+	o := &client.DocumentObject{}
+	if err := readFile(c.File, o); err != nil {
+		return nil, err
+	}
+	return &client.ObjectInfo{Code: o.Code, Description: o.Description, Inherits: o.Inherits, Parameters: o.Parameters}, nil
+}
+
+type objectCreate struct {
+	base
+	envOption
+	overrideOption
+	fileOption
+}
+
+func (c *objectCreate) Execute(args []string) error {
+	info, err := c.object()
+	if err != nil {
+		return err
+	}
+	o, err := c.objects(c.app, c.Override).Create(info)
+	if err != nil {
+		return err
+	}
+	return c.app.printObjects(o, o)
+}
+
+type objectUpdate struct {
+	base
+	envOption
+	overrideOption
+	fileOption
+}
+
+func (c *objectUpdate) Execute(args []string) error {
+	info, err := c.object()
+	if err != nil {
+		return err
+	}
+	o, err := c.objects(c.app, c.Override).Update(info)
+	if err != nil {
+		return err
+	}
+	return c.app.printObjects(o, o)
+}
+
+type objectDelete struct {
+	base
+	envOption
+	overrideOption
+	Args objectArgs `positional-args:"yes"`
+}
+
+func (c *objectDelete) Execute(args []string) error {
+	return c.objects(c.app, c.Override).Delete(c.Args.Code, 0)
+}
+
+// treeNode is an object with its inheritors
+type treeNode struct {
+	Project    client.ProjectCode     `json:"project_code"`
+	Env        client.EnvironmentCode `json:"env_code"`
+	Code       client.ObjectCode      `json:"code"`
+	Inheritors []*treeNode            `json:"inheritors"`
+}
+
+type objectTree struct {
+	base
+	envOption
+	Args objectArgs `positional-args:"yes"`
+}
+
+func (c *objectTree) Execute(args []string) error {
+	objects := c.objects(c.app, false)
+	root, err := objects.Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	list, err := objects.InheritorsFlatList(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	children := make(map[client.ObjectInheritance][]*client.Object)
+	for _, o := range list {
+		if o.Inherits != nil {
+			children[*o.Inherits] = append(children[*o.Inherits], o)
+		}
+	}
+	var build func(o *client.Object) *treeNode
+	build = func(o *client.Object) *treeNode {
+		n := &treeNode{Project: o.ProjectCode, Env: o.EnvCode, Code: o.Code, Inheritors: make([]*treeNode, 0)}
+		for _, child := range children[client.ObjectInheritance{ProjectCode: o.ProjectCode, EnvCode: o.EnvCode, ObjectCode: o.Code}] {
+			n.Inheritors = append(n.Inheritors, build(child))
+		}
+		return n
+	}
+	tree := build(root)
+	if c.app.opts.Output != "table" {
+		return c.app.print(tree, nil, nil)
+	}
+	var write func(n *treeNode, depth int)
+	write = func(n *treeNode, depth int) {
+		fmt.Fprintf(c.app.out, "%s%s\n", strings.Repeat("  ", depth), inherits(c.Project, &client.ObjectInheritance{
+			ProjectCode: n.Project,
+			EnvCode:     n.Env,
+			ObjectCode:  n.Code,
+		}))
+		for _, child := range n.Inheritors {
+			write(child, depth+1)
+		}
+	}
+	write(tree, 0)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"github.com/Toggly/core/pkg/client"
+)
+
+type projectCommand struct {
+	List   projectList   `command:"list" description:"List projects"`
+	Get    projectGet    `command:"get" description:"Show project"`
+	Create projectCreate `command:"create" description:"Create project"`
+	Update projectUpdate `command:"update" description:"Update project"`
+	Delete projectDelete `command:"delete" description:"Delete project"`
+}
+
+type projectArgs struct {
+	Code client.ProjectCode `positional-arg-name:"CODE" required:"yes"`
+}
+
+func (a *app) printProjects(v interface{}, list ...*client.Project) error {
+	rows := make([][]string, len(list))
+	for i, p := range list {
+		rows[i] = []string{string(p.Code), string(p.Status), p.Description}
+	}
+	return a.print(v, []string{"CODE", "STATUS", "DESCRIPTION"}, rows)
+}
+
+type projectList struct {
+	base
+}
+
+func (c *projectList) Execute(args []string) error {
+	list, err := c.app.client.Projects().List()
+	if err != nil {
+		return err
+	}
+	return c.app.printProjects(list, list...)
+}
+
+type projectGet struct {
+	base
+	Args projectArgs `positional-args:"yes"`
+}
+
+func (c *projectGet) Execute(args []string) error {
+	p, err := c.app.client.Projects().Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	return c.app.printProjects(p, p)
+}
+
+type projectCreate struct {
+	base
+	Description string               `short:"d" long:"description" description:"Description"`
+	Status      client.ProjectStatus `short:"s" long:"status" choice:"active" choice:"disabled" default:"active" description:"Status"`
+	Args        projectArgs          `positional-args:"yes"`
+}
+
+func (c *projectCreate) Execute(args []string) error {
+	p, err := c.app.client.Projects().Create(&client.ProjectInfo{Code: c.Args.Code, Description: c.Description, Status: c.Status})
+	if err != nil {
+		return err
+	}
+	return c.app.printProjects(p, p)
+}
+
+type projectUpdate struct {
+	base
+	Description *string               `short:"d" long:"description" description:"Description"`
+	Status      *client.ProjectStatus `short:"s" long:"status" choice:"active" choice:"disabled" description:"Status"`
+	Args        projectArgs           `positional-args:"yes"`
+}
+
+func (c *projectUpdate) Execute(args []string) error {
+	projects := c.app.client.Projects()
+	p, err := projects.Get(c.Args.Code)
+	if err != nil {
+		return err
+	}
+	info := &client.ProjectInfo{Code: p.Code, Description: p.Description, Status: p.Status, Version: p.Version}
+	if c.Description != nil {
+		info.Description = *c.Description
+	}
+	if c.Status != nil {
+		info.Status = *c.Status
+	}
+	if p, err = projects.Update(info); err != nil {
+		return err
+	}
+	return c.app.printProjects(p, p)
+}
+
+type projectDelete struct {
+	base
+	Args projectArgs `positional-args:"yes"`
+}
+
+func (c *projectDelete) Execute(args []string) error {
+	return c.app.client.Projects().Delete(c.Args.Code)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+
+	"github.com/Toggly/core/pkg/client"
+)
+
+type exportCommand struct {
+	base
+	projectOption
+	File string `short:"f" long:"file" description:"Output file, standard output by default"`
+}
+
+func (c *exportCommand) Execute(args []string) error {
+	doc, err := c.app.client.Projects().Export(c.Project)
+	if err != nil {
+		return err
+	}
+	format := "yaml"
+	if c.app.opts.Output == "json" {
+		format = "json"
+	}
+	if c.File == "" {
+		return c.app.encode(doc, format)
+	}
+	f, err := os.Create(c.File)
+	if err != nil {
+		return err
+	}
+	out := c.app.out
+	c.app.out = f
+	err = c.app.encode(doc, format)
+	c.app.out = out
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+type importCommand struct {
+	base
+	File     string `short:"f" long:"file" required:"yes" description:"Project document, JSON or YAML, - reads standard input"`
+	DryRun   bool   `long:"dry-run" description:"Check the document and show changes without applying them"`
+	Override bool   `long:"override" description:"Override protection of environments to change their objects"`
+}
+
+func (c *importCommand) Execute(args []string) error {
+	doc := &client.Document{}
+	if err := readFile(c.File, doc); err != nil {
+		return err
+	}
+	changes, err := c.app.client.Projects().Import(doc, c.DryRun, c.Override)
+	if err != nil {
+		return err
+	}
+	return c.app.printChanges(changes)
+}
+
+func (a *app) printChanges(changes []*client.ImportChange) error {
+	rows := make([][]string, len(changes))
+	for i, ch := range changes {
+		rows[i] = []string{string(ch.Entity), string(ch.EnvCode), ch.Code, string(ch.Action)}
+	}
+	return a.print(changes, []string{"ENTITY", "ENV", "CODE", "ACTION"}, rows)
+}
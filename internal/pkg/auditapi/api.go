@@ -0,0 +1,185 @@
+package auditapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+// NewAuditAPI returns API implementation which writes every change made through it to the sink.
+// Nil sink disables audit, engine is returned as is.
+func NewAuditAPI(engine api.TogglyAPI, sink Sink) api.TogglyAPI {
+	if sink == nil {
+		return engine
+	}
+	return &auditAPI{engine: engine, sink: sink}
+}
+
+const recordIDSize = 12
+
+type recorder struct {
+	sink      Sink
+	owner     string
+	actor     string
+	requestID string
+}
+
+// record writes the change after it is applied, so failure is logged only
+func (r recorder) record(entityType domain.AuditEntity, entity string, action domain.AuditAction, before, after interface{}) {
+	if err := r.write(entityType, entity, action, before, after); err != nil {
+		log.Printf("[ERROR] Can't write audit record of %s %s: %v", action, entity, err)
+	}
+}
+
+func (r recorder) write(entityType domain.AuditEntity, entity string, action domain.AuditAction, before, after interface{}) error {
+	id := make([]byte, recordIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	rec := &domain.AuditRecord{
+		ID:         hex.EncodeToString(id),
+		Owner:      r.owner,
+		Actor:      r.actor,
+		RequestID:  r.requestID,
+		Entity:     entity,
+		EntityType: entityType,
+		Action:     action,
+		Date:       bson.Now().In(time.UTC),
+	}
+	var err error
+	if rec.Before, err = payload(before); err != nil {
+		return err
+	}
+	if rec.After, err = payload(after); err != nil {
+		return err
+	}
+	return r.sink.Write(rec)
+}
+
+// payload returns entity the way api returns it, so all sinks keep the same document.
+// Nil pointer of missing entity results in nil.
+func payload(entity interface{}) (interface{}, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func projectPath(project domain.ProjectCode) string {
+	return fmt.Sprintf("/project/%s", project)
+}
+
+func envPath(project domain.ProjectCode, env domain.EnvironmentCode) string {
+	return fmt.Sprintf("/project/%s/env/%s", project, env)
+}
+
+func segmentPath(project domain.ProjectCode, segment domain.SegmentCode) string {
+	return fmt.Sprintf("/project/%s/segment/%s", project, segment)
+}
+
+func objectPath(project domain.ProjectCode, env domain.EnvironmentCode, object domain.ObjectCode) string {
+	return fmt.Sprintf("/project/%s/env/%s/object/%s", project, env, object)
+}
+
+func webhookPath(project domain.ProjectCode, id domain.WebhookID) string {
+	return fmt.Sprintf("/project/%s/webhook/%s", project, id)
+}
+
+func keyPath(id domain.APIKeyID) string {
+	return fmt.Sprintf("/key/%s", id)
+}
+
+type auditAPI struct {
+	engine    api.TogglyAPI
+	sink      Sink
+	actor     string
+	requestID string
+}
+
+func (a *auditAPI) ForOwner(owner string) api.OwnerAPI {
+	return &auditOwnerAPI{
+		recorder: recorder{sink: a.sink, owner: owner, actor: a.actor, requestID: a.requestID},
+		engine:   a.engine.ForOwner(owner),
+	}
+}
+
+func (a *auditAPI) WithActor(actor string) api.TogglyAPI {
+	return &auditAPI{engine: a.engine.WithActor(actor), sink: a.sink, actor: actor, requestID: a.requestID}
+}
+
+func (a *auditAPI) WithRequestID(requestID string) api.TogglyAPI {
+	return &auditAPI{engine: a.engine.WithRequestID(requestID), sink: a.sink, actor: a.actor, requestID: requestID}
+}
+
+func (a *auditAPI) Authenticate(key string) (*domain.APIKey, error) {
+	return a.engine.Authenticate(key)
+}
+
+type auditOwnerAPI struct {
+	recorder
+	engine api.OwnerAPI
+}
+
+func (a *auditOwnerAPI) Projects() api.ProjectAPI {
+	return &auditProjectAPI{
+		recorder: a.recorder,
+		engine:   a.engine.Projects(),
+	}
+}
+
+func (a *auditOwnerAPI) Keys() api.KeyAPI {
+	return &auditKeyAPI{
+		recorder: a.recorder,
+		engine:   a.engine.Keys(),
+	}
+}
+
+func (a *auditOwnerAPI) Audit() api.AuditAPI {
+	return a.engine.Audit()
+}
+
+type auditKeyAPI struct {
+	recorder
+	engine api.KeyAPI
+}
+
+func (a *auditKeyAPI) List() ([]*domain.APIKey, error) {
+	return a.engine.List()
+}
+
+func (a *auditKeyAPI) Get(id domain.APIKeyID) (*domain.APIKey, error) {
+	return a.engine.Get(id)
+}
+
+// Create records the key without its value
+func (a *auditKeyAPI) Create(info *api.KeyInfo) (*domain.APIKey, error) {
+	key, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	recorded := *key
+	recorded.Key = ""
+	a.record(domain.AuditKey, keyPath(key.ID), domain.AuditCreate, nil, &recorded)
+	return key, nil
+}
+
+func (a *auditKeyAPI) Revoke(id domain.APIKeyID) error {
+	before, _ := a.engine.Get(id)
+	if err := a.engine.Revoke(id); err != nil {
+		return err
+	}
+	a.record(domain.AuditKey, keyPath(id), domain.AuditDelete, before, nil)
+	return nil
+}
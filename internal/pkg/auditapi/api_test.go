@@ -0,0 +1,218 @@
+package auditapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/auditapi"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const ow = "test_owner"
+
+var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+
+func DropDB() {
+	dataStorage = memory.NewMemoryStorage()
+}
+
+func BeforeTest() {
+	DropDB()
+}
+
+func AfterTest() {
+	DropDB()
+}
+
+// payload returns stored payload as JSON object
+func payload(v interface{}) map[string]interface{} {
+	data, _ := json.Marshal(v)
+	var res map[string]interface{}
+	json.Unmarshal(data, &res)
+	return res
+}
+
+func TestNilSink(t *testing.T) {
+	assert := asserts.New(t)
+	e := engine.NewTogglyAPI(&dataStorage)
+	assert.Equal(e, auditapi.NewAuditAPI(e, nil))
+}
+
+func TestAuditRecords(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	e := auditapi.NewAuditAPI(engine.NewTogglyAPI(&dataStorage), auditapi.NewStorageSink(&dataStorage))
+	owner := e.WithActor("key1").WithRequestID("req-1").ForOwner(ow)
+
+	pApi := owner.Projects()
+	_, err := pApi.Create(&api.ProjectInfo{Code: "p1", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	envApi := pApi.For("p1").Environments()
+	envApi.Create(&api.EnvironmentInfo{Code: "dev", Protected: true})
+	pApi.For("p1").Segments().Create(&api.SegmentInfo{Code: "beta", Include: []string{"user1"}})
+	objApi := envApi.For("dev").OverrideProtection().Objects()
+	objApi.Create(&api.ObjectInfo{Code: "obj1", Description: "first"})
+	objApi.Update(&api.ObjectInfo{Code: "obj1", Description: "second"})
+	objApi.Revert("obj1", 1)
+	assert.Nil(objApi.Delete("obj1", 0))
+	key, err := owner.Keys().Create(&api.KeyInfo{Role: domain.RoleAdmin})
+	assert.Nil(err)
+	assert.Nil(owner.Keys().Revoke(key.ID))
+	hooks := pApi.For("p1").Webhooks()
+	hook, err := hooks.Create(&api.WebhookInfo{URL: "https://example.com/hook"})
+	assert.Nil(err)
+	_, err = hooks.Update(&api.WebhookInfo{ID: hook.ID, URL: "https://example.com/new"})
+	assert.Nil(err)
+	assert.Nil(hooks.Delete(hook.ID))
+
+	// failed changes are not recorded
+	_, err = pApi.Create(&api.ProjectInfo{Code: "p1", Status: domain.ProjectStatusActive})
+	assert.NotNil(err)
+	assert.Equal(api.ErrEnvironmentProtected, envApi.For("dev").Objects().Delete("obj1", 0))
+
+	list, err := owner.Audit().List(&domain.AuditFilter{})
+	assert.Nil(err)
+	type entry struct {
+		entity string
+		action domain.AuditAction
+	}
+	entries := make([]entry, 0)
+	for _, rec := range list {
+		entries = append(entries, entry{rec.Entity, rec.Action})
+		assert.Equal(ow, rec.Owner)
+		assert.Equal("key1", rec.Actor)
+		assert.Equal("req-1", rec.RequestID)
+		assert.NotEmpty(rec.ID)
+		assert.False(rec.Date.IsZero())
+	}
+	assert.Equal([]entry{
+		{"/project/p1", domain.AuditCreate},
+		{"/project/p1/env/dev", domain.AuditCreate},
+		{"/project/p1/segment/beta", domain.AuditCreate},
+		{"/project/p1/env/dev/object/obj1", domain.AuditCreate},
+		{"/project/p1/env/dev/object/obj1", domain.AuditUpdate},
+		{"/project/p1/env/dev/object/obj1", domain.AuditRevert},
+		{"/project/p1/env/dev/object/obj1", domain.AuditDelete},
+		{"/key/" + string(key.ID), domain.AuditCreate},
+		{"/key/" + string(key.ID), domain.AuditDelete},
+		{"/project/p1/webhook/" + string(hook.ID), domain.AuditCreate},
+		{"/project/p1/webhook/" + string(hook.ID), domain.AuditUpdate},
+		{"/project/p1/webhook/" + string(hook.ID), domain.AuditDelete},
+	}, entries)
+
+	assert.Equal(domain.AuditObject, list[4].EntityType)
+	assert.Equal("first", payload(list[4].Before)["description"])
+	assert.Equal("second", payload(list[4].After)["description"])
+	assert.Nil(list[6].After)
+	assert.Equal("first", payload(list[6].Before)["description"])
+	assert.Nil(list[7].Before)
+	assert.Equal(string(key.ID), payload(list[7].After)["id"])
+	assert.NotContains(payload(list[7].After), "key")
+	assert.NotContains(payload(list[7].After), "hash")
+	assert.Equal(domain.AuditWebhook, list[9].EntityType)
+	assert.Equal("https://example.com/hook", payload(list[9].After)["url"])
+	assert.NotContains(payload(list[9].After), "secret")
+	assert.Equal("https://example.com/hook", payload(list[10].Before)["url"])
+	assert.Equal("https://example.com/new", payload(list[10].After)["url"])
+
+	AfterTest()
+}
+
+func TestWriterSink(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	var buf bytes.Buffer
+	e := auditapi.NewAuditAPI(engine.NewTogglyAPI(&dataStorage), auditapi.MultiSink(auditapi.NewWriterSink(&buf)))
+	pApi := e.WithActor("master").ForOwner(ow).Projects()
+	pApi.Create(&api.ProjectInfo{Code: "p1", Status: domain.ProjectStatusActive})
+	pApi.Update(&api.ProjectInfo{Code: "p1", Description: "changed", Status: domain.ProjectStatusActive})
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(lines, 2)
+	var rec map[string]interface{}
+	assert.Nil(json.Unmarshal(lines[1], &rec))
+	assert.Equal("update", rec["action"])
+	assert.Equal("master", rec["actor"])
+	assert.Equal("/project/p1", rec["entity"])
+	assert.Equal("changed", rec["after"].(map[string]interface{})["description"])
+
+	list, err := e.ForOwner(ow).Audit().List(&domain.AuditFilter{})
+	assert.Nil(err)
+	assert.Empty(list)
+
+	AfterTest()
+}
+
+func TestFileSink(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	dir, err := ioutil.TempDir("", "toggly-audit")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	for _, code := range []domain.ProjectCode{"p1", "p2"} {
+		sink, err := auditapi.NewFileSink(path)
+		assert.Nil(err)
+		e := auditapi.NewAuditAPI(engine.NewTogglyAPI(&dataStorage), sink)
+		e.ForOwner(ow).Projects().Create(&api.ProjectInfo{Code: code, Status: domain.ProjectStatusActive})
+		assert.Nil(sink.Close())
+	}
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	assert.Len(lines, 2)
+	var rec domain.AuditRecord
+	assert.Nil(json.Unmarshal(lines[1], &rec))
+	assert.Equal("/project/p2", rec.Entity)
+
+	AfterTest()
+}
+
+func TestImportRecords(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	e := auditapi.NewAuditAPI(&engine.Engine{Storage: &dataStorage, DryRunStorage: memory.NewMemoryStorage}, auditapi.NewStorageSink(&dataStorage))
+	owner := e.ForOwner(ow)
+	doc := &domain.Document{
+		Version: domain.DocumentVersion,
+		Project: &domain.DocumentProject{Code: "p1", Status: domain.ProjectStatusActive},
+		Environments: []*domain.DocumentEnvironment{{
+			Code:    "dev",
+			Objects: []*domain.DocumentObject{{Code: "obj1"}},
+		}},
+	}
+
+	_, err := owner.Projects().Import(doc, true, true)
+	assert.Nil(err)
+	list, err := owner.Audit().List(&domain.AuditFilter{})
+	assert.Nil(err)
+	assert.Len(list, 0, "dry run is not recorded")
+
+	_, err = owner.Projects().Import(doc, false, true)
+	assert.Nil(err)
+	list, err = owner.Audit().List(&domain.AuditFilter{})
+	assert.Nil(err)
+	entities := make([]string, 0)
+	for _, rec := range list {
+		assert.Equal(domain.AuditCreate, rec.Action)
+		entities = append(entities, rec.Entity)
+	}
+	assert.Equal([]string{"/project/p1", "/project/p1/env/dev", "/project/p1/env/dev/object/obj1"}, entities)
+
+	AfterTest()
+}
@@ -0,0 +1,57 @@
+package auditapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type auditEnvAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	engine      api.EnvironmentAPI
+}
+
+func (a *auditEnvAPI) List() ([]*domain.Environment, error) {
+	return a.engine.List()
+}
+
+func (a *auditEnvAPI) Get(code domain.EnvironmentCode) (*domain.Environment, error) {
+	return a.engine.Get(code)
+}
+
+func (a *auditEnvAPI) Create(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	env, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditEnvironment, envPath(a.projectCode, env.Code), domain.AuditCreate, nil, env)
+	return env, nil
+}
+
+func (a *auditEnvAPI) Update(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	before, _ := a.engine.Get(info.Code)
+	env, err := a.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditEnvironment, envPath(a.projectCode, env.Code), domain.AuditUpdate, before, env)
+	return env, nil
+}
+
+func (a *auditEnvAPI) Delete(code domain.EnvironmentCode) error {
+	before, _ := a.engine.Get(code)
+	if err := a.engine.Delete(code); err != nil {
+		return err
+	}
+	a.record(domain.AuditEnvironment, envPath(a.projectCode, code), domain.AuditDelete, before, nil)
+	return nil
+}
+
+func (a *auditEnvAPI) For(code domain.EnvironmentCode) api.ForObjectAPI {
+	return &auditForObjectAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		envCode:     code,
+		engine:      a.engine.For(code),
+	}
+}
@@ -0,0 +1,104 @@
+package auditapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type auditForObjectAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	engine      api.ForObjectAPI
+}
+
+func (a *auditForObjectAPI) Objects() api.ObjectAPI {
+	return &auditObjectAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		envCode:     a.envCode,
+		engine:      a.engine.Objects(),
+	}
+}
+
+func (a *auditForObjectAPI) OverrideProtection() api.ForObjectAPI {
+	return &auditForObjectAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		envCode:     a.envCode,
+		engine:      a.engine.OverrideProtection(),
+	}
+}
+
+type auditObjectAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	engine      api.ObjectAPI
+}
+
+func (a *auditObjectAPI) path(code domain.ObjectCode) string {
+	return objectPath(a.projectCode, a.envCode, code)
+}
+
+func (a *auditObjectAPI) List() ([]*domain.Object, error) {
+	return a.engine.List()
+}
+
+func (a *auditObjectAPI) Get(code domain.ObjectCode) (*domain.Object, error) {
+	return a.engine.Get(code)
+}
+
+func (a *auditObjectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
+	obj, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditObject, a.path(obj.Code), domain.AuditCreate, nil, obj)
+	return obj, nil
+}
+
+func (a *auditObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
+	before, _ := a.engine.Get(info.Code)
+	obj, err := a.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditObject, a.path(obj.Code), domain.AuditUpdate, before, obj)
+	return obj, nil
+}
+
+func (a *auditObjectAPI) Delete(code domain.ObjectCode, version int64) error {
+	before, _ := a.engine.Get(code)
+	if err := a.engine.Delete(code, version); err != nil {
+		return err
+	}
+	a.record(domain.AuditObject, a.path(code), domain.AuditDelete, before, nil)
+	return nil
+}
+
+func (a *auditObjectAPI) Revert(code domain.ObjectCode, revision int64) (*domain.Object, error) {
+	before, _ := a.engine.Get(code)
+	obj, err := a.engine.Revert(code, revision)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditObject, a.path(code), domain.AuditRevert, before, obj)
+	return obj, nil
+}
+
+func (a *auditObjectAPI) InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error) {
+	return a.engine.InheritorsFlatList(code)
+}
+
+func (a *auditObjectAPI) History(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	return a.engine.History(code)
+}
+
+func (a *auditObjectAPI) Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error) {
+	return a.engine.Evaluate(code, ctx)
+}
+
+func (a *auditObjectAPI) EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	return a.engine.EvaluateAll(ctx)
+}
@@ -0,0 +1,98 @@
+package auditapi
+
+import (
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type auditProjectAPI struct {
+	recorder
+	engine api.ProjectAPI
+}
+
+func (a *auditProjectAPI) List() ([]*domain.Project, error) {
+	return a.engine.List()
+}
+
+func (a *auditProjectAPI) Get(code domain.ProjectCode) (*domain.Project, error) {
+	return a.engine.Get(code)
+}
+
+func (a *auditProjectAPI) Create(info *api.ProjectInfo) (*domain.Project, error) {
+	proj, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditProject, projectPath(proj.Code), domain.AuditCreate, nil, proj)
+	return proj, nil
+}
+
+func (a *auditProjectAPI) Update(info *api.ProjectInfo) (*domain.Project, error) {
+	before, _ := a.engine.Get(info.Code)
+	proj, err := a.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditProject, projectPath(proj.Code), domain.AuditUpdate, before, proj)
+	return proj, nil
+}
+
+func (a *auditProjectAPI) Delete(code domain.ProjectCode) error {
+	before, _ := a.engine.Get(code)
+	if err := a.engine.Delete(code); err != nil {
+		return err
+	}
+	a.record(domain.AuditProject, projectPath(code), domain.AuditDelete, before, nil)
+	return nil
+}
+
+func (a *auditProjectAPI) Export(code domain.ProjectCode) (*domain.Document, error) {
+	return a.engine.Export(code)
+}
+
+// Import saves entities through the audit api, so every change is recorded
+func (a *auditProjectAPI) Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error) {
+	if dryRun {
+		return a.engine.Import(doc, true, override)
+	}
+	return transfer.Import(a, doc, override)
+}
+
+func (a *auditProjectAPI) For(code domain.ProjectCode) api.ForProjectAPI {
+	return &auditForProjectAPI{
+		recorder:    a.recorder,
+		projectCode: code,
+		engine:      a.engine.For(code),
+	}
+}
+
+type auditForProjectAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	engine      api.ForProjectAPI
+}
+
+func (a *auditForProjectAPI) Environments() api.EnvironmentAPI {
+	return &auditEnvAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		engine:      a.engine.Environments(),
+	}
+}
+
+func (a *auditForProjectAPI) Segments() api.SegmentAPI {
+	return &auditSegmentAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		engine:      a.engine.Segments(),
+	}
+}
+
+func (a *auditForProjectAPI) Webhooks() api.WebhookAPI {
+	return &auditWebhookAPI{
+		recorder:    a.recorder,
+		projectCode: a.projectCode,
+		engine:      a.engine.Webhooks(),
+	}
+}
@@ -0,0 +1,48 @@
+package auditapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type auditSegmentAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	engine      api.SegmentAPI
+}
+
+func (a *auditSegmentAPI) List() ([]*domain.Segment, error) {
+	return a.engine.List()
+}
+
+func (a *auditSegmentAPI) Get(code domain.SegmentCode) (*domain.Segment, error) {
+	return a.engine.Get(code)
+}
+
+func (a *auditSegmentAPI) Create(info *api.SegmentInfo) (*domain.Segment, error) {
+	seg, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditSegment, segmentPath(a.projectCode, seg.Code), domain.AuditCreate, nil, seg)
+	return seg, nil
+}
+
+func (a *auditSegmentAPI) Update(info *api.SegmentInfo) (*domain.Segment, error) {
+	before, _ := a.engine.Get(info.Code)
+	seg, err := a.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditSegment, segmentPath(a.projectCode, seg.Code), domain.AuditUpdate, before, seg)
+	return seg, nil
+}
+
+func (a *auditSegmentAPI) Delete(code domain.SegmentCode) error {
+	before, _ := a.engine.Get(code)
+	if err := a.engine.Delete(code); err != nil {
+		return err
+	}
+	a.record(domain.AuditSegment, segmentPath(a.projectCode, code), domain.AuditDelete, before, nil)
+	return nil
+}
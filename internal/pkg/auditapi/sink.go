@@ -0,0 +1,87 @@
+package auditapi
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// Sink writes audit records
+type Sink interface {
+	Write(rec *domain.AuditRecord) error
+}
+
+// NewStorageSink returns sink saving records to the owner audit log, records can be queried by api
+func NewStorageSink(storage *storage.DataStorage) Sink {
+	return &storageSink{storage: storage}
+}
+
+type storageSink struct {
+	storage *storage.DataStorage
+}
+
+func (s *storageSink) Write(rec *domain.AuditRecord) error {
+	return (*s.storage).ForOwner(rec.Owner).Audit().Save(rec)
+}
+
+// NewWriterSink returns sink writing records to w as JSON lines
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+type writerSink struct {
+	sync.Mutex
+	w io.Writer
+}
+
+func (s *writerSink) Write(rec *domain.AuditRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// FileSink appends records to the file as JSON lines
+type FileSink struct {
+	writerSink
+	file *os.File
+}
+
+// NewFileSink opens the file for appending, it is created if missing
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{writerSink: writerSink{w: file}, file: file}, nil
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// MultiSink returns sink writing records to all sinks, the first error is returned
+func MultiSink(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
+
+type multiSink []Sink
+
+func (m multiSink) Write(rec *domain.AuditRecord) error {
+	var first error
+	for _, s := range m {
+		if err := s.Write(rec); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
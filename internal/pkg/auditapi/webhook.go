@@ -0,0 +1,58 @@
+package auditapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type auditWebhookAPI struct {
+	recorder
+	projectCode domain.ProjectCode
+	engine      api.WebhookAPI
+}
+
+func (a *auditWebhookAPI) List() ([]*domain.Webhook, error) {
+	return a.engine.List()
+}
+
+func (a *auditWebhookAPI) Get(id domain.WebhookID) (*domain.Webhook, error) {
+	return a.engine.Get(id)
+}
+
+func (a *auditWebhookAPI) Create(info *api.WebhookInfo) (*domain.Webhook, error) {
+	hook, err := a.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	after := *hook
+	after.Secret = ""
+	a.record(domain.AuditWebhook, webhookPath(a.projectCode, hook.ID), domain.AuditCreate, nil, &after)
+	return hook, nil
+}
+
+func (a *auditWebhookAPI) Update(info *api.WebhookInfo) (*domain.Webhook, error) {
+	before, _ := a.engine.Get(info.ID)
+	hook, err := a.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	a.record(domain.AuditWebhook, webhookPath(a.projectCode, hook.ID), domain.AuditUpdate, before, hook)
+	return hook, nil
+}
+
+func (a *auditWebhookAPI) Delete(id domain.WebhookID) error {
+	before, _ := a.engine.Get(id)
+	if err := a.engine.Delete(id); err != nil {
+		return err
+	}
+	a.record(domain.AuditWebhook, webhookPath(a.projectCode, id), domain.AuditDelete, before, nil)
+	return nil
+}
+
+func (a *auditWebhookAPI) Deliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	return a.engine.Deliveries(id)
+}
+
+func (a *auditWebhookAPI) Test(id domain.WebhookID) (*domain.WebhookDelivery, error) {
+	return a.engine.Test(id)
+}
@@ -0,0 +1,124 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// NewAuthorizedAPI returns API implementation which checks api key permissions.
+// Nil key stands for the master key which has full access, engine is returned as is.
+func NewAuthorizedAPI(engine api.TogglyAPI, key *domain.APIKey) api.TogglyAPI {
+	if key == nil {
+		return engine
+	}
+	return &authzAPI{engine: engine, key: key}
+}
+
+type authorizer struct {
+	owner string
+	key   *domain.APIKey
+}
+
+func (a authorizer) check(p domain.Permission, project domain.ProjectCode, env domain.EnvironmentCode) error {
+	if a.owner != a.key.OwnerID || !a.key.Allows(p, project, env) {
+		return api.ErrForbidden
+	}
+	return nil
+}
+
+type authzAPI struct {
+	engine api.TogglyAPI
+	key    *domain.APIKey
+}
+
+func (a *authzAPI) ForOwner(owner string) api.OwnerAPI {
+	return &authzOwnerAPI{
+		authorizer: authorizer{owner: owner, key: a.key},
+		engine:     a.engine.ForOwner(owner),
+	}
+}
+
+func (a *authzAPI) WithActor(actor string) api.TogglyAPI {
+	return &authzAPI{engine: a.engine.WithActor(actor), key: a.key}
+}
+
+func (a *authzAPI) WithRequestID(requestID string) api.TogglyAPI {
+	return &authzAPI{engine: a.engine.WithRequestID(requestID), key: a.key}
+}
+
+func (a *authzAPI) Authenticate(key string) (*domain.APIKey, error) {
+	return a.engine.Authenticate(key)
+}
+
+type authzOwnerAPI struct {
+	authorizer
+	engine api.OwnerAPI
+}
+
+func (a *authzOwnerAPI) Projects() api.ProjectAPI {
+	return &authzProjectAPI{
+		authorizer: a.authorizer,
+		engine:     a.engine.Projects(),
+	}
+}
+
+func (a *authzOwnerAPI) Keys() api.KeyAPI {
+	return &authzKeyAPI{
+		authorizer: a.authorizer,
+		engine:     a.engine.Keys(),
+	}
+}
+
+func (a *authzOwnerAPI) Audit() api.AuditAPI {
+	return &authzAuditAPI{
+		authorizer: a.authorizer,
+		engine:     a.engine.Audit(),
+	}
+}
+
+// authzAuditAPI allows owner level admin keys only to read audit log
+type authzAuditAPI struct {
+	authorizer
+	engine api.AuditAPI
+}
+
+func (a *authzAuditAPI) List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	if err := a.check(domain.PermissionAdmin, "", ""); err != nil {
+		return nil, err
+	}
+	return a.engine.List(filter)
+}
+
+// authzKeyAPI allows owner level admin keys only to manage api keys
+type authzKeyAPI struct {
+	authorizer
+	engine api.KeyAPI
+}
+
+func (a *authzKeyAPI) List() ([]*domain.APIKey, error) {
+	if err := a.check(domain.PermissionAdmin, "", ""); err != nil {
+		return nil, err
+	}
+	return a.engine.List()
+}
+
+func (a *authzKeyAPI) Get(id domain.APIKeyID) (*domain.APIKey, error) {
+	if err := a.check(domain.PermissionAdmin, "", ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(id)
+}
+
+func (a *authzKeyAPI) Create(info *api.KeyInfo) (*domain.APIKey, error) {
+	if err := a.check(domain.PermissionAdmin, "", ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzKeyAPI) Revoke(id domain.APIKeyID) error {
+	if err := a.check(domain.PermissionAdmin, "", ""); err != nil {
+		return err
+	}
+	return a.engine.Revoke(id)
+}
@@ -0,0 +1,55 @@
+package authzapi_test
+
+import (
+	"github.com/Toggly/core/internal/pkg/authzapi"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+const ow = "test_owner"
+
+var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+
+func getEngine() api.TogglyAPI {
+	return &engine.Engine{Storage: &dataStorage, DryRunStorage: memory.NewMemoryStorage}
+}
+
+func forKey(e api.TogglyAPI, role domain.Role, project domain.ProjectCode, env domain.EnvironmentCode) api.OwnerAPI {
+	key := &domain.APIKey{
+		OwnerID:         ow,
+		Role:            role,
+		ProjectCode:     project,
+		EnvironmentCode: env,
+	}
+	return authzapi.NewAuthorizedAPI(e, key).ForOwner(ow)
+}
+
+// prepare creates projects p1 and p2, environments dev and prod in p1 with object obj1 in each
+func prepare(e api.TogglyAPI) {
+	pApi := e.ForOwner(ow).Projects()
+	pApi.Create(&api.ProjectInfo{Code: "p1", Status: domain.ProjectStatusActive})
+	pApi.Create(&api.ProjectInfo{Code: "p2", Status: domain.ProjectStatusActive})
+	envApi := pApi.For("p1").Environments()
+	for _, env := range []domain.EnvironmentCode{"dev", "prod"} {
+		envApi.Create(&api.EnvironmentInfo{Code: env, Protected: env == "prod"})
+		envApi.For(env).OverrideProtection().Objects().Create(&api.ObjectInfo{
+			Code:       "obj1",
+			Parameters: []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: true}},
+		})
+	}
+}
+
+func DropDB() {
+	dataStorage = memory.NewMemoryStorage()
+}
+
+func BeforeTest() {
+	DropDB()
+}
+
+func AfterTest() {
+	DropDB()
+}
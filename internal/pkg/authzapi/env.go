@@ -0,0 +1,67 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type authzEnvAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	engine      api.EnvironmentAPI
+}
+
+// List returns project environments the key has access to
+func (a *authzEnvAPI) List() ([]*domain.Environment, error) {
+	if err := a.check(domain.PermissionRead, a.projectCode, ""); err != nil {
+		return nil, err
+	}
+	list, err := a.engine.List()
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]*domain.Environment, 0)
+	for _, env := range list {
+		if a.check(domain.PermissionRead, a.projectCode, env.Code) == nil {
+			allowed = append(allowed, env)
+		}
+	}
+	return allowed, nil
+}
+
+func (a *authzEnvAPI) Get(code domain.EnvironmentCode) (*domain.Environment, error) {
+	if err := a.check(domain.PermissionRead, a.projectCode, code); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(code)
+}
+
+func (a *authzEnvAPI) Create(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	if err := a.check(domain.PermissionAdmin, a.projectCode, info.Code); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzEnvAPI) Update(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	if err := a.check(domain.PermissionAdmin, a.projectCode, info.Code); err != nil {
+		return nil, err
+	}
+	return a.engine.Update(info)
+}
+
+func (a *authzEnvAPI) Delete(code domain.EnvironmentCode) error {
+	if err := a.check(domain.PermissionAdmin, a.projectCode, code); err != nil {
+		return err
+	}
+	return a.engine.Delete(code)
+}
+
+func (a *authzEnvAPI) For(code domain.EnvironmentCode) api.ForObjectAPI {
+	return &authzForObjectAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		envCode:     code,
+		engine:      a.engine.For(code),
+	}
+}
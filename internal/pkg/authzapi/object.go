@@ -0,0 +1,161 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type authzForObjectAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	override    bool
+	engine      api.ForObjectAPI
+}
+
+func (a *authzForObjectAPI) Objects() api.ObjectAPI {
+	return &authzObjectAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		envCode:     a.envCode,
+		override:    a.override,
+		engine:      a.engine.Objects(),
+	}
+}
+
+func (a *authzForObjectAPI) OverrideProtection() api.ForObjectAPI {
+	return &authzForObjectAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		envCode:     a.envCode,
+		override:    true,
+		engine:      a.engine.OverrideProtection(),
+	}
+}
+
+type authzObjectAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	override    bool
+	engine      api.ObjectAPI
+}
+
+func (a *authzObjectAPI) read() error {
+	return a.check(domain.PermissionRead, a.projectCode, a.envCode)
+}
+
+// write requires admin permission to override environment protection
+func (a *authzObjectAPI) write() error {
+	p := domain.PermissionWrite
+	if a.override {
+		p = domain.PermissionAdmin
+	}
+	return a.check(p, a.projectCode, a.envCode)
+}
+
+// parent requires read permission on the environment of the inherited object
+func (a *authzObjectAPI) parent(inherits *domain.ObjectInheritance) error {
+	if inherits == nil {
+		return nil
+	}
+	return a.check(domain.PermissionRead, inherits.ProjectCode, inherits.EnvCode)
+}
+
+func (a *authzObjectAPI) List() ([]*domain.Object, error) {
+	if err := a.read(); err != nil {
+		return nil, err
+	}
+	return a.engine.List()
+}
+
+func (a *authzObjectAPI) Get(code domain.ObjectCode) (*domain.Object, error) {
+	if err := a.read(); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(code)
+}
+
+func (a *authzObjectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
+	if err := a.write(); err != nil {
+		return nil, err
+	}
+	if err := a.parent(info.Inherits); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
+	if err := a.write(); err != nil {
+		return nil, err
+	}
+	if err := a.parent(info.Inherits); err != nil {
+		return nil, err
+	}
+	return a.engine.Update(info)
+}
+
+func (a *authzObjectAPI) Delete(code domain.ObjectCode, version int64) error {
+	if err := a.write(); err != nil {
+		return err
+	}
+	return a.engine.Delete(code, version)
+}
+
+func (a *authzObjectAPI) History(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	if err := a.read(); err != nil {
+		return nil, err
+	}
+	return a.engine.History(code)
+}
+
+// Revert requires read permission on the parent the object inherited in the revision
+func (a *authzObjectAPI) Revert(code domain.ObjectCode, revision int64) (*domain.Object, error) {
+	if err := a.write(); err != nil {
+		return nil, err
+	}
+	// missing object or revision is reported by the engine
+	history, _ := a.engine.History(code)
+	for _, rev := range history {
+		if rev.Revision != revision || rev.Object == nil {
+			continue
+		}
+		if err := a.parent(rev.Object.Inherits); err != nil {
+			return nil, err
+		}
+	}
+	return a.engine.Revert(code, revision)
+}
+
+// InheritorsFlatList returns inheritors the key has access to, inheritors can belong to other environments
+func (a *authzObjectAPI) InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error) {
+	if err := a.read(); err != nil {
+		return nil, err
+	}
+	list, err := a.engine.InheritorsFlatList(code)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]*domain.Object, 0)
+	for _, obj := range list {
+		if a.check(domain.PermissionRead, obj.ProjectCode, obj.EnvCode) == nil {
+			allowed = append(allowed, obj)
+		}
+	}
+	return allowed, nil
+}
+
+func (a *authzObjectAPI) Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error) {
+	if err := a.check(domain.PermissionEvaluate, a.projectCode, a.envCode); err != nil {
+		return nil, err
+	}
+	return a.engine.Evaluate(code, ctx)
+}
+
+func (a *authzObjectAPI) EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	if err := a.check(domain.PermissionEvaluate, a.projectCode, a.envCode); err != nil {
+		return nil, err
+	}
+	return a.engine.EvaluateAll(ctx)
+}
@@ -0,0 +1,145 @@
+package authzapi_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+var objInfo = &api.ObjectInfo{
+	Code:       "obj1",
+	Parameters: []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: false}},
+}
+
+func TestEnvironmentPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	envApi := forKey(e, domain.RoleEditor, "p1", "dev").Projects().For("p1").Environments()
+	list, err := envApi.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+	assert.Equal(domain.EnvironmentCode("dev"), list[0].Code)
+	_, err = envApi.Get("prod")
+	assert.Equal(api.ErrForbidden, err)
+	_, err = envApi.Create(&api.EnvironmentInfo{Code: "qa"})
+	assert.Equal(api.ErrForbidden, err)
+	_, err = envApi.Update(&api.EnvironmentInfo{Code: "dev"})
+	assert.Equal(api.ErrForbidden, err)
+
+	adminEnvApi := forKey(e, domain.RoleAdmin, "p1", "").Projects().For("p1").Environments()
+	_, err = adminEnvApi.Create(&api.EnvironmentInfo{Code: "qa"})
+	assert.Nil(err)
+	assert.Nil(adminEnvApi.Delete("qa"))
+
+	AfterTest()
+}
+
+func TestSegmentPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	segApi := forKey(e, domain.RoleEditor, "p1", "dev").Projects().For("p1").Segments()
+	_, err := segApi.List()
+	assert.Nil(err)
+	_, err = segApi.Create(&api.SegmentInfo{Code: "beta"})
+	assert.Equal(api.ErrForbidden, err)
+
+	segApi = forKey(e, domain.RoleEditor, "p1", "").Projects().For("p1").Segments()
+	_, err = segApi.Create(&api.SegmentInfo{Code: "beta"})
+	assert.Nil(err)
+	assert.Nil(segApi.Delete("beta"))
+
+	AfterTest()
+}
+
+func TestObjectPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	objects := func(role domain.Role, env domain.EnvironmentCode) api.ObjectAPI {
+		return forKey(e, role, "p1", env).Projects().For("p1").Environments().For("dev").Objects()
+	}
+	ctx := &domain.EvaluationContext{UserID: "u1"}
+
+	sdk := objects(domain.RoleEvaluate, "dev")
+	_, err := sdk.Evaluate("obj1", ctx)
+	assert.Nil(err)
+	_, err = sdk.EvaluateAll(ctx)
+	assert.Nil(err)
+	_, err = sdk.Get("obj1")
+	assert.Equal(api.ErrForbidden, err)
+	_, err = sdk.List()
+	assert.Equal(api.ErrForbidden, err)
+
+	readOnly := objects(domain.RoleReadOnly, "")
+	_, err = readOnly.Get("obj1")
+	assert.Nil(err)
+	_, err = readOnly.Evaluate("obj1", ctx)
+	assert.Nil(err)
+	_, err = readOnly.Update(objInfo)
+	assert.Equal(api.ErrForbidden, err)
+	assert.Equal(api.ErrForbidden, readOnly.Delete("obj1", 0))
+
+	_, err = objects(domain.RoleEditor, "dev").Update(objInfo)
+	assert.Nil(err)
+
+	_, err = objects(domain.RoleEditor, "prod").Get("obj1")
+	assert.Equal(api.ErrForbidden, err)
+	_, err = objects(domain.RoleEvaluate, "prod").Evaluate("obj1", ctx)
+	assert.Equal(api.ErrForbidden, err)
+
+	prodEditor := forKey(e, domain.RoleEditor, "p1", "prod").Projects().For("p1").Environments().For("prod")
+	_, err = prodEditor.Objects().Update(objInfo)
+	assert.Equal(api.ErrEnvironmentProtected, err)
+	_, err = prodEditor.OverrideProtection().Objects().Update(objInfo)
+	assert.Equal(api.ErrForbidden, err)
+
+	prodAdmin := forKey(e, domain.RoleAdmin, "p1", "prod").Projects().For("p1").Environments().For("prod")
+	_, err = prodAdmin.OverrideProtection().Objects().Update(objInfo)
+	assert.Nil(err)
+
+	AfterTest()
+}
+
+func TestObjectInheritsPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+	e.ForOwner(ow).Projects().For("p2").Environments().Create(&api.EnvironmentInfo{Code: "dev"})
+	parent := &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "obj1"}
+	child := &api.ObjectInfo{Code: "child", Inherits: parent}
+
+	objects := forKey(e, domain.RoleEditor, "p2", "").Projects().For("p2").Environments().For("dev").Objects()
+	_, err := objects.Create(child)
+	assert.Equal(api.ErrForbidden, err)
+
+	engineObjects := e.ForOwner(ow).Projects().For("p2").Environments().For("dev").Objects()
+	_, err = engineObjects.Create(&api.ObjectInfo{Code: "child"})
+	assert.Nil(err)
+	_, err = objects.Update(&api.ObjectInfo{Code: "child", Inherits: parent, Version: 1})
+	assert.Equal(api.ErrForbidden, err)
+
+	_, err = engineObjects.Update(&api.ObjectInfo{Code: "child", Inherits: parent, Version: 1})
+	assert.Nil(err)
+	_, err = engineObjects.Update(&api.ObjectInfo{Code: "child", Version: 2})
+	assert.Nil(err)
+	_, err = objects.Revert("child", 2)
+	assert.Equal(api.ErrForbidden, err)
+	_, err = objects.Revert("child", 1)
+	assert.Nil(err)
+
+	_, err = forKey(e, domain.RoleEditor, "", "").Projects().For("p2").Environments().For("dev").Objects().Revert("child", 2)
+	assert.Nil(err)
+
+	AfterTest()
+}
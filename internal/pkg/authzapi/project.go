@@ -0,0 +1,125 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type authzProjectAPI struct {
+	authorizer
+	engine api.ProjectAPI
+}
+
+// List returns projects the key has access to
+func (a *authzProjectAPI) List() ([]*domain.Project, error) {
+	if err := a.check(domain.PermissionRead, "", ""); err != nil {
+		return nil, err
+	}
+	list, err := a.engine.List()
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]*domain.Project, 0)
+	for _, p := range list {
+		if a.check(domain.PermissionRead, p.Code, "") == nil {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed, nil
+}
+
+func (a *authzProjectAPI) Get(code domain.ProjectCode) (*domain.Project, error) {
+	if err := a.check(domain.PermissionRead, code, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(code)
+}
+
+func (a *authzProjectAPI) Create(info *api.ProjectInfo) (*domain.Project, error) {
+	if err := a.check(domain.PermissionAdmin, info.Code, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzProjectAPI) Update(info *api.ProjectInfo) (*domain.Project, error) {
+	if err := a.check(domain.PermissionAdmin, info.Code, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Update(info)
+}
+
+func (a *authzProjectAPI) Delete(code domain.ProjectCode) error {
+	if err := a.check(domain.PermissionAdmin, code, ""); err != nil {
+		return err
+	}
+	return a.engine.Delete(code)
+}
+
+func (a *authzProjectAPI) Export(code domain.ProjectCode) (*domain.Document, error) {
+	if err := a.check(domain.PermissionRead, code, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Export(code)
+}
+
+// Import requires admin permission for the project, import may create environments and segments.
+// Objects inheriting other projects require read permission for the parent environments.
+func (a *authzProjectAPI) Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error) {
+	var code domain.ProjectCode
+	if doc.Project != nil {
+		code = doc.Project.Code
+	}
+	if err := a.check(domain.PermissionAdmin, code, ""); err != nil {
+		return nil, err
+	}
+	for _, env := range doc.Environments {
+		for _, obj := range env.Objects {
+			if obj.Inherits == nil || obj.Inherits.ProjectCode == code {
+				continue
+			}
+			if err := a.check(domain.PermissionRead, obj.Inherits.ProjectCode, obj.Inherits.EnvCode); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return a.engine.Import(doc, dryRun, override)
+}
+
+func (a *authzProjectAPI) For(code domain.ProjectCode) api.ForProjectAPI {
+	return &authzForProjectAPI{
+		authorizer:  a.authorizer,
+		projectCode: code,
+		engine:      a.engine.For(code),
+	}
+}
+
+type authzForProjectAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	engine      api.ForProjectAPI
+}
+
+func (a *authzForProjectAPI) Environments() api.EnvironmentAPI {
+	return &authzEnvAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		engine:      a.engine.Environments(),
+	}
+}
+
+func (a *authzForProjectAPI) Segments() api.SegmentAPI {
+	return &authzSegmentAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		engine:      a.engine.Segments(),
+	}
+}
+
+func (a *authzForProjectAPI) Webhooks() api.WebhookAPI {
+	return &authzWebhookAPI{
+		authorizer:  a.authorizer,
+		projectCode: a.projectCode,
+		engine:      a.engine.Webhooks(),
+	}
+}
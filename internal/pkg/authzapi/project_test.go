@@ -0,0 +1,173 @@
+package authzapi_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/authzapi"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func projectCodes(list []*domain.Project) []domain.ProjectCode {
+	codes := make([]domain.ProjectCode, 0)
+	for _, p := range list {
+		codes = append(codes, p.Code)
+	}
+	return codes
+}
+
+func TestMasterKey(t *testing.T) {
+	assert := asserts.New(t)
+	e := getEngine()
+	assert.Equal(e, authzapi.NewAuthorizedAPI(e, nil))
+}
+
+func TestProjectPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	admin := forKey(e, domain.RoleAdmin, "", "").Projects()
+	list, err := admin.List()
+	assert.Nil(err)
+	assert.Equal([]domain.ProjectCode{"p1", "p2"}, projectCodes(list))
+	_, err = admin.Create(&api.ProjectInfo{Code: "p3", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	assert.Nil(admin.Delete("p3"))
+
+	scoped := forKey(e, domain.RoleReadOnly, "p1", "").Projects()
+	list, err = scoped.List()
+	assert.Nil(err)
+	assert.Equal([]domain.ProjectCode{"p1"}, projectCodes(list))
+	_, err = scoped.Get("p1")
+	assert.Nil(err)
+	_, err = scoped.Get("p2")
+	assert.Equal(api.ErrForbidden, err)
+
+	editor := forKey(e, domain.RoleEditor, "", "").Projects()
+	_, err = editor.Update(&api.ProjectInfo{Code: "p1", Status: domain.ProjectStatusDisabled})
+	assert.Equal(api.ErrForbidden, err)
+	assert.Equal(api.ErrForbidden, editor.Delete("p2"))
+
+	scopedAdmin := forKey(e, domain.RoleAdmin, "p1", "").Projects()
+	_, err = scopedAdmin.Create(&api.ProjectInfo{Code: "p3", Status: domain.ProjectStatusActive})
+	assert.Equal(api.ErrForbidden, err)
+	_, err = scopedAdmin.Update(&api.ProjectInfo{Code: "p1", Description: "updated", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+
+	_, err = forKey(e, domain.RoleEvaluate, "", "").Projects().List()
+	assert.Equal(api.ErrForbidden, err)
+
+	AfterTest()
+}
+
+func TestKeyPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+
+	key, err := forKey(e, domain.RoleAdmin, "", "").Keys().Create(&api.KeyInfo{Role: domain.RoleReadOnly})
+	assert.Nil(err)
+
+	for _, owner := range []api.OwnerAPI{
+		forKey(e, domain.RoleEditor, "", ""),
+		forKey(e, domain.RoleAdmin, "p1", ""),
+	} {
+		_, err = owner.Keys().List()
+		assert.Equal(api.ErrForbidden, err)
+		_, err = owner.Keys().Create(&api.KeyInfo{Role: domain.RoleAdmin})
+		assert.Equal(api.ErrForbidden, err)
+		assert.Equal(api.ErrForbidden, owner.Keys().Revoke(key.ID))
+		_, err = owner.Audit().List(&domain.AuditFilter{})
+		assert.Equal(api.ErrForbidden, err)
+	}
+
+	_, err = forKey(e, domain.RoleAdmin, "", "").Audit().List(&domain.AuditFilter{})
+	assert.Nil(err)
+
+	other := &domain.APIKey{OwnerID: "another_owner", Role: domain.RoleAdmin}
+	_, err = authzapi.NewAuthorizedAPI(e, other).ForOwner(ow).Keys().List()
+	assert.Equal(api.ErrForbidden, err)
+
+	AfterTest()
+}
+
+func TestWebhookPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	hooks := forKey(e, domain.RoleAdmin, "p1", "").Projects().For("p1").Webhooks()
+	hook, err := hooks.Create(&api.WebhookInfo{URL: "https://example.com/hook"})
+	assert.Nil(err)
+	_, err = hooks.List()
+	assert.Nil(err)
+
+	for _, owner := range []api.OwnerAPI{
+		forKey(e, domain.RoleEditor, "", ""),
+		forKey(e, domain.RoleAdmin, "p2", ""),
+		forKey(e, domain.RoleAdmin, "p1", "dev"),
+	} {
+		hooks := owner.Projects().For("p1").Webhooks()
+		_, err = hooks.List()
+		assert.Equal(api.ErrForbidden, err)
+		_, err = hooks.Get(hook.ID)
+		assert.Equal(api.ErrForbidden, err)
+		_, err = hooks.Create(&api.WebhookInfo{URL: "https://example.com/hook"})
+		assert.Equal(api.ErrForbidden, err)
+		_, err = hooks.Update(&api.WebhookInfo{ID: hook.ID, URL: "https://example.com/hook"})
+		assert.Equal(api.ErrForbidden, err)
+		_, err = hooks.Deliveries(hook.ID)
+		assert.Equal(api.ErrForbidden, err)
+		_, err = hooks.Test(hook.ID)
+		assert.Equal(api.ErrForbidden, err)
+		assert.Equal(api.ErrForbidden, hooks.Delete(hook.ID))
+	}
+
+	AfterTest()
+}
+
+func TestTransferPermissions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	e := getEngine()
+	prepare(e)
+
+	scoped := forKey(e, domain.RoleReadOnly, "p1", "").Projects()
+	doc, err := scoped.Export("p1")
+	assert.Nil(err)
+	_, err = scoped.Export("p2")
+	assert.Equal(api.ErrForbidden, err)
+
+	_, err = forKey(e, domain.RoleEditor, "p1", "").Projects().Import(doc, true, true)
+	assert.Equal(api.ErrForbidden, err)
+	_, err = forKey(e, domain.RoleAdmin, "p2", "").Projects().Import(doc, true, true)
+	assert.Equal(api.ErrForbidden, err)
+	changes, err := forKey(e, domain.RoleAdmin, "p1", "").Projects().Import(doc, false, true)
+	assert.Nil(err)
+	for _, c := range changes {
+		assert.Equal(domain.ImportUnchanged, c.Action)
+	}
+
+	external := &domain.Document{
+		Version: domain.DocumentVersion,
+		Project: &domain.DocumentProject{Code: "p2", Status: domain.ProjectStatusActive},
+		Environments: []*domain.DocumentEnvironment{{
+			Code: "dev",
+			Objects: []*domain.DocumentObject{{
+				Code:     "child",
+				Inherits: &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "obj1"},
+			}},
+		}},
+	}
+	_, err = forKey(e, domain.RoleAdmin, "p2", "").Projects().Import(external, true, false)
+	assert.Equal(api.ErrForbidden, err)
+	changes, err = forKey(e, domain.RoleAdmin, "", "").Projects().Import(external, true, false)
+	assert.Nil(err)
+	assert.Len(changes, 3)
+
+	AfterTest()
+}
@@ -0,0 +1,48 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// authzSegmentAPI treats segments as project level resources
+type authzSegmentAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	engine      api.SegmentAPI
+}
+
+func (a *authzSegmentAPI) List() ([]*domain.Segment, error) {
+	if err := a.check(domain.PermissionRead, a.projectCode, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.List()
+}
+
+func (a *authzSegmentAPI) Get(code domain.SegmentCode) (*domain.Segment, error) {
+	if err := a.check(domain.PermissionRead, a.projectCode, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(code)
+}
+
+func (a *authzSegmentAPI) Create(info *api.SegmentInfo) (*domain.Segment, error) {
+	if err := a.check(domain.PermissionWrite, a.projectCode, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzSegmentAPI) Update(info *api.SegmentInfo) (*domain.Segment, error) {
+	if err := a.check(domain.PermissionWrite, a.projectCode, ""); err != nil {
+		return nil, err
+	}
+	return a.engine.Update(info)
+}
+
+func (a *authzSegmentAPI) Delete(code domain.SegmentCode) error {
+	if err := a.check(domain.PermissionWrite, a.projectCode, ""); err != nil {
+		return err
+	}
+	return a.engine.Delete(code)
+}
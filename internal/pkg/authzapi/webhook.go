@@ -0,0 +1,66 @@
+package authzapi
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// authzWebhookAPI requires project admin permission, webhooks expose project changes to external services
+type authzWebhookAPI struct {
+	authorizer
+	projectCode domain.ProjectCode
+	engine      api.WebhookAPI
+}
+
+func (a *authzWebhookAPI) admin() error {
+	return a.check(domain.PermissionAdmin, a.projectCode, "")
+}
+
+func (a *authzWebhookAPI) List() ([]*domain.Webhook, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.List()
+}
+
+func (a *authzWebhookAPI) Get(id domain.WebhookID) (*domain.Webhook, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.Get(id)
+}
+
+func (a *authzWebhookAPI) Create(info *api.WebhookInfo) (*domain.Webhook, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.Create(info)
+}
+
+func (a *authzWebhookAPI) Update(info *api.WebhookInfo) (*domain.Webhook, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.Update(info)
+}
+
+func (a *authzWebhookAPI) Delete(id domain.WebhookID) error {
+	if err := a.admin(); err != nil {
+		return err
+	}
+	return a.engine.Delete(id)
+}
+
+func (a *authzWebhookAPI) Deliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.Deliveries(id)
+}
+
+func (a *authzWebhookAPI) Test(id domain.WebhookID) (*domain.WebhookDelivery, error) {
+	if err := a.admin(); err != nil {
+		return nil, err
+	}
+	return a.engine.Test(id)
+}
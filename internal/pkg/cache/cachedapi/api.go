@@ -4,8 +4,9 @@ import (
 	"encoding/json"
 	"log"
 
-	"github.com/Toggly/core/internal/api"
 	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 // NewCachedAPI returns cached API implementation
@@ -54,6 +55,19 @@ func (c *cachedAPI) ForOwner(owner string) api.OwnerAPI {
 	}
 }
 
+func (c *cachedAPI) WithActor(actor string) api.TogglyAPI {
+	return &cachedAPI{engine: c.engine.WithActor(actor), cache: c.cache}
+}
+
+func (c *cachedAPI) WithRequestID(requestID string) api.TogglyAPI {
+	return &cachedAPI{engine: c.engine.WithRequestID(requestID), cache: c.cache}
+}
+
+// Authenticate is not cached so revoked keys are rejected immediately
+func (c *cachedAPI) Authenticate(key string) (*domain.APIKey, error) {
+	return c.engine.Authenticate(key)
+}
+
 type cachedOwnerAPI struct {
 	owner  string
 	engine api.OwnerAPI
@@ -67,3 +81,12 @@ func (c *cachedOwnerAPI) Projects() api.ProjectAPI {
 		cache:  c.cache,
 	}
 }
+
+func (c *cachedOwnerAPI) Keys() api.KeyAPI {
+	return c.engine.Keys()
+}
+
+// Audit is not cached, audit log is appended on every change
+func (c *cachedOwnerAPI) Audit() api.AuditAPI {
+	return c.engine.Audit()
+}
@@ -1,35 +1,25 @@
 package cachedapi_test
 
 import (
-	"log"
-
-	"github.com/Toggly/core/internal/api"
 	"github.com/Toggly/core/internal/pkg/cache"
 	"github.com/Toggly/core/internal/pkg/cache/cachedapi"
 	"github.com/Toggly/core/internal/pkg/engine"
-	"github.com/Toggly/core/internal/pkg/storage/mongo"
-	"github.com/globalsign/mgo"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/api"
 )
 
-const MongoTestUrl = "mongodb://localhost:27017/toggly_cache_test"
+var dataStorage storage.DataStorage = memory.NewMemoryStorage()
 
 func getEngineAndCache() (api.TogglyAPI, cache.DataCache) {
-	dataStorage, err := mongo.NewMongoStorage(MongoTestUrl)
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
 	dataCache := &cache.InMemoryCache{
 		Storage: make(map[string][]byte, 0),
 	}
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-	return cachedapi.NewCachedAPI(engine.NewTogglyAPI(&dataStorage), dataCache), dataCache
+	return cachedapi.NewCachedAPI(&engine.Engine{Storage: &dataStorage, DryRunStorage: memory.NewMemoryStorage}, dataCache), dataCache
 }
 
 func DropDB() {
-	session, _ := mgo.Dial(MongoTestUrl)
-	session.DB("").DropDatabase()
+	dataStorage = memory.NewMemoryStorage()
 }
 
 func BeforeTest() {
@@ -4,15 +4,17 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 type cachedEnvAPI struct {
 	owner       string
 	projectCode domain.ProjectCode
 	engine      api.EnvironmentAPI
+	projects    api.ProjectAPI
+	segments    api.SegmentAPI
 	cache       cache.DataCache
 }
 
@@ -91,7 +93,9 @@ func (c *cachedEnvAPI) For(code domain.EnvironmentCode) api.ForObjectAPI {
 		owner:       c.owner,
 		projectCode: c.projectCode,
 		envCode:     code,
-		engine:      c.engine.For(code).Objects(),
+		engine:      c.engine.For(code),
+		projects:    c.projects,
+		segments:    c.segments,
 		cache:       c.cache,
 	}
 }
@@ -100,7 +104,9 @@ type cachedForObjectAPI struct {
 	owner       string
 	projectCode domain.ProjectCode
 	envCode     domain.EnvironmentCode
-	engine      api.ObjectAPI
+	engine      api.ForObjectAPI
+	projects    api.ProjectAPI
+	segments    api.SegmentAPI
 	cache       cache.DataCache
 }
 
@@ -109,7 +115,15 @@ func (c *cachedForObjectAPI) Objects() api.ObjectAPI {
 		owner:       c.owner,
 		projectCode: c.projectCode,
 		envCode:     c.envCode,
-		engine:      c.engine,
+		engine:      c.engine.Objects(),
+		projects:    c.projects,
+		segments:    c.segments,
 		cache:       c.cache,
 	}
 }
+
+func (c *cachedForObjectAPI) OverrideProtection() api.ForObjectAPI {
+	res := *c
+	res.engine = c.engine.OverrideProtection()
+	return &res
+}
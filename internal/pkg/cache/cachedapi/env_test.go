@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	asserts "github.com/stretchr/testify/assert"
 )
 
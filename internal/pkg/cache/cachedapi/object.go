@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 type cachedObjectAPI struct {
@@ -15,9 +16,21 @@ type cachedObjectAPI struct {
 	projectCode domain.ProjectCode
 	envCode     domain.EnvironmentCode
 	engine      api.ObjectAPI
+	projects    api.ProjectAPI
+	segments    api.SegmentAPI
 	cache       cache.DataCache
 }
 
+// projectDisabled reports whether the project is disabled.
+// Disabled projects are evaluated by the engine, which decides if evaluation is available.
+func (c *cachedObjectAPI) projectDisabled() (bool, error) {
+	proj, err := c.projects.Get(c.projectCode)
+	if err != nil {
+		return false, err
+	}
+	return proj.Status == domain.ProjectStatusDisabled, nil
+}
+
 func (c *cachedObjectAPI) basePath() string {
 	return fmt.Sprintf("/own/%s/project/%s/env/%s/object", c.owner, c.projectCode, c.envCode)
 }
@@ -70,23 +83,31 @@ func (c *cachedObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := c.flushWithInheritors(obj.Code); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// flushWithInheritors flushes the object and its inheritors
+func (c *cachedObjectAPI) flushWithInheritors(code domain.ObjectCode) error {
 	scopes := make([]string, 0)
 	scopes = append(scopes, c.basePath())
-	scopes = append(scopes, fmt.Sprintf("%s/%s", c.basePath(), obj.Code))
-	inheritors, err := c.engine.InheritorsFlatList(obj.Code)
+	scopes = append(scopes, fmt.Sprintf("%s/%s", c.basePath(), code))
+	inheritors, err := c.engine.InheritorsFlatList(code)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	for _, i := range inheritors {
 		scopes = append(scopes, fmt.Sprintf("/own/%s/project/%s/env/%s/object", i.Owner, i.ProjectCode, i.EnvCode))
 		scopes = append(scopes, fmt.Sprintf("/own/%s/project/%s/env/%s/object/%s", i.Owner, i.ProjectCode, i.EnvCode, i.Code))
 	}
 	c.cache.Flush(scopes...)
-	return obj, nil
+	return nil
 }
 
-func (c *cachedObjectAPI) Delete(code domain.ObjectCode) error {
-	if err := c.engine.Delete(code); err != nil {
+func (c *cachedObjectAPI) Delete(code domain.ObjectCode, version int64) error {
+	if err := c.engine.Delete(code, version); err != nil {
 		return err
 	}
 	scopes := make([]string, 0)
@@ -96,7 +117,57 @@ func (c *cachedObjectAPI) Delete(code domain.ObjectCode) error {
 	return nil
 }
 
+// History is not cached, it is rarely requested
+func (c *cachedObjectAPI) History(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	return c.engine.History(code)
+}
+
+func (c *cachedObjectAPI) Revert(code domain.ObjectCode, revision int64) (*domain.Object, error) {
+	obj, err := c.engine.Revert(code, revision)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.flushWithInheritors(obj.Code); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
 func (c *cachedObjectAPI) InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error) {
 	log.Print("[WARN] API method `InheritorsFlatList` not cached. It may cause performance problems. Consider to avoid it.")
 	return c.engine.InheritorsFlatList(code)
 }
+
+func (c *cachedObjectAPI) Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error) {
+	disabled, err := c.projectDisabled()
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		return c.engine.Evaluate(code, ctx)
+	}
+	obj, err := c.Get(code)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := engine.ObjectSegments(c.segments, obj)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Evaluate(obj, segments, ctx), nil
+}
+
+func (c *cachedObjectAPI) EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	disabled, err := c.projectDisabled()
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		return c.engine.EvaluateAll(ctx)
+	}
+	list, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return engine.EvaluateAll(list, c.segments, ctx)
+}
@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	asserts "github.com/stretchr/testify/assert"
 )
 
@@ -61,7 +61,7 @@ func TestObjectCaching(t *testing.T) {
 		json.Unmarshal(b, obj)
 		assert.Equal(domain.ObjectCode("obj1"), obj.Code)
 		assert.Equal("Description 1", obj.Description)
-		eng.Delete("obj1")
+		eng.Delete("obj1", 0)
 	})
 
 	t.Run("invalidate on update", func(t *testing.T) {
@@ -103,9 +103,9 @@ func TestObjectCaching(t *testing.T) {
 		b, err = cache.Get("/own/ow1/project/project1/env/env1/object/obj3")
 		assert.Nil(err)
 		assert.Nil(b)
-		eng.Delete("obj3")
-		eng.Delete("obj2")
-		eng.Delete("obj1")
+		eng.Delete("obj3", 0)
+		eng.Delete("obj2", 0)
+		eng.Delete("obj1", 0)
 	})
 
 	t.Run("invalidate on delete", func(t *testing.T) {
@@ -120,7 +120,7 @@ func TestObjectCaching(t *testing.T) {
 		b, err = cache.Get("/own/ow1/project/project1/env/env1/object/obj1")
 		assert.Nil(err)
 		assert.NotNil(b)
-		eng.Delete("obj1")
+		eng.Delete("obj1", 0)
 		b, err = cache.Get("/own/ow1/project/project1/env/env1/object")
 		assert.Nil(err)
 		assert.Nil(b)
@@ -131,3 +131,33 @@ func TestObjectCaching(t *testing.T) {
 
 	AfterTest()
 }
+
+func TestObjectParameterTypesCaching(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	engine, _ := getEngineAndCache()
+	engine.ForOwner("ow1").Projects().Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	engine.ForOwner("ow1").Projects().For("project1").Environments().Create(&api.EnvironmentInfo{Code: "env1"})
+	eng := engine.ForOwner("ow1").Projects().For("project1").Environments().For("env1").Objects()
+
+	params := []*domain.Parameter{
+		{Code: "int", Type: domain.ParameterInt, Value: int64(10)},
+		{Code: "float", Type: domain.ParameterFloat, Value: 1.5},
+		{Code: "json", Type: domain.ParameterJSON, Value: map[string]interface{}{"$key": []interface{}{"a", float64(1)}}},
+		{Code: "list", Type: domain.ParameterList, Value: []string{"a", "b"}},
+		{Code: "duration", Type: domain.ParameterDuration, Value: "1m30s"},
+		{Code: "semver", Type: domain.ParameterSemver, Value: "1.2.3"},
+	}
+	_, err := eng.Create(&api.ObjectInfo{Code: "obj1", Parameters: params})
+	assert.Nil(err)
+
+	for i := 0; i < 2; i++ {
+		obj, err := eng.Get("obj1")
+		assert.Nil(err)
+		assert.Equal(params, obj.Parameters)
+	}
+
+	AfterTest()
+}
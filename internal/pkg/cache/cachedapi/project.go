@@ -4,9 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 type cachedProjectAPI struct {
@@ -85,11 +86,25 @@ func (c *cachedProjectAPI) Delete(code domain.ProjectCode) error {
 	return nil
 }
 
+// Export is not cached, it is used rarely
+func (c *cachedProjectAPI) Export(code domain.ProjectCode) (*domain.Document, error) {
+	return c.engine.Export(code)
+}
+
+// Import saves entities through the cached api, so their cache is flushed
+func (c *cachedProjectAPI) Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error) {
+	if dryRun {
+		return c.engine.Import(doc, true, override)
+	}
+	return transfer.Import(c, doc, override)
+}
+
 func (c *cachedProjectAPI) For(code domain.ProjectCode) api.ForProjectAPI {
 	return &cachedForProjectAPI{
 		owner:       c.owner,
 		projectCode: code,
-		engine:      c.engine.For(code).Environments(),
+		engine:      c.engine.For(code),
+		projects:    c,
 		cache:       c.cache,
 	}
 }
@@ -97,7 +112,8 @@ func (c *cachedProjectAPI) For(code domain.ProjectCode) api.ForProjectAPI {
 type cachedForProjectAPI struct {
 	owner       string
 	projectCode domain.ProjectCode
-	engine      api.EnvironmentAPI
+	engine      api.ForProjectAPI
+	projects    api.ProjectAPI
 	cache       cache.DataCache
 }
 
@@ -105,7 +121,23 @@ func (c *cachedForProjectAPI) Environments() api.EnvironmentAPI {
 	return &cachedEnvAPI{
 		owner:       c.owner,
 		projectCode: c.projectCode,
-		engine:      c.engine,
+		engine:      c.engine.Environments(),
+		projects:    c.projects,
+		segments:    c.Segments(),
 		cache:       c.cache,
 	}
 }
+
+func (c *cachedForProjectAPI) Segments() api.SegmentAPI {
+	return &cachedSegmentAPI{
+		owner:       c.owner,
+		projectCode: c.projectCode,
+		engine:      c.engine.Segments(),
+		cache:       c.cache,
+	}
+}
+
+// Webhooks are not cached, they are rarely read by clients
+func (c *cachedForProjectAPI) Webhooks() api.WebhookAPI {
+	return c.engine.Webhooks()
+}
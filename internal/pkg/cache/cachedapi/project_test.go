@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/domain"
 
-	"github.com/Toggly/core/internal/api"
+	"github.com/Toggly/core/pkg/api"
 	asserts "github.com/stretchr/testify/assert"
 )
 
@@ -82,3 +82,35 @@ func TestProjectCaching(t *testing.T) {
 
 	AfterTest()
 }
+
+func TestImportFlushesCache(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	engine, cache := getEngineAndCache()
+	eng := engine.ForOwner("ow1").Projects()
+
+	eng.Create(&api.ProjectInfo{Code: "project1", Description: "Description 1", Status: domain.ProjectStatusActive})
+	eng.Get("project1")
+	doc, err := eng.Export("project1")
+	assert.Nil(err)
+	doc.Project.Description = "Description 2"
+
+	_, err = eng.Import(doc, true, true)
+	assert.Nil(err)
+	b, err := cache.Get("/own/ow1/project/project1")
+	assert.Nil(err)
+	assert.NotNil(b, "dry run keeps cache")
+
+	_, err = eng.Import(doc, false, true)
+	assert.Nil(err)
+	b, err = cache.Get("/own/ow1/project/project1")
+	assert.Nil(err)
+	assert.Nil(b)
+	proj, err := eng.Get("project1")
+	assert.Nil(err)
+	assert.Equal("Description 2", proj.Description)
+
+	AfterTest()
+}
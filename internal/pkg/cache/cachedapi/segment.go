@@ -0,0 +1,87 @@
+package cachedapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type cachedSegmentAPI struct {
+	owner       string
+	projectCode domain.ProjectCode
+	engine      api.SegmentAPI
+	cache       cache.DataCache
+}
+
+func (c *cachedSegmentAPI) basePath() string {
+	return fmt.Sprintf("/own/%s/project/%s/segment", c.owner, c.projectCode)
+}
+
+func (c *cachedSegmentAPI) List() ([]*domain.Segment, error) {
+	key := c.basePath()
+	bytes, err := withCache(c.cache, key, func() (interface{}, error) {
+		return c.engine.List()
+	})
+	if err != nil {
+		return nil, err
+	}
+	var list []*domain.Segment
+	err = json.Unmarshal(bytes, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (c *cachedSegmentAPI) Get(code domain.SegmentCode) (*domain.Segment, error) {
+	key := fmt.Sprintf("%s/%s", c.basePath(), code)
+	bytes, err := withCache(c.cache, key, func() (interface{}, error) {
+		return c.engine.Get(code)
+	})
+	if err != nil {
+		return nil, err
+	}
+	segment := &domain.Segment{}
+	err = json.Unmarshal(bytes, segment)
+	if err != nil {
+		return nil, err
+	}
+	return segment, err
+}
+
+func (c *cachedSegmentAPI) Create(info *api.SegmentInfo) (*domain.Segment, error) {
+	segment, err := c.engine.Create(info)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Flush([]string{
+		c.basePath(),
+	}...)
+	return segment, nil
+}
+
+func (c *cachedSegmentAPI) Update(info *api.SegmentInfo) (*domain.Segment, error) {
+	segment, err := c.engine.Update(info)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Flush([]string{
+		c.basePath(),
+		fmt.Sprintf("%s/%s", c.basePath(), segment.Code),
+	}...)
+	return segment, nil
+}
+
+func (c *cachedSegmentAPI) Delete(code domain.SegmentCode) error {
+	if err := c.engine.Delete(code); err != nil {
+		return err
+	}
+	c.cache.Flush([]string{
+		c.basePath(),
+		fmt.Sprintf("%s/%s", c.basePath(), code),
+	}...)
+	return nil
+}
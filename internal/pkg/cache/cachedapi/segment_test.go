@@ -0,0 +1,66 @@
+package cachedapi_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestSegmentCaching(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	engine, cache := getEngineAndCache()
+	engine.ForOwner("ow1").Projects().Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	engine.ForOwner("ow1").Projects().For("project1").Environments().Create(&api.EnvironmentInfo{Code: "env1"})
+	eng := engine.ForOwner("ow1").Projects().For("project1").Segments()
+	objEng := engine.ForOwner("ow1").Projects().For("project1").Environments().For("env1").Objects()
+
+	eng.Create(&api.SegmentInfo{Code: "beta", Include: []string{"u1"}})
+
+	b, err := cache.Get("/own/ow1/project/project1/segment/beta")
+	assert.Nil(err)
+	assert.Nil(b)
+
+	_, err = objEng.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "new_ui", Type: domain.ParameterBool, Value: false, Rules: []*domain.Rule{{Segment: "beta", Value: true}}},
+		},
+	})
+	assert.Nil(err)
+
+	values, err := objEng.Evaluate("obj1", &domain.EvaluationContext{UserID: "u1"})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true}, values)
+
+	b, err = cache.Get("/own/ow1/project/project1/segment/beta")
+	assert.Nil(err)
+	assert.NotNil(b)
+
+	eng.Update(&api.SegmentInfo{Code: "beta", Include: []string{"u2"}})
+
+	b, err = cache.Get("/own/ow1/project/project1/segment/beta")
+	assert.Nil(err)
+	assert.Nil(b)
+
+	values, err = objEng.Evaluate("obj1", &domain.EvaluationContext{UserID: "u1"})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": false}, values)
+
+	err = eng.Delete("beta")
+	assert.Equal(api.ErrSegmentInUse, err)
+
+	objEng.Delete("obj1", 0)
+	err = eng.Delete("beta")
+	assert.Nil(err)
+
+	b, err = cache.Get("/own/ow1/project/project1/segment")
+	assert.Nil(err)
+	assert.Nil(b)
+
+	AfterTest()
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"fmt"
+	"sync"
 )
 
 // NewInMemoryCache returns in-memory cache implementation
@@ -14,23 +15,30 @@ func NewInMemoryCache() DataCache {
 // InMemoryCache type
 type InMemoryCache struct {
 	Storage map[string][]byte
+	lock    sync.RWMutex
 }
 
 // Get cached data by key
 func (c *InMemoryCache) Get(key string) (data []byte, err error) {
 	fmt.Printf("[DEBUG] Cache get key: %s\n", key)
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.Storage[key], nil
 }
 
 // Set cache for key
 func (c *InMemoryCache) Set(key string, data []byte) error {
 	fmt.Printf("[DEBUG] Cache set key: %s\n", key)
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	c.Storage[key] = data
 	return nil
 }
 
 // Flush data
 func (c *InMemoryCache) Flush(scopes ...string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 	for _, s := range scopes {
 		fmt.Printf("[DEBUG] Invalidate cache for key: %s\n", s)
 		delete(c.Storage, s)
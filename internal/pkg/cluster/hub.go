@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Hub connects in-process transports, it runs several nodes in one process
+type Hub struct {
+	lock       sync.RWMutex
+	transports map[*hubTransport]struct{}
+}
+
+// NewHub returns hub without transports
+func NewHub() *Hub {
+	return &Hub{transports: make(map[*hubTransport]struct{})}
+}
+
+// Transport returns new transport connected to the hub
+func (h *Hub) Transport() Transport {
+	t := &hubTransport{hub: h}
+	h.lock.Lock()
+	h.transports[t] = struct{}{}
+	h.lock.Unlock()
+	return t
+}
+
+type hubTransport struct {
+	hub     *Hub
+	lock    sync.Mutex
+	handler func(msg *Message)
+}
+
+// Send delivers message synchronously, it is encoded the same way as for network transports
+func (t *hubTransport) Send(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.hub.lock.RLock()
+	defer t.hub.lock.RUnlock()
+	for r := range t.hub.transports {
+		m := &Message{}
+		if err := json.Unmarshal(data, m); err != nil {
+			return err
+		}
+		r.deliver(m)
+	}
+	return nil
+}
+
+func (t *hubTransport) deliver(msg *Message) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.handler != nil {
+		t.handler(msg)
+	}
+}
+
+func (t *hubTransport) Listen(handler func(msg *Message)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.handler = handler
+}
+
+func (t *hubTransport) Close() error {
+	t.hub.lock.Lock()
+	defer t.hub.lock.Unlock()
+	delete(t.hub.transports, t)
+	return nil
+}
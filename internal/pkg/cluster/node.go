@@ -0,0 +1,148 @@
+// Package cluster propagates cache invalidations and change events between server instances
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+
+	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// queueSize is the number of messages waiting to be sent
+const queueSize = 1024
+
+// Message is sent by a node to all nodes
+type Message struct {
+	Node  string        `json:"node"`
+	Keys  []string      `json:"keys,omitempty"`
+	Event *domain.Event `json:"event,omitempty"`
+}
+
+// Transport broadcasts messages to all nodes including the sender
+type Transport interface {
+	Send(msg *Message) error
+	// Listen starts delivering received messages to the handler one by one
+	Listen(handler func(msg *Message))
+	Close() error
+}
+
+// Node connects local cache and events of the server instance to other instances.
+// Messages are sent asynchronously, they are dropped if the queue is full.
+type Node struct {
+	ID        string
+	transport Transport
+	cache     cache.DataCache
+	events    engine.EventPublisher
+	queue     chan *Message
+	done      chan struct{}
+	once      sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewNode returns node sending changes through the transport. Messages of other nodes flush keys of the local cache
+// and are published to the local events publisher, so the publisher must not send them to webhooks again.
+// Both cache and events can be nil.
+func NewNode(transport Transport, local cache.DataCache, events engine.EventPublisher) *Node {
+	id := make([]byte, 8)
+	rand.Read(id)
+	n := &Node{
+		ID:        hex.EncodeToString(id),
+		transport: transport,
+		cache:     local,
+		events:    events,
+		queue:     make(chan *Message, queueSize),
+		done:      make(chan struct{}),
+	}
+	transport.Listen(n.receive)
+	n.wg.Add(1)
+	go n.run()
+	return n
+}
+
+// Cache returns local cache which broadcasts invalidated keys to other nodes
+func (n *Node) Cache() cache.DataCache {
+	if n.cache == nil {
+		return nil
+	}
+	return &nodeCache{node: n}
+}
+
+// Publish queues the event to other nodes
+func (n *Node) Publish(event *domain.Event) {
+	n.send(&Message{Node: n.ID, Event: event})
+}
+
+// Close stops sending queued messages and the transport
+func (n *Node) Close() error {
+	n.once.Do(func() { close(n.done) })
+	n.wg.Wait()
+	return n.transport.Close()
+}
+
+// send queues the message, it is dropped if the queue is full or node is closed
+func (n *Node) send(msg *Message) {
+	select {
+	case <-n.done:
+		return
+	default:
+	}
+	select {
+	case n.queue <- msg:
+	default:
+		log.Printf("[ERROR] Cluster queue is full, message dropped")
+	}
+}
+
+func (n *Node) run() {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.done:
+			return
+		case msg := <-n.queue:
+			if err := n.transport.Send(msg); err != nil {
+				log.Printf("[ERROR] Can't send cluster message: %v", err)
+			}
+		}
+	}
+}
+
+func (n *Node) receive(msg *Message) {
+	if msg.Node == n.ID {
+		return
+	}
+	if len(msg.Keys) > 0 && n.cache != nil {
+		if err := n.cache.Flush(msg.Keys...); err != nil {
+			log.Printf("[ERROR] Can't flush cache: %v", err)
+		}
+	}
+	if msg.Event != nil && n.events != nil {
+		n.events.Publish(msg.Event)
+	}
+}
+
+type nodeCache struct {
+	node *Node
+}
+
+func (c *nodeCache) Get(key string) ([]byte, error) {
+	return c.node.cache.Get(key)
+}
+
+func (c *nodeCache) Set(key string, data []byte) error {
+	return c.node.cache.Set(key, data)
+}
+
+func (c *nodeCache) Flush(scopes ...string) error {
+	if err := c.node.cache.Flush(scopes...); err != nil {
+		return err
+	}
+	if len(scopes) > 0 {
+		c.node.send(&Message{Node: c.node.ID, Keys: scopes})
+	}
+	return nil
+}
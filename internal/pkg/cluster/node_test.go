@@ -0,0 +1,135 @@
+package cluster_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/cache"
+	"github.com/Toggly/core/internal/pkg/cache/cachedapi"
+	"github.com/Toggly/core/internal/pkg/cluster"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+type replica struct {
+	api   api.TogglyAPI
+	cache cache.DataCache
+	bus   *eventbus.Bus
+	node  *cluster.Node
+}
+
+func newReplica(hub *cluster.Hub, dataStorage *storage.DataStorage) *replica {
+	r := &replica{cache: cache.NewInMemoryCache(), bus: eventbus.New(10, 10)}
+	r.node = cluster.NewNode(hub.Transport(), r.cache, r.bus)
+	r.api = cachedapi.NewCachedAPI(&engine.Engine{
+		Storage: dataStorage,
+		Events:  engine.MultiPublisher(r.bus, r.node),
+	}, r.node.Cache())
+	return r
+}
+
+// eventually waits for the condition to become true
+func eventually(cond func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestReplicas(t *testing.T) {
+	assert := asserts.New(t)
+	dataStorage := memory.NewMemoryStorage()
+	hub := cluster.NewHub()
+	r1 := newReplica(hub, &dataStorage)
+	r2 := newReplica(hub, &dataStorage)
+	defer r1.node.Close()
+	defer r2.node.Close()
+
+	list, err := r2.api.ForOwner("ow1").Projects().List()
+	assert.Nil(err)
+	assert.Len(list, 0)
+	b, _ := r2.cache.Get("/own/ow1/project")
+	assert.NotNil(b, "list is cached by the second replica")
+
+	s1, _, _ := r1.bus.Subscribe(func(*domain.Event) bool { return true }, 0)
+	s2, _, _ := r2.bus.Subscribe(func(*domain.Event) bool { return true }, 0)
+	_, err = r1.api.ForOwner("ow1").Projects().Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+
+	assert.True(eventually(func() bool {
+		b, _ := r2.cache.Get("/own/ow1/project")
+		return b == nil
+	}), "cache of the second replica is flushed")
+	list, err = r2.api.ForOwner("ow1").Projects().List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+
+	msg := <-s2.C()
+	assert.Equal(domain.EventProjectCreate, msg.Event.Type)
+	assert.Equal(domain.ProjectCode("project1"), msg.Event.ProjectCode)
+	assert.Equal("project1", msg.Event.Data.(map[string]interface{})["code"])
+
+	msg = <-s1.C()
+	assert.Equal(domain.EventProjectCreate, msg.Event.Type)
+	assert.Len(s1.C(), 0, "own events are not received back")
+	assert.Len(s2.C(), 0)
+}
+
+func TestNodeWithoutCache(t *testing.T) {
+	assert := asserts.New(t)
+	hub := cluster.NewHub()
+	node := cluster.NewNode(hub.Transport(), nil, nil)
+	other := cluster.NewNode(hub.Transport(), cache.NewInMemoryCache(), nil)
+	assert.Nil(node.Cache())
+	assert.NotEqual(node.ID, other.ID)
+	node.Publish(&domain.Event{Type: domain.EventProjectDelete})
+	assert.Nil(other.Cache().Flush("key"))
+	assert.Nil(node.Close())
+	assert.Nil(other.Close())
+}
+
+// blockedTransport doesn't return from Send until it is released
+type blockedTransport struct {
+	release chan struct{}
+}
+
+func (t *blockedTransport) Send(msg *cluster.Message) error {
+	<-t.release
+	return nil
+}
+
+func (t *blockedTransport) Listen(handler func(msg *cluster.Message)) {}
+
+func (t *blockedTransport) Close() error {
+	return nil
+}
+
+func TestNodeBlockedTransport(t *testing.T) {
+	assert := asserts.New(t)
+	transport := &blockedTransport{release: make(chan struct{})}
+	node := cluster.NewNode(transport, cache.NewInMemoryCache(), nil)
+
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 2000; i++ {
+			node.Publish(&domain.Event{Type: domain.EventProjectUpdate})
+			node.Cache().Flush("key")
+		}
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		assert.Fail("publish is blocked by the transport")
+	}
+	close(transport.release)
+	assert.Nil(node.Close())
+}
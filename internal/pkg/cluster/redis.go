@@ -0,0 +1,152 @@
+package cluster
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	// redisRetryDelay is the delay before reconnecting lost subscription
+	redisRetryDelay = time.Second
+	// redisTimeout limits connecting to Redis and publishing, subscription waits for messages without timeout
+	redisTimeout = 5 * time.Second
+)
+
+func dialPublisher(url string) (redis.Conn, error) {
+	return redis.DialURL(url,
+		redis.DialConnectTimeout(redisTimeout),
+		redis.DialReadTimeout(redisTimeout),
+		redis.DialWriteTimeout(redisTimeout),
+	)
+}
+
+func dialSubscriber(url string) (redis.Conn, error) {
+	return redis.DialURL(url,
+		redis.DialConnectTimeout(redisTimeout),
+		redis.DialWriteTimeout(redisTimeout),
+	)
+}
+
+// RedisTransport broadcasts messages through Redis pub/sub channel
+type RedisTransport struct {
+	url     string
+	channel string
+	lock    sync.Mutex
+	pub     redis.Conn
+	sub     *redis.PubSubConn
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRedisTransport connects to Redis url, messages are published to the channel
+func NewRedisTransport(url, channel string) (*RedisTransport, error) {
+	pub, err := dialPublisher(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisTransport{
+		url:     url,
+		channel: channel,
+		pub:     pub,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Send publishes message to the channel, connection is restored on the next message if it is broken
+func (t *RedisTransport) Send(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.pub == nil {
+		if t.pub, err = dialPublisher(t.url); err != nil {
+			t.pub = nil
+			return err
+		}
+	}
+	if _, err = t.pub.Do("PUBLISH", t.channel, data); err != nil {
+		t.pub.Close()
+		t.pub = nil
+	}
+	return err
+}
+
+// Listen subscribes to the channel, subscription is restored until the transport is closed.
+// Messages published while subscription is lost are not received.
+func (t *RedisTransport) Listen(handler func(msg *Message)) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		for {
+			if err := t.receive(handler); err != nil {
+				log.Printf("[ERROR] Cluster subscription lost: %v", err)
+			}
+			select {
+			case <-t.done:
+				return
+			case <-time.After(redisRetryDelay):
+			}
+		}
+	}()
+}
+
+func (t *RedisTransport) receive(handler func(msg *Message)) error {
+	conn, err := dialSubscriber(t.url)
+	if err != nil {
+		return err
+	}
+	sub := &redis.PubSubConn{Conn: conn}
+	defer sub.Close()
+	t.lock.Lock()
+	select {
+	case <-t.done:
+		t.lock.Unlock()
+		return nil
+	default:
+	}
+	t.sub = sub
+	t.lock.Unlock()
+	if err := sub.Subscribe(t.channel); err != nil {
+		return err
+	}
+	for {
+		switch v := sub.Receive().(type) {
+		case redis.Message:
+			msg := &Message{}
+			if err := json.Unmarshal(v.Data, msg); err != nil {
+				log.Printf("[ERROR] Can't decode cluster message: %v", err)
+				continue
+			}
+			handler(msg)
+		case error:
+			select {
+			case <-t.done:
+				return nil
+			default:
+				return v
+			}
+		}
+	}
+}
+
+// Close stops the subscription and closes connections
+func (t *RedisTransport) Close() error {
+	t.lock.Lock()
+	close(t.done)
+	if t.sub != nil {
+		t.sub.Close()
+	}
+	var err error
+	if t.pub != nil {
+		err = t.pub.Close()
+	}
+	t.lock.Unlock()
+	t.wg.Wait()
+	return err
+}
@@ -1,8 +1,8 @@
 package engine
 
 import (
-	"github.com/Toggly/core/internal/api"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
 )
 
 // NewTogglyAPI returns api engine
@@ -10,23 +10,91 @@ func NewTogglyAPI(storage *storage.DataStorage) api.TogglyAPI {
 	return &Engine{Storage: storage}
 }
 
-// Engine type
+// DisabledProjectMode defines how disabled projects are served
+type DisabledProjectMode string
+
+// Disabled project modes enum
+const (
+	DisabledProjectReadOnly    DisabledProjectMode = "read-only"
+	DisabledProjectUnavailable DisabledProjectMode = "unavailable"
+)
+
+// Engine type. DryRunStorage returns empty storage for dry run of import, dry run fails if it's not set.
 type Engine struct {
-	Storage *storage.DataStorage
+	Storage             *storage.DataStorage
+	DryRunStorage       func() storage.DataStorage
+	DisabledProjectMode DisabledProjectMode
+	Actor               string
+	Events              EventPublisher
+	Webhooks            WebhookSender
 }
 
 // ForOwner returns owner api
 func (e *Engine) ForOwner(owner string) api.OwnerAPI {
-	return &OwnerAPI{Owner: owner, Storage: e.Storage}
+	return &OwnerAPI{
+		Owner:               owner,
+		Storage:             e.Storage,
+		DryRunStorage:       e.DryRunStorage,
+		DisabledProjectMode: e.DisabledProjectMode,
+		Actor:               e.Actor,
+		Events:              e.Events,
+		Webhooks:            e.Webhooks,
+	}
+}
+
+// WithActor returns engine recording changes on behalf of the actor
+func (e *Engine) WithActor(actor string) api.TogglyAPI {
+	res := *e
+	res.Actor = actor
+	return &res
+}
+
+// WithRequestID returns engine as is, request id is recorded by decorators
+func (e *Engine) WithRequestID(requestID string) api.TogglyAPI {
+	return e
 }
 
 // OwnerAPI type
 type OwnerAPI struct {
-	Owner   string
-	Storage *storage.DataStorage
+	Owner               string
+	Storage             *storage.DataStorage
+	DryRunStorage       func() storage.DataStorage
+	DisabledProjectMode DisabledProjectMode
+	Actor               string
+	Events              EventPublisher
+	Webhooks            WebhookSender
 }
 
 // Projects returns project api
 func (o *OwnerAPI) Projects() api.ProjectAPI {
 	return &ProjectAPI{*o}
 }
+
+// Keys returns api keys api
+func (o *OwnerAPI) Keys() api.KeyAPI {
+	return &KeyAPI{*o}
+}
+
+// Audit returns audit log api
+func (o *OwnerAPI) Audit() api.AuditAPI {
+	return &AuditAPI{*o}
+}
+
+// checkVersion returns error if expected version is specified and differs from the current one
+func checkVersion(expected, current int64) error {
+	if expected != 0 && expected != current {
+		return api.ErrVersionConflict
+	}
+	return nil
+}
+
+// updateError maps storage update error to api error
+func updateError(err, notFound error) error {
+	switch err {
+	case storage.ErrNotFound:
+		return notFound
+	case storage.ErrVersionConflict:
+		return api.ErrVersionConflict
+	}
+	return err
+}
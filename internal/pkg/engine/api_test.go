@@ -1,29 +1,29 @@
 package engine_test
 
 import (
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/engine"
-	"github.com/Toggly/core/internal/pkg/storage/mongo"
-	"github.com/globalsign/mgo"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 const ProjectCode = domain.ProjectCode("p1")
 
-const MongoTestUrl = "mongodb://localhost:27017/toggly_test"
-
 const ow = "test_owner"
 
+var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+
 func GetApi() api.ProjectAPI {
-	dataStorage, _ := mongo.NewMongoStorage(MongoTestUrl)
-	engine := engine.NewTogglyAPI(&dataStorage)
-	pApi := engine.ForOwner(ow).Projects()
-	return pApi
+	return GetEngine(engine.DisabledProjectReadOnly).ForOwner(ow).Projects()
+}
+
+func GetEngine(mode engine.DisabledProjectMode) api.TogglyAPI {
+	return &engine.Engine{Storage: &dataStorage, DisabledProjectMode: mode, DryRunStorage: memory.NewMemoryStorage}
 }
 
 func DropDB() {
-	session, _ := mgo.Dial(MongoTestUrl)
-	session.DB("").DropDatabase()
+	dataStorage = memory.NewMemoryStorage()
 }
 
 func BeforeTest() {
@@ -0,0 +1,22 @@
+package engine
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// AuditAPI servers owner audit log
+type AuditAPI struct {
+	OwnerAPI
+}
+
+// List returns audit records matching the filter
+func (a *AuditAPI) List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	if filter == nil {
+		filter = &domain.AuditFilter{}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, api.NewBadRequestError("Time range end is before its start")
+	}
+	return (*a.Storage).ForOwner(a.Owner).Audit().List(filter)
+}
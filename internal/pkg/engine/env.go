@@ -3,9 +3,9 @@ package engine
 import (
 	"time"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/globalsign/mgo/bson"
 )
 
@@ -17,9 +17,8 @@ type EnvironmentAPI struct {
 	ProjectAPI  *ProjectAPI
 }
 
-func (e *EnvironmentAPI) projectExists() error {
-	_, err := e.ProjectAPI.Get(e.ProjectCode)
-	return err
+func (e *EnvironmentAPI) projectExists(access projectAccess) error {
+	return e.ProjectAPI.check(e.ProjectCode, access)
 }
 
 func (e *EnvironmentAPI) storage() storage.EnvironmentStorage {
@@ -28,7 +27,7 @@ func (e *EnvironmentAPI) storage() storage.EnvironmentStorage {
 
 // List returns list of project environments
 func (e *EnvironmentAPI) List() ([]*domain.Environment, error) {
-	if err := e.projectExists(); err != nil {
+	if err := e.projectExists(accessRead); err != nil {
 		return nil, err
 	}
 	envList, err := e.storage().List()
@@ -40,7 +39,7 @@ func (e *EnvironmentAPI) List() ([]*domain.Environment, error) {
 
 // Get returns environment by code
 func (e *EnvironmentAPI) Get(code domain.EnvironmentCode) (*domain.Environment, error) {
-	if err := e.projectExists(); err != nil {
+	if err := e.projectExists(accessRead); err != nil {
 		return nil, err
 	}
 	env, err := e.storage().Get(code)
@@ -62,7 +61,7 @@ func (e *EnvironmentAPI) Create(info *api.EnvironmentInfo) (*domain.Environment,
 	code := info.Code
 	description := info.Description
 	protected := info.Protected
-	if err := e.projectExists(); err != nil {
+	if err := e.projectExists(accessWrite); err != nil {
 		return nil, err
 	}
 	if err := checkEnvParams(code, description, protected); err != nil {
@@ -75,10 +74,12 @@ func (e *EnvironmentAPI) Create(info *api.EnvironmentInfo) (*domain.Environment,
 		ProjectCode: e.ProjectCode,
 		Protected:   protected,
 		RegDate:     bson.Now().In(time.UTC),
+		Version:     1,
 	}
 	if err := e.storage().Save(newEnv); err != nil {
 		return nil, err
 	}
+	e.publish(domain.EventEnvironmentCreate, code, newEnv)
 	return newEnv, nil
 }
 
@@ -87,7 +88,7 @@ func (e *EnvironmentAPI) Update(info *api.EnvironmentInfo) (*domain.Environment,
 	code := info.Code
 	description := info.Description
 	protected := info.Protected
-	if err := e.projectExists(); err != nil {
+	if err := e.projectExists(accessWrite); err != nil {
 		return nil, err
 	}
 	if err := checkEnvParams(code, description, protected); err != nil {
@@ -97,6 +98,9 @@ func (e *EnvironmentAPI) Update(info *api.EnvironmentInfo) (*domain.Environment,
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(info.Version, uEnv.Version); err != nil {
+		return nil, err
+	}
 	newEnv := &domain.Environment{
 		Code:        code,
 		Description: description,
@@ -104,18 +108,27 @@ func (e *EnvironmentAPI) Update(info *api.EnvironmentInfo) (*domain.Environment,
 		ProjectCode: e.ProjectCode,
 		Protected:   protected,
 		RegDate:     uEnv.RegDate,
+		Version:     uEnv.Version,
 	}
 	if err := e.storage().Update(newEnv); err != nil {
-		return nil, err
+		return nil, updateError(err, api.ErrEnvironmentNotFound)
 	}
+	e.publish(domain.EventEnvironmentUpdate, code, newEnv)
 	return newEnv, nil
 }
 
 // Delete environment
 func (e *EnvironmentAPI) Delete(code domain.EnvironmentCode) error {
-	if err := e.projectExists(); err != nil {
+	if err := e.projectExists(accessWrite); err != nil {
+		return err
+	}
+	env, err := e.Get(code)
+	if err != nil {
 		return err
 	}
+	if env.Protected {
+		return api.ErrEnvironmentProtected
+	}
 	objList, err := e.For(code).Objects().List()
 	if err != nil {
 		return err
@@ -127,7 +140,20 @@ func (e *EnvironmentAPI) Delete(code domain.EnvironmentCode) error {
 	if err == storage.ErrNotFound {
 		return api.ErrEnvironmentNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	e.publish(domain.EventEnvironmentDelete, code, nil)
+	return nil
+}
+
+func (e *EnvironmentAPI) publish(eventType domain.EventType, code domain.EnvironmentCode, env *domain.Environment) {
+	event := e.ProjectAPI.newEvent(eventType, e.ProjectCode)
+	event.EnvCode = code
+	if env != nil {
+		event.Data = env
+	}
+	e.ProjectAPI.publish(event)
 }
 
 // For returns object api for specified environment
@@ -148,6 +174,7 @@ type ForObjectAPI struct {
 	EnvCode        domain.EnvironmentCode
 	Storage        *storage.DataStorage
 	EnvironmentAPI *EnvironmentAPI
+	Override       bool
 }
 
 // Objects returns ObjectAPI
@@ -158,5 +185,13 @@ func (fo *ForObjectAPI) Objects() api.ObjectAPI {
 		EnvCode:        fo.EnvCode,
 		Storage:        fo.Storage,
 		EnvironmentAPI: fo.EnvironmentAPI,
+		Override:       fo.Override,
 	}
 }
+
+// OverrideProtection returns api allowed to change objects of protected environment
+func (fo *ForObjectAPI) OverrideProtection() api.ForObjectAPI {
+	res := *fo
+	res.Override = true
+	return &res
+}
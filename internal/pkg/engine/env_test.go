@@ -3,8 +3,8 @@ package engine_test
 import (
 	"testing"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 
 	"github.com/Toggly/core/internal/pkg/storage"
 
@@ -150,16 +150,75 @@ func TestEnvWithProject(t *testing.T) {
 	assert.Nil(envU2)
 	assert.Equal(api.ErrEnvironmentNotFound, err)
 
-	envApi.For(envCode).Objects().Create(&api.ObjectInfo{
+	assert.Equal(api.ErrEnvironmentProtected, envApi.Delete(envCode))
+
+	_, err = envApi.For(envCode).Objects().Create(&api.ObjectInfo{
 		Code:        "obj1",
 		Description: "",
 	})
+	assert.Equal(api.ErrEnvironmentProtected, err)
+
+	_, err = envApi.For(envCode).OverrideProtection().Objects().Create(&api.ObjectInfo{
+		Code:        "obj1",
+		Description: "",
+	})
+	assert.Nil(err)
+
+	_, err = envApi.Update(&api.EnvironmentInfo{Code: envCode, Protected: false})
+	assert.Nil(err)
 
 	assert.Equal(api.ErrEnvironmentNotEmpty, envApi.Delete(envCode))
 
-	envApi.For(envCode).Objects().Delete(domain.ObjectCode("obj1"))
+	envApi.For(envCode).Objects().Delete(domain.ObjectCode("obj1"), 0)
 
 	assert.Nil(envApi.Delete(envCode))
 
 	AfterTest()
 }
+
+func TestEnvProtection(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode, Protected: true})
+
+	objApi := envApi.For(envCode).Objects()
+	overrideApi := envApi.For(envCode).OverrideProtection().Objects()
+
+	info := &api.ObjectInfo{
+		Code:       "obj1",
+		Parameters: []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: true}},
+	}
+
+	_, err := objApi.Create(info)
+	assert.Equal(api.ErrEnvironmentProtected, err)
+
+	_, err = overrideApi.Create(info)
+	assert.Nil(err)
+
+	_, err = objApi.Update(info)
+	assert.Equal(api.ErrEnvironmentProtected, err)
+
+	_, err = overrideApi.Update(info)
+	assert.Nil(err)
+
+	obj, err := objApi.Get("obj1")
+	assert.Nil(err)
+	assert.Equal(domain.ObjectCode("obj1"), obj.Code)
+
+	values, err := objApi.Evaluate("obj1", &domain.EvaluationContext{})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"param1": true}, values)
+
+	assert.Equal(api.ErrEnvironmentProtected, objApi.Delete("obj1", 0))
+	assert.Nil(overrideApi.Delete("obj1", 0))
+
+	assert.Equal(api.ErrEnvironmentProtected, envApi.Delete(envCode))
+
+	AfterTest()
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+// EventPublisher receives events of successful changes. Publish must not block the caller.
+type EventPublisher interface {
+	Publish(event *domain.Event)
+}
+
+// MultiPublisher returns publisher passing events to all the publishers
+func MultiPublisher(publishers ...EventPublisher) EventPublisher {
+	return multiPublisher(publishers)
+}
+
+type multiPublisher []EventPublisher
+
+func (m multiPublisher) Publish(event *domain.Event) {
+	for _, p := range m {
+		p.Publish(event)
+	}
+}
+
+// WebhookSender delivers the event to the webhook once and returns the delivery record
+type WebhookSender interface {
+	Deliver(hook *domain.Webhook, event *domain.Event) *domain.WebhookDelivery
+}
+
+// newEvent returns event of the owner change made by the actor
+func (o *OwnerAPI) newEvent(eventType domain.EventType, projectCode domain.ProjectCode) *domain.Event {
+	id, err := randomHex(12)
+	if err != nil {
+		log.Printf("[ERROR] Can't generate event id: %v", err)
+	}
+	return &domain.Event{
+		ID:          id,
+		Type:        eventType,
+		Owner:       o.Owner,
+		ProjectCode: projectCode,
+		Actor:       o.Actor,
+		Date:        bson.Now().In(time.UTC),
+	}
+}
+
+// publish passes the event to the publisher if it is configured
+func (o *OwnerAPI) publish(event *domain.Event) {
+	if o.Events != nil {
+		o.Events.Publish(event)
+	}
+}
+
+// inheritorsState returns effective state of the object inheritors to find out which of them
+// are changed by the object change. Nil is returned if nobody listens to events.
+func (o *ObjectAPI) inheritorsState(code domain.ObjectCode) map[domain.ObjectInheritance]*domain.Object {
+	if o.EnvironmentAPI.ProjectAPI.Events == nil {
+		return nil
+	}
+	inheritors, err := o.InheritorsFlatList(code)
+	if err != nil {
+		log.Printf("[ERROR] Can't list inheritors of object %s: %v", code, err)
+		return nil
+	}
+	state := make(map[domain.ObjectInheritance]*domain.Object, len(inheritors))
+	for _, inh := range inheritors {
+		if computed, err := o.getInherits(inh, nil); err == nil {
+			state[domain.ObjectInheritance{ProjectCode: inh.ProjectCode, EnvCode: inh.EnvCode, ObjectCode: inh.Code}] = computed
+		}
+	}
+	return state
+}
+
+// publishObject publishes object change. Update and revert of the object change effective
+// parameters of its inheritors, inheritors with changed parameters get update events as well.
+// Before is the inheritors state before the change.
+func (o *ObjectAPI) publishObject(eventType domain.EventType, code domain.ObjectCode, obj *domain.Object, before map[domain.ObjectInheritance]*domain.Object) {
+	p := o.EnvironmentAPI.ProjectAPI
+	if p.Events == nil {
+		return
+	}
+	event := p.newEvent(eventType, o.ProjectCode)
+	event.EnvCode = o.EnvCode
+	event.ObjectCode = code
+	if obj != nil {
+		event.Data = obj
+	}
+	p.publish(event)
+	if eventType != domain.EventObjectUpdate && eventType != domain.EventObjectRevert {
+		return
+	}
+	inheritors, err := o.InheritorsFlatList(code)
+	if err != nil {
+		log.Printf("[ERROR] Can't list inheritors of object %s: %v", code, err)
+		return
+	}
+	parent := &domain.ObjectInheritance{ProjectCode: o.ProjectCode, EnvCode: o.EnvCode, ObjectCode: code}
+	for _, inh := range inheritors {
+		computed, err := o.getInherits(inh, nil)
+		if err != nil {
+			log.Printf("[ERROR] Can't compute inheritor %s:%s:%s: %v", inh.ProjectCode, inh.EnvCode, inh.Code, err)
+			continue
+		}
+		key := domain.ObjectInheritance{ProjectCode: inh.ProjectCode, EnvCode: inh.EnvCode, ObjectCode: inh.Code}
+		if prev, ok := before[key]; ok && reflect.DeepEqual(prev.Parameters, computed.Parameters) {
+			continue
+		}
+		event := p.newEvent(domain.EventObjectUpdate, inh.ProjectCode)
+		event.EnvCode = inh.EnvCode
+		event.ObjectCode = inh.Code
+		event.Parent = parent
+		event.Data = computed
+		p.publish(event)
+	}
+}
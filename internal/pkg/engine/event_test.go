@@ -0,0 +1,153 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+type eventRecorder struct {
+	events []*domain.Event
+}
+
+func (r *eventRecorder) Publish(event *domain.Event) {
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) types() []domain.EventType {
+	res := make([]domain.EventType, 0, len(r.events))
+	for _, e := range r.events {
+		res = append(res, e.Type)
+	}
+	return res
+}
+
+func (r *eventRecorder) reset() {
+	r.events = nil
+}
+
+func TestEvents(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	events := &eventRecorder{}
+	togglyAPI := &engine.Engine{Storage: &dataStorage, DisabledProjectMode: engine.DisabledProjectReadOnly, Events: events}
+	pApi := togglyAPI.WithActor("key_1").ForOwner(ow).Projects()
+	envApi := pApi.For(ProjectCode).Environments()
+	segApi := pApi.For(ProjectCode).Segments()
+	objApi := envApi.For(envCode).Objects()
+
+	_, err := pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	_, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Description: "changed", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	_, err = envApi.Create(&api.EnvironmentInfo{Code: envCode})
+	assert.Nil(err)
+	_, err = segApi.Create(&api.SegmentInfo{Code: "beta", Include: []string{"u1"}})
+	assert.Nil(err)
+	assert.Nil(segApi.Delete("beta"))
+
+	assert.Equal([]domain.EventType{
+		domain.EventProjectCreate,
+		domain.EventProjectUpdate,
+		domain.EventEnvironmentCreate,
+		domain.EventSegmentCreate,
+		domain.EventSegmentDelete,
+	}, events.types())
+
+	e := events.events[1]
+	assert.NotEmpty(e.ID)
+	assert.Equal(ow, e.Owner)
+	assert.Equal(ProjectCode, e.ProjectCode)
+	assert.Equal("key_1", e.Actor)
+	assert.False(e.Date.IsZero())
+	assert.Equal("changed", e.Data.(*domain.Project).Description)
+	assert.Equal(envCode, events.events[2].EnvCode)
+	assert.Equal(domain.SegmentCode("beta"), events.events[3].SegmentCode)
+	assert.Nil(events.events[4].Data)
+	assert.NotEqual(events.events[0].ID, events.events[1].ID)
+
+	events.reset()
+	_, err = envApi.Create(&api.EnvironmentInfo{Code: envCode})
+	assert.NotNil(err)
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Parameters: boolParam(true)})
+	assert.Equal(api.ErrObjectNotFound, err)
+	assert.Empty(events.events, "failed changes are not published")
+
+	AfterTest()
+}
+
+func TestObjectEvents(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	events := &eventRecorder{}
+	togglyAPI := &engine.Engine{Storage: &dataStorage, DisabledProjectMode: engine.DisabledProjectReadOnly, Events: events}
+	pApi := togglyAPI.ForOwner(ow).Projects()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+	parent := &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: objCode}
+
+	_, err := objApi.Create(&api.ObjectInfo{Code: objCode, Parameters: boolParam(false)})
+	assert.Nil(err)
+	_, err = objApi.Create(&api.ObjectInfo{Code: "child", Inherits: parent})
+	assert.Nil(err)
+	_, err = objApi.Create(&api.ObjectInfo{Code: "grandchild", Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "child"}})
+	assert.Nil(err)
+
+	events.reset()
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Parameters: boolParam(true)})
+	assert.Nil(err)
+	assert.Equal([]domain.EventType{domain.EventObjectUpdate, domain.EventObjectUpdate, domain.EventObjectUpdate}, events.types())
+	assert.Equal(objCode, events.events[0].ObjectCode)
+	assert.Nil(events.events[0].Parent)
+	assert.Equal(domain.ObjectCode("child"), events.events[1].ObjectCode)
+	assert.Equal(parent, events.events[1].Parent)
+	assert.Equal(domain.ObjectCode("grandchild"), events.events[2].ObjectCode)
+	assert.Equal(parent, events.events[2].Parent)
+	child := events.events[2].Data.(*domain.Object)
+	assert.Equal(true, child.Parameters[0].Value, "inheritor data has effective parameters")
+
+	events.reset()
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Description: "changed", Parameters: boolParam(true)})
+	assert.Nil(err)
+	assert.Equal([]domain.EventType{domain.EventObjectUpdate}, events.types(), "inheritors parameters are not changed")
+
+	events.reset()
+	_, err = objApi.Update(&api.ObjectInfo{Code: "child", Inherits: parent, Parameters: boolParam(false)})
+	assert.Nil(err)
+	assert.Equal([]domain.EventType{domain.EventObjectUpdate, domain.EventObjectUpdate}, events.types())
+	assert.Equal(domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "child"}, *events.events[1].Parent)
+
+	events.reset()
+	_, err = objApi.Revert(objCode, 1)
+	assert.Nil(err)
+	assert.Equal([]domain.EventType{domain.EventObjectRevert}, events.types(), "child overrides the parameter")
+	_, err = objApi.Revert("child", 1)
+	assert.Nil(err)
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Parameters: boolParam(true)})
+	assert.Nil(err)
+
+	events.reset()
+	_, err = objApi.Revert(objCode, 1)
+	assert.Nil(err)
+	assert.Equal([]domain.EventType{domain.EventObjectRevert, domain.EventObjectUpdate, domain.EventObjectUpdate}, events.types())
+
+	events.reset()
+	assert.Nil(objApi.Delete("grandchild", 0))
+	assert.Equal([]domain.EventType{domain.EventObjectDelete}, events.types())
+	assert.Equal(domain.ObjectCode("grandchild"), events.events[0].ObjectCode)
+	assert.Equal(envCode, events.events[0].EnvCode)
+	assert.Nil(events.events[0].Data)
+
+	AfterTest()
+}
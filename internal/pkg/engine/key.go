@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	keyIDSize     = 8
+	keySecretSize = 32
+)
+
+func randomHex(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// Authenticate resolves api key to the key owner
+func (e *Engine) Authenticate(key string) (*domain.APIKey, error) {
+	if key == "" {
+		return nil, api.ErrUnauthorized
+	}
+	k, err := (*e.Storage).Keys().GetByHash(hashKey(key))
+	if err == storage.ErrNotFound {
+		return nil, api.ErrUnauthorized
+	}
+	return k, err
+}
+
+// KeyAPI servers owner api keys
+type KeyAPI struct {
+	OwnerAPI
+}
+
+func (k *KeyAPI) storage() storage.KeyStorage {
+	return (*k.Storage).ForOwner(k.Owner).Keys()
+}
+
+// List returns list of api keys
+func (k *KeyAPI) List() ([]*domain.APIKey, error) {
+	return k.storage().List()
+}
+
+// Get returns api key by id
+func (k *KeyAPI) Get(id domain.APIKeyID) (*domain.APIKey, error) {
+	key, err := k.storage().Get(id)
+	if err == storage.ErrNotFound {
+		return nil, api.ErrKeyNotFound
+	}
+	return key, err
+}
+
+func checkKeyParams(info *api.KeyInfo) error {
+	if !info.Role.Valid() {
+		return api.NewBadRequestError(fmt.Sprintf("Key role can be `%s`, `%s`, `%s` or `%s`",
+			domain.RoleEvaluate, domain.RoleReadOnly, domain.RoleEditor, domain.RoleAdmin))
+	}
+	if info.EnvironmentCode != "" && info.ProjectCode == "" {
+		return api.NewBadRequestError("Key environment scope requires project code")
+	}
+	return nil
+}
+
+// Create generates new api key. The key value is returned only once.
+func (k *KeyAPI) Create(info *api.KeyInfo) (*domain.APIKey, error) {
+	if err := checkKeyParams(info); err != nil {
+		return nil, err
+	}
+	id, err := randomHex(keyIDSize)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(keySecretSize)
+	if err != nil {
+		return nil, err
+	}
+	newKey := &domain.APIKey{
+		ID:              domain.APIKeyID(id),
+		OwnerID:         k.Owner,
+		Description:     info.Description,
+		Role:            info.Role,
+		ProjectCode:     info.ProjectCode,
+		EnvironmentCode: info.EnvironmentCode,
+		Hash:            hashKey(secret),
+		RegDate:         bson.Now().In(time.UTC),
+	}
+	if err := k.storage().Save(newKey); err != nil {
+		return nil, err
+	}
+	newKey.Key = secret
+	return newKey, nil
+}
+
+// Revoke deletes api key
+func (k *KeyAPI) Revoke(id domain.APIKeyID) error {
+	err := k.storage().Delete(id)
+	if err == storage.ErrNotFound {
+		return api.ErrKeyNotFound
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	togglyAPI := GetEngine(engine.DisabledProjectReadOnly)
+	keyAPI := togglyAPI.ForOwner(ow).Keys()
+
+	list, err := keyAPI.List()
+	assert.Nil(err)
+	assert.Empty(list)
+
+	_, err = keyAPI.Create(&api.KeyInfo{Role: "owner"})
+	assert.IsType(&api.ErrBadRequest{}, err)
+	_, err = keyAPI.Create(&api.KeyInfo{Role: domain.RoleEditor, EnvironmentCode: "dev"})
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	scoped, err := keyAPI.Create(&api.KeyInfo{Role: domain.RoleEditor, ProjectCode: ProjectCode, EnvironmentCode: "dev"})
+	assert.Nil(err)
+	assert.Equal(domain.RoleEditor, scoped.Role)
+	assert.Equal(ProjectCode, scoped.ProjectCode)
+	assert.Equal(domain.EnvironmentCode("dev"), scoped.EnvironmentCode)
+	assert.Nil(keyAPI.Revoke(scoped.ID))
+
+	key, err := keyAPI.Create(&api.KeyInfo{Description: "ci", Role: domain.RoleAdmin})
+	assert.Nil(err)
+	assert.NotEmpty(key.ID)
+	assert.NotEmpty(key.Key)
+	assert.NotEqual(key.Key, key.Hash)
+	assert.Equal(ow, key.OwnerID)
+
+	stored, err := keyAPI.Get(key.ID)
+	assert.Nil(err)
+	assert.Empty(stored.Key)
+	assert.Equal(key.Hash, stored.Hash)
+
+	other, err := togglyAPI.ForOwner("another_owner").Keys().List()
+	assert.Nil(err)
+	assert.Empty(other)
+
+	authKey, err := togglyAPI.Authenticate(key.Key)
+	assert.Nil(err)
+	assert.Equal(key.ID, authKey.ID)
+	assert.Equal(ow, authKey.OwnerID)
+
+	_, err = togglyAPI.Authenticate(key.Hash)
+	assert.Equal(api.ErrUnauthorized, err)
+	_, err = togglyAPI.Authenticate("")
+	assert.Equal(api.ErrUnauthorized, err)
+
+	assert.Nil(keyAPI.Revoke(key.ID))
+	assert.Equal(api.ErrKeyNotFound, keyAPI.Revoke(key.ID))
+	_, err = keyAPI.Get(key.ID)
+	assert.Equal(api.ErrKeyNotFound, err)
+
+	_, err = togglyAPI.Authenticate(key.Key)
+	assert.Equal(api.ErrUnauthorized, err)
+
+	AfterTest()
+}
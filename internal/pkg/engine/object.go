@@ -2,10 +2,11 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 // ObjectAPI servers object api namespace
@@ -15,6 +16,7 @@ type ObjectAPI struct {
 	EnvCode        domain.EnvironmentCode
 	Storage        *storage.DataStorage
 	EnvironmentAPI *EnvironmentAPI
+	Override       bool
 }
 
 func (o *ObjectAPI) envExists() error {
@@ -22,11 +24,41 @@ func (o *ObjectAPI) envExists() error {
 	return err
 }
 
+// checkProtection denies changing objects of disabled project
+// and protected environment without override permission
+func (o *ObjectAPI) checkProtection() error {
+	if err := o.EnvironmentAPI.projectExists(accessWrite); err != nil {
+		return err
+	}
+	env, err := o.EnvironmentAPI.Get(o.EnvCode)
+	if err != nil {
+		return err
+	}
+	if env.Protected && !o.Override {
+		return api.ErrEnvironmentProtected
+	}
+	return nil
+}
+
 func (o *ObjectAPI) storage() storage.ObjectStorage {
 	return (*o.Storage).ForOwner(o.Owner).Projects().For(o.ProjectCode).Environments().For(o.EnvCode).Objects()
 }
 
-func (o *ObjectAPI) getParentObject(parent *domain.ObjectInheritance) (*domain.Object, error) {
+// parentsCache keeps parent objects loaded while serving a single request
+type parentsCache map[domain.ObjectInheritance]*domain.Object
+
+func (o *ObjectAPI) getParentObject(parent *domain.ObjectInheritance, cache parentsCache) (*domain.Object, error) {
+	if obj, ok := cache[*parent]; ok {
+		return obj, nil
+	}
+	obj, err := o.loadParentObject(parent)
+	if err == nil && cache != nil {
+		cache[*parent] = obj
+	}
+	return obj, err
+}
+
+func (o *ObjectAPI) loadParentObject(parent *domain.ObjectInheritance) (*domain.Object, error) {
 	_, err := (*o.Storage).ForOwner(o.Owner).Projects().Get(parent.ProjectCode)
 	if err == storage.ErrNotFound {
 		return nil, api.ErrProjectNotFound
@@ -42,16 +74,16 @@ func (o *ObjectAPI) getParentObject(parent *domain.ObjectInheritance) (*domain.O
 	return obj, err
 }
 
-func (o *ObjectAPI) getInherits(obj *domain.Object) (*domain.Object, error) {
+func (o *ObjectAPI) getInherits(obj *domain.Object, cache parentsCache) (*domain.Object, error) {
 	if obj.Inherits == nil {
 		return obj, nil
 	}
-	iObj, err := o.getParentObject(obj.Inherits)
+	iObj, err := o.getParentObject(obj.Inherits, cache)
 	if err != nil {
 		return nil, err
 	}
 	if iObj.Inherits != nil {
-		iObj, err = o.getInherits(iObj)
+		iObj, err = o.getInherits(iObj, cache)
 		if err != nil {
 			return nil, err
 		}
@@ -64,12 +96,21 @@ func (o *ObjectAPI) getInherits(obj *domain.Object) (*domain.Object, error) {
 				if ip.Type != p.Type {
 					return nil, api.ErrObjectInheritorTypeMismatch
 				}
-				resultParams = append(resultParams, &domain.Parameter{
-					Code:        ip.Code,
-					Description: ip.Description,
-					Type:        ip.Type,
-					Value:       p.Value,
-				})
+				np, err := normalizeParameter(ip, p)
+				if err != nil {
+					return nil, err
+				}
+				rp := *ip
+				rp.Value = np.Value
+				rp.ClearRules = np.ClearRules
+				rp.ClearRollout = np.ClearRollout
+				if len(np.Rules) > 0 || np.ClearRules {
+					rp.Rules = np.Rules
+				}
+				if np.Rollout != nil || np.ClearRollout {
+					rp.Rollout = np.Rollout
+				}
+				resultParams = append(resultParams, &rp)
 			}
 		}
 	}
@@ -85,6 +126,7 @@ func (o *ObjectAPI) getInherits(obj *domain.Object) (*domain.Object, error) {
 		Inherits:    obj.Inherits,
 		Owner:       obj.Owner,
 		Parameters:  resultParams,
+		Version:     obj.Version,
 	}, nil
 }
 
@@ -115,9 +157,13 @@ func (o *ObjectAPI) List() (objects []*domain.Object, err error) {
 	if err != nil {
 		return nil, err
 	}
+	cache := make(parentsCache, len(objList))
+	for _, obj := range objList {
+		cache[domain.ObjectInheritance{ProjectCode: o.ProjectCode, EnvCode: o.EnvCode, ObjectCode: obj.Code}] = obj
+	}
 	computedObjects := make([]*domain.Object, len(objList))
 	for i, obj := range objList {
-		obj, e := o.getInherits(obj)
+		obj, e := o.getInherits(obj, cache)
 		if e != nil {
 			return nil, e
 		}
@@ -135,13 +181,132 @@ func (o *ObjectAPI) Get(code domain.ObjectCode) (obj *domain.Object, err error)
 	if err == storage.ErrNotFound {
 		return nil, api.ErrObjectNotFound
 	}
-	obj, err = o.getInherits(obj)
+	obj, err = o.getInherits(obj, nil)
 	if err != nil {
 		return nil, err
 	}
 	return obj, nil
 }
 
+// Evaluate returns object parameter values for the context
+func (o *ObjectAPI) Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error) {
+	if err := o.EnvironmentAPI.projectExists(accessEvaluate); err != nil {
+		return nil, err
+	}
+	obj, err := o.Get(code)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := ObjectSegments(o.segments(), obj)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(obj, segments, ctx), nil
+}
+
+// EvaluateAll returns parameter values of all environment objects for the context.
+// Objects are loaded once and reused as parents while resolving inheritance.
+func (o *ObjectAPI) EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	if err := o.EnvironmentAPI.projectExists(accessEvaluate); err != nil {
+		return nil, err
+	}
+	objList, err := o.List()
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateAll(objList, o.segments(), ctx)
+}
+
+func (o *ObjectAPI) segments() api.SegmentAPI {
+	return o.EnvironmentAPI.ProjectAPI.For(o.ProjectCode).Segments()
+}
+
+func (o *ObjectAPI) checkSegments(parameters []*domain.Parameter) error {
+	for _, p := range parameters {
+		for i, r := range p.Rules {
+			if r.Segment == "" {
+				continue
+			}
+			_, err := o.segments().Get(r.Segment)
+			if err == api.ErrSegmentNotFound {
+				return api.NewObjectParameterError(string(p.Code), fmt.Sprintf("Rule %d: segment `%s` not found", i+1, r.Segment))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// segmentRule returns segment the first parameter rule with segment refers to
+func segmentRule(p *domain.Parameter) domain.SegmentCode {
+	for _, r := range p.Rules {
+		if r != nil && r.Segment != "" {
+			return r.Segment
+		}
+	}
+	return ""
+}
+
+// ownRules reports whether the object defines rules of the parameter instead of inheriting them
+func ownRules(obj *domain.Object, code domain.ParameterCode) bool {
+	for _, p := range obj.Parameters {
+		if p.Code == code && (len(p.Rules) > 0 || p.ClearRules) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInheritedSegments denies inheriting rules with segments from other project, segments are looked up
+// in the object project. Inheritor has to define own rules of such parameters.
+func (o *ObjectAPI) checkInheritedSegments(parent *domain.Object, inherits *domain.ObjectInheritance, parameters []*domain.Parameter) error {
+	if parent == nil || inherits.ProjectCode == o.ProjectCode {
+		return nil
+	}
+	obj := &domain.Object{Parameters: parameters}
+	for _, p := range parent.Parameters {
+		if segment := segmentRule(p); segment != "" && !ownRules(obj, p.Code) {
+			msg := fmt.Sprintf("Rules with segment `%s` can't be inherited from other project", segment)
+			return api.NewObjectParameterError(string(p.Code), msg)
+		}
+	}
+	return nil
+}
+
+// checkInheritorsSegments denies rules with segments which inheritors from other projects would inherit
+func (o *ObjectAPI) checkInheritorsSegments(code domain.ObjectCode, parameters []*domain.Parameter) error {
+	inheritors, err := o.InheritorsFlatList(code)
+	if err != nil {
+		return err
+	}
+	self := domain.ObjectInheritance{ProjectCode: o.ProjectCode, EnvCode: o.EnvCode, ObjectCode: code}
+	refs := make(map[domain.ObjectInheritance]*domain.Object, len(inheritors))
+	for _, inh := range inheritors {
+		refs[domain.ObjectInheritance{ProjectCode: inh.ProjectCode, EnvCode: inh.EnvCode, ObjectCode: inh.Code}] = inh
+	}
+	for _, p := range parameters {
+		segment := segmentRule(p)
+		if segment == "" {
+			continue
+		}
+		for _, inh := range inheritors {
+			if inh.ProjectCode == o.ProjectCode {
+				continue
+			}
+			// rules are inherited unless the inheritor or an object between them defines own ones
+			for obj := inh; obj != nil && !ownRules(obj, p.Code); obj = refs[*obj.Inherits] {
+				if *obj.Inherits == self {
+					msg := fmt.Sprintf("Rules with segment `%s` would be inherited by %s:%s:%s", segment, inh.ProjectCode, inh.EnvCode, inh.Code)
+					return api.NewObjectParameterError(string(p.Code), msg)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func checkObjParams(code domain.ObjectCode, description string, inherits *domain.ObjectInheritance, parameters []*domain.Parameter) error {
 	if code == "" {
 		return api.NewBadRequestError("Object code not specified")
@@ -163,27 +328,58 @@ func checkObjParams(code domain.ObjectCode, description string, inherits *domain
 }
 
 func isParameterType(t domain.ParameterType) bool {
-	return t == domain.ParameterBool || t == domain.ParameterEnum || t == domain.ParameterInt || t == domain.ParameterString
+	switch t {
+	case domain.ParameterBool,
+		domain.ParameterString,
+		domain.ParameterInt,
+		domain.ParameterEnum,
+		domain.ParameterFloat,
+		domain.ParameterJSON,
+		domain.ParameterList,
+		domain.ParameterDuration,
+		domain.ParameterSemver:
+		return true
+	}
+	return false
 }
 
 // Create object
 func (o *ObjectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
+	obj, err := o.create(info)
+	if err != nil {
+		return nil, err
+	}
+	o.record(domain.RevisionCreate, obj.Code, nil, 0)
+	o.publishObject(domain.EventObjectCreate, obj.Code, obj, nil)
+	return obj, nil
+}
+
+func (o *ObjectAPI) create(info *api.ObjectInfo) (*domain.Object, error) {
 	code := info.Code
 	description := info.Description
 	inherits := info.Inherits
 	parameters := info.Parameters
-	if err := o.envExists(); err != nil {
+	if err := o.checkProtection(); err != nil {
 		return nil, err
 	}
 	if err := checkObjParams(code, description, inherits, parameters); err != nil {
 		return nil, err
 	}
+	parameters, err := normalizeParameters(parameters)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.checkSegments(parameters); err != nil {
+		return nil, err
+	}
 	var parent *domain.Object
-	var err error
 	if parent, err = o.checkInheritance(inherits); err != nil {
 		return nil, err
 	}
-	if err := o.checkParametersInheritanceForParent(parent, parameters); err != nil {
+	if err := o.checkParametersInheritanceForParent(parent, nil, parameters); err != nil {
+		return nil, err
+	}
+	if err := o.checkInheritedSegments(parent, inherits, parameters); err != nil {
 		return nil, err
 	}
 	newObj := &domain.Object{
@@ -194,6 +390,7 @@ func (o *ObjectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
 		Description: description,
 		Inherits:    inherits,
 		Parameters:  parameters,
+		Version:     1,
 	}
 	if err := o.storage().Save(newObj); err != nil {
 		return nil, err
@@ -203,20 +400,42 @@ func (o *ObjectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
 
 // Update object
 func (o *ObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
+	old, _ := o.storage().Get(info.Code)
+	inheritors := o.inheritorsState(info.Code)
+	obj, err := o.update(info)
+	if err != nil {
+		return nil, err
+	}
+	o.record(domain.RevisionUpdate, obj.Code, old, 0)
+	o.publishObject(domain.EventObjectUpdate, obj.Code, obj, inheritors)
+	return obj, nil
+}
+
+func (o *ObjectAPI) update(info *api.ObjectInfo) (*domain.Object, error) {
 	code := info.Code
 	description := info.Description
 	inherits := info.Inherits
 	parameters := info.Parameters
-	if err := o.envExists(); err != nil {
+	if err := o.checkProtection(); err != nil {
 		return nil, err
 	}
 	if err := checkObjParams(code, description, inherits, parameters); err != nil {
 		return nil, err
 	}
+	parameters, err := normalizeParameters(parameters)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.checkSegments(parameters); err != nil {
+		return nil, err
+	}
 	obj, err := o.Get(code)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(info.Version, obj.Version); err != nil {
+		return nil, err
+	}
 	if err := o.checkIfParametersChanged(obj, parameters); err != nil {
 		return nil, err
 	}
@@ -224,7 +443,13 @@ func (o *ObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
 	if parent, err = o.checkInheritance(inherits); err != nil {
 		return nil, err
 	}
-	if err := o.checkParametersInheritanceForParent(parent, parameters); err != nil {
+	if err := o.checkParametersInheritanceForParent(parent, obj, parameters); err != nil {
+		return nil, err
+	}
+	if err := o.checkInheritedSegments(parent, inherits, parameters); err != nil {
+		return nil, err
+	}
+	if err := o.checkInheritorsSegments(code, parameters); err != nil {
 		return nil, err
 	}
 	newObj := &domain.Object{
@@ -235,25 +460,41 @@ func (o *ObjectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
 		Description: description,
 		Inherits:    inherits,
 		Parameters:  parameters,
+		Version:     obj.Version,
 	}
 	if err := o.storage().Update(newObj); err != nil {
-		return nil, err
+		return nil, updateError(err, api.ErrObjectNotFound)
 	}
 	return o.Get(code)
 }
 
 // InheritorsFlatList returns flat list of inheritors
 func (o *ObjectAPI) InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error) {
-	list, err := o.storage().ListInheritors(code)
-	if err != nil {
-		return nil, err
-	}
-	for _, i := range list {
-		sub, err := o.InheritorsFlatList(i.Code)
+	return listInheritors((*o.Storage).ForOwner(o.Owner).Projects(), o.ProjectCode, o.EnvCode, code)
+}
+
+// listInheritors returns inheritors of the object and their inheritors,
+// every inheritor is looked up in its own project and environment and listed once
+func listInheritors(projects storage.ProjectStorage, project domain.ProjectCode, env domain.EnvironmentCode, code domain.ObjectCode) ([]*domain.Object, error) {
+	list := make([]*domain.Object, 0)
+	visited := map[domain.ObjectInheritance]bool{{ProjectCode: project, EnvCode: env, ObjectCode: code}: true}
+	queue := []domain.ObjectInheritance{{ProjectCode: project, EnvCode: env, ObjectCode: code}}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		inheritors, err := projects.For(next.ProjectCode).Environments().For(next.EnvCode).Objects().ListInheritors(next.ObjectCode)
 		if err != nil {
 			return nil, err
 		}
-		list = append(list, sub...)
+		for _, i := range inheritors {
+			key := domain.ObjectInheritance{ProjectCode: i.ProjectCode, EnvCode: i.EnvCode, ObjectCode: i.Code}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			list = append(list, i)
+			queue = append(queue, key)
+		}
 	}
 	return list, nil
 }
@@ -274,8 +515,24 @@ func (o *ObjectAPI) checkIfParametersChanged(obj *domain.Object, parameters []*d
 			if par.Type != p.Type {
 				return api.NewObjectParameterError(string(par.Code), "Object parameter type changing restricted")
 			}
+			// 2. Deny changing the parameter definition if inheritors values don't match it
+			violators := make([]string, 0)
+			for _, inh := range inheritors {
+				for _, ip := range inh.Parameters {
+					if ip.Code != p.Code {
+						continue
+					}
+					if _, err := normalizeParameter(p, ip); err != nil {
+						violators = append(violators, fmt.Sprintf("%s:%s:%s", inh.ProjectCode, inh.EnvCode, inh.Code))
+					}
+				}
+			}
+			if len(violators) > 0 {
+				msg := fmt.Sprintf("Object parameter is not valid for inheritors: %s", strings.Join(violators, ", "))
+				return api.NewObjectParameterError(string(p.Code), msg)
+			}
 		} else {
-			// 2. Deny creating the parameter if inheritors already have it
+			// 3. Deny creating the parameter if inheritors already have it
 			for _, inh := range inheritors {
 				for _, ip := range inh.Parameters {
 					if ip.Code == p.Code {
@@ -293,7 +550,7 @@ func (o *ObjectAPI) checkInheritance(inherits *domain.ObjectInheritance) (*domai
 	if inherits == nil {
 		return nil, nil
 	}
-	obj, err := o.getParentObject(inherits)
+	obj, err := o.getParentObject(inherits, nil)
 	if err != nil {
 		switch err {
 		case api.ErrProjectNotFound:
@@ -306,26 +563,62 @@ func (o *ObjectAPI) checkInheritance(inherits *domain.ObjectInheritance) (*domai
 			return nil, err
 		}
 	}
-	return obj, nil
+	return o.getInherits(obj, nil)
 }
 
-func (o *ObjectAPI) checkParametersInheritanceForParent(parent *domain.Object, parameters []*domain.Parameter) error {
-	if parent == nil || len(parameters) == 0 {
-		return nil
+// unchangedEnum reports whether the current object has enum parameter without allowed values,
+// such parameters were saved before allowed values became required and are kept unchanged
+func unchangedEnum(current *domain.Object, code domain.ParameterCode) bool {
+	if current == nil {
+		return false
+	}
+	for _, p := range current.Parameters {
+		if p.Code == code {
+			return p.Type == domain.ParameterEnum && len(p.AllowedValues) == 0
+		}
 	}
+	return false
+}
+
+func (o *ObjectAPI) checkParametersInheritanceForParent(parent, current *domain.Object, parameters []*domain.Parameter) error {
 	for _, p := range parameters {
-		for _, pp := range parent.Parameters {
-			if p.Code == pp.Code && p.Type != pp.Type {
-				return api.ErrObjectInheritorTypeMismatch
+		var def *domain.Parameter
+		if parent != nil {
+			for _, pp := range parent.Parameters {
+				if p.Code == pp.Code {
+					def = pp
+					break
+				}
+			}
+		}
+		if def == nil {
+			if p.Type == domain.ParameterEnum && len(p.AllowedValues) == 0 && !unchangedEnum(current, p.Code) {
+				return api.NewObjectParameterError(string(p.Code), "Enum allowed values not specified")
 			}
+			continue
+		}
+		if p.Type != def.Type {
+			return api.ErrObjectInheritorTypeMismatch
+		}
+		if len(p.AllowedValues) > 0 {
+			return api.NewObjectParameterError(string(p.Code), "Enum allowed values are defined by parent object")
+		}
+		if len(p.Schema) > 0 {
+			return api.NewObjectParameterError(string(p.Code), "Schema is defined by parent object")
+		}
+		if p.Constraints != nil {
+			return api.NewObjectParameterError(string(p.Code), "Constraints are defined by parent object")
+		}
+		if _, err := normalizeParameter(def, p); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// Delete object
-func (o *ObjectAPI) Delete(code domain.ObjectCode) (err error) {
-	if err := o.envExists(); err != nil {
+// Delete object, it is deleted only if stored version is not changed since it was checked
+func (o *ObjectAPI) Delete(code domain.ObjectCode, version int64) (err error) {
+	if err := o.checkProtection(); err != nil {
 		return err
 	}
 	inheritors, err := o.storage().ListInheritors(code)
@@ -335,9 +628,17 @@ func (o *ObjectAPI) Delete(code domain.ObjectCode) (err error) {
 	if len(inheritors) > 0 {
 		return api.ErrObjectHasInheritors
 	}
-	err = o.storage().Delete(code)
-	if err == storage.ErrNotFound {
-		return api.ErrObjectNotFound
+	old, err := o.storage().Get(code)
+	if err != nil {
+		return updateError(err, api.ErrObjectNotFound)
 	}
-	return err
+	if err := checkVersion(version, old.Version); err != nil {
+		return err
+	}
+	if err := o.storage().Delete(code, old.Version); err != nil {
+		return updateError(err, api.ErrObjectNotFound)
+	}
+	o.record(domain.RevisionDelete, code, old, 0)
+	o.publishObject(domain.EventObjectDelete, code, nil, nil)
+	return nil
 }
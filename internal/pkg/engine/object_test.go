@@ -3,10 +3,10 @@ package engine_test
 import (
 	"testing"
 
-	"github.com/Toggly/core/internal/api"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
 
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/domain"
 
 	asserts "github.com/stretchr/testify/assert"
 )
@@ -37,7 +37,7 @@ func TestObjectsWithNoProject(t *testing.T) {
 	assert.Equal(api.ErrProjectNotFound, err)
 	assert.Nil(env)
 
-	err = objApi.Delete(objCode)
+	err = objApi.Delete(objCode, 0)
 	assert.Equal(api.ErrProjectNotFound, err)
 
 	AfterTest()
@@ -69,7 +69,7 @@ func TestObjectsWithNoEnvironment(t *testing.T) {
 	assert.Equal(api.ErrEnvironmentNotFound, err)
 	assert.Nil(env)
 
-	err = objApi.Delete(objCode)
+	err = objApi.Delete(objCode, 0)
 	assert.Equal(api.ErrEnvironmentNotFound, err)
 
 	AfterTest()
@@ -100,7 +100,7 @@ func TestObjectsCreateErrors(t *testing.T) {
 	assert.Equal(api.ErrObjectNotFound, err)
 	assert.Nil(obj)
 
-	err = objApi.Delete("obj1")
+	err = objApi.Delete("obj1", 0)
 	assert.Equal(api.ErrObjectNotFound, err)
 
 	objApi.Create(&api.ObjectInfo{
@@ -280,18 +280,77 @@ func TestObjectsDeleteWithInheritors(t *testing.T) {
 	})
 	assert.Nil(err)
 
-	err = objApi.Delete("obj1")
+	err = objApi.Delete("obj1", 0)
 	assert.Equal(api.ErrObjectHasInheritors, err)
 
-	err = objApi.Delete("obj2")
+	err = objApi.Delete("obj2", 0)
 	assert.Nil(err)
 
-	err = objApi.Delete("obj1")
+	err = objApi.Delete("obj1", 0)
 	assert.Nil(err)
 
 	AfterTest()
 }
 
+func TestObjectsVersion(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	proj, err := pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	assert.Equal(int64(1), proj.Version)
+	proj, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive, Version: 1})
+	assert.Nil(err)
+	assert.Equal(int64(2), proj.Version)
+	_, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive, Version: 1})
+	assert.Equal(api.ErrVersionConflict, err)
+
+	env, err := envApi.Create(&api.EnvironmentInfo{Code: envCode})
+	assert.Nil(err)
+	assert.Equal(int64(1), env.Version)
+	_, err = envApi.Update(&api.EnvironmentInfo{Code: envCode, Version: 2})
+	assert.Equal(api.ErrVersionConflict, err)
+	env, err = envApi.Update(&api.EnvironmentInfo{Code: envCode, Description: "changed"})
+	assert.Nil(err)
+	assert.Equal(int64(2), env.Version)
+
+	obj, err := objApi.Create(&api.ObjectInfo{Code: objCode})
+	assert.Nil(err)
+	assert.Equal(int64(1), obj.Version)
+	obj, err = objApi.Update(&api.ObjectInfo{Code: objCode, Description: "first", Version: 1})
+	assert.Nil(err)
+	assert.Equal(int64(2), obj.Version)
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Description: "stale", Version: 1})
+	assert.Equal(api.ErrVersionConflict, err)
+	obj, err = objApi.Update(&api.ObjectInfo{Code: objCode, Description: "second"})
+	assert.Nil(err)
+	assert.Equal(int64(3), obj.Version)
+
+	obj, err = objApi.Get(objCode)
+	assert.Nil(err)
+	assert.Equal("second", obj.Description)
+	assert.Equal(int64(3), obj.Version)
+
+	inherits := &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: objCode}
+	child, err := objApi.Create(&api.ObjectInfo{Code: "child", Inherits: inherits})
+	assert.Nil(err)
+	assert.Equal(int64(1), child.Version)
+	child, err = objApi.Update(&api.ObjectInfo{Code: "child", Description: "child", Inherits: inherits, Version: 1})
+	assert.Nil(err)
+	assert.Equal(int64(2), child.Version)
+
+	assert.Equal(api.ErrVersionConflict, objApi.Delete("child", 1))
+	assert.Nil(objApi.Delete("child", 2))
+	assert.Equal(api.ErrObjectNotFound, objApi.Delete("child", 2))
+
+	AfterTest()
+}
+
 func TestObjectsInheritance(t *testing.T) {
 	assert := asserts.New(t)
 
@@ -435,6 +494,51 @@ func TestObjectsInheritance(t *testing.T) {
 
 }
 
+func TestObjectsInheritorsOtherEnvironments(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	for _, env := range []domain.EnvironmentCode{"dev", "prod", "stage"} {
+		envApi.Create(&api.EnvironmentInfo{Code: env})
+	}
+	enum := func(allowed ...string) []*domain.Parameter {
+		return []*domain.Parameter{{Code: "mode", Type: domain.ParameterEnum, Value: "a", AllowedValues: allowed}}
+	}
+
+	flag, err := envApi.For("dev").Objects().Create(&api.ObjectInfo{Code: "flag", Parameters: enum("a", "b", "c")})
+	assert.Nil(err)
+	_, err = envApi.For("prod").Objects().Create(&api.ObjectInfo{
+		Code:     "flag",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: "dev", ObjectCode: "flag"},
+	})
+	assert.Nil(err)
+	_, err = envApi.For("stage").Objects().Create(&api.ObjectInfo{
+		Code:       "child",
+		Inherits:   &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: "prod", ObjectCode: "flag"},
+		Parameters: []*domain.Parameter{{Code: "mode", Type: domain.ParameterEnum, Value: "c"}},
+	})
+	assert.Nil(err)
+
+	ls, err := envApi.For("dev").Objects().InheritorsFlatList("flag")
+	assert.Nil(err)
+	found := make([]string, 0)
+	for _, l := range ls {
+		found = append(found, string(l.EnvCode)+"/"+string(l.Code))
+	}
+	assert.Equal([]string{"prod/flag", "stage/child"}, found)
+
+	_, err = envApi.For("dev").Objects().Update(&api.ObjectInfo{Code: "flag", Parameters: enum("a", "b"), Version: flag.Version})
+	assert.IsType(&api.ErrObjectParameter{}, err, "grandchild in other environment is checked")
+	flag, err = envApi.For("dev").Objects().Update(&api.ObjectInfo{Code: "flag", Description: "updated", Parameters: enum("a", "b", "c"), Version: flag.Version})
+	assert.Nil(err)
+	assert.Equal("updated", flag.Description)
+
+	AfterTest()
+}
+
 func TestObjectsParameterInheritance(t *testing.T) {
 	assert := asserts.New(t)
 	BeforeTest()
@@ -0,0 +1,321 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Toggly/core/internal/pkg/jsonschema"
+	"github.com/Toggly/core/internal/pkg/semver"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// parameterValue checks value against parameter definition and returns it in the normalized form
+func parameterValue(def *domain.Parameter, value interface{}) (interface{}, error) {
+	var v interface{}
+	var ok bool
+	switch def.Type {
+	case domain.ParameterBool:
+		v, ok = value.(bool)
+	case domain.ParameterString:
+		v, ok = value.(string)
+	case domain.ParameterEnum:
+		var s string
+		if s, ok = value.(string); ok && len(def.AllowedValues) > 0 {
+			ok = contains(def.AllowedValues, s)
+		}
+		v = s
+	case domain.ParameterInt:
+		v, ok = toInt(value)
+	case domain.ParameterFloat:
+		v, ok = toFloat(value)
+	case domain.ParameterJSON:
+		if v, ok = toJSON(value); ok && len(def.Schema) > 0 {
+			schema, err := jsonschema.Compile(def.Schema)
+			if err != nil {
+				return nil, api.NewObjectParameterError(string(def.Code), fmt.Sprintf("Schema error: %v", err))
+			}
+			if err := schema.Validate(v); err != nil {
+				return nil, api.NewObjectParameterError(string(def.Code), fmt.Sprintf("Value doesn't match schema: %v", err))
+			}
+		}
+	case domain.ParameterList:
+		v, ok = toList(value)
+	case domain.ParameterDuration:
+		var s string
+		if s, ok = value.(string); ok {
+			d, err := time.ParseDuration(s)
+			ok = err == nil
+			v = d.String()
+		}
+	case domain.ParameterSemver:
+		var s string
+		if s, ok = value.(string); ok {
+			ver, err := semver.Parse(s)
+			if ok = err == nil; ok {
+				v = ver.String()
+			}
+		}
+	}
+	if !ok {
+		return nil, api.NewObjectParameterError(string(def.Code), fmt.Sprintf("Value `%v` is not valid %s", value, def.Type))
+	}
+	if def.Constraints != nil {
+		if msg := checkValueConstraints(def.Constraints, v); msg != "" {
+			return nil, api.NewObjectParameterError(string(def.Code), msg)
+		}
+	}
+	return v, nil
+}
+
+func checkValueConstraints(c *domain.ParameterConstraints, value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return checkNumberConstraints(c, float64(v), value)
+	case float64:
+		return checkNumberConstraints(c, v, value)
+	case string:
+		l := utf8.RuneCountInString(v)
+		if c.MinLength != nil && l < *c.MinLength {
+			return fmt.Sprintf("Value length is less than %d", *c.MinLength)
+		}
+		if c.MaxLength != nil && l > *c.MaxLength {
+			return fmt.Sprintf("Value length is greater than %d", *c.MaxLength)
+		}
+		if c.Pattern != "" {
+			if re, err := regexp.Compile(c.Pattern); err != nil || !re.MatchString(v) {
+				return fmt.Sprintf("Value `%s` doesn't match pattern `%s`", v, c.Pattern)
+			}
+		}
+	}
+	return ""
+}
+
+func checkNumberConstraints(c *domain.ParameterConstraints, f float64, value interface{}) string {
+	if c.Min != nil && f < *c.Min {
+		return fmt.Sprintf("Value `%v` is less than %v", value, *c.Min)
+	}
+	if c.Max != nil && f > *c.Max {
+		return fmt.Sprintf("Value `%v` is greater than %v", value, *c.Max)
+	}
+	return ""
+}
+
+func checkConstraints(p *domain.Parameter) error {
+	c := p.Constraints
+	if c == nil {
+		return nil
+	}
+	fail := func(msg string) error {
+		return api.NewObjectParameterError(string(p.Code), msg)
+	}
+	numeric := p.Type == domain.ParameterInt || p.Type == domain.ParameterFloat
+	if (c.Min != nil || c.Max != nil) && !numeric {
+		return fail("Min and max constraints can be specified for int and float parameters only")
+	}
+	if (c.Pattern != "" || c.MinLength != nil || c.MaxLength != nil) && p.Type != domain.ParameterString {
+		return fail("Pattern and length constraints can be specified for string parameter only")
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return fail("Min constraint is greater than max")
+	}
+	if (c.MinLength != nil && *c.MinLength < 0) || (c.MaxLength != nil && *c.MaxLength < 0) {
+		return fail("Length constraint can't be negative")
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		return fail("Min length constraint is greater than max length")
+	}
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return fail(fmt.Sprintf("Pattern constraint error: %v", err))
+		}
+	}
+	return nil
+}
+
+func checkSchema(p *domain.Parameter) error {
+	if len(p.Schema) == 0 {
+		return nil
+	}
+	if p.Type != domain.ParameterJSON {
+		return api.NewObjectParameterError(string(p.Code), "Schema can be specified for json parameter only")
+	}
+	if _, err := jsonschema.Compile(p.Schema); err != nil {
+		return api.NewObjectParameterError(string(p.Code), fmt.Sprintf("Schema error: %v", err))
+	}
+	return nil
+}
+
+func checkAllowedValues(p *domain.Parameter) error {
+	if len(p.AllowedValues) == 0 {
+		return nil
+	}
+	if p.Type != domain.ParameterEnum {
+		return api.NewObjectParameterError(string(p.Code), "Allowed values can be specified for enum parameter only")
+	}
+	for i, v := range p.AllowedValues {
+		if v == "" {
+			return api.NewObjectParameterError(string(p.Code), "Allowed value can't be empty")
+		}
+		if contains(p.AllowedValues[:i], v) {
+			return api.NewObjectParameterError(string(p.Code), fmt.Sprintf("Allowed value `%s` duplicated", v))
+		}
+	}
+	return nil
+}
+
+// normalizeParameters returns copy of parameters with normalized values
+func normalizeParameters(parameters []*domain.Parameter) ([]*domain.Parameter, error) {
+	if parameters == nil {
+		return nil, nil
+	}
+	res := make([]*domain.Parameter, len(parameters))
+	for i, p := range parameters {
+		if err := checkAllowedValues(p); err != nil {
+			return nil, err
+		}
+		if err := checkSchema(p); err != nil {
+			return nil, err
+		}
+		if err := checkConstraints(p); err != nil {
+			return nil, err
+		}
+		if err := checkRules(p); err != nil {
+			return nil, err
+		}
+		if err := checkRollout(p); err != nil {
+			return nil, err
+		}
+		np, err := normalizeParameter(p, p)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = np
+	}
+	return res, nil
+}
+
+// normalizeParameter checks parameter value, rule and rollout values against definition
+// and returns copy of parameter with normalized values
+func normalizeParameter(def *domain.Parameter, p *domain.Parameter) (*domain.Parameter, error) {
+	v, err := parameterValue(def, p.Value)
+	if err != nil {
+		return nil, err
+	}
+	np := *p
+	np.Value = v
+	if p.Rules != nil {
+		np.Rules = make([]*domain.Rule, len(p.Rules))
+		for i, r := range p.Rules {
+			rule := *r
+			if rule.Value, err = parameterValue(def, r.Value); err != nil {
+				return nil, err
+			}
+			np.Rules[i] = &rule
+		}
+	}
+	if np.Rollout, err = normalizeRollout(def, p.Rollout); err != nil {
+		return nil, err
+	}
+	return &np, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), uint64(v) <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float32:
+		return floatToInt(float64(v))
+	case float64:
+		return floatToInt(v)
+	}
+	return 0, false
+}
+
+func floatToInt(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	default:
+		i, ok := toInt(value)
+		if !ok {
+			return 0, false
+		}
+		f = float64(i)
+	}
+	return f, !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// toJSON returns value in the form produced by json.Unmarshal
+func toJSON(value interface{}) (interface{}, bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+func toList(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		res := make([]string, len(v))
+		copy(res, v)
+		return res, true
+	case []interface{}:
+		res := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			res[i] = s
+		}
+		return res, true
+	}
+	return nil, false
+}
@@ -0,0 +1,508 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestParameterValues(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	tt := []struct {
+		name    string
+		pType   domain.ParameterType
+		value   interface{}
+		allowed []string
+		valid   bool
+		norm    interface{}
+	}{
+		{"bool", domain.ParameterBool, true, nil, true, true},
+		{"bool from string", domain.ParameterBool, "banana", nil, false, nil},
+		{"bool from int", domain.ParameterBool, 1, nil, false, nil},
+		{"string", domain.ParameterString, "value", nil, true, "value"},
+		{"string from bool", domain.ParameterString, false, nil, false, nil},
+		{"enum", domain.ParameterEnum, "value", []string{"value", "other"}, true, "value"},
+		{"enum from int", domain.ParameterEnum, 1, []string{"value", "other"}, false, nil},
+		{"enum not allowed", domain.ParameterEnum, "banana", []string{"value", "other"}, false, nil},
+		{"enum without allowed values", domain.ParameterEnum, "value", nil, false, nil},
+		{"enum duplicated allowed values", domain.ParameterEnum, "value", []string{"value", "value"}, false, nil},
+		{"allowed values for string", domain.ParameterString, "value", []string{"value"}, false, nil},
+		{"int", domain.ParameterInt, 10, nil, true, int64(10)},
+		{"int from integral float", domain.ParameterInt, float64(3), nil, true, int64(3)},
+		{"int from float", domain.ParameterInt, 3.7, nil, false, nil},
+		{"int from string", domain.ParameterInt, "3", nil, false, nil},
+		{"float", domain.ParameterFloat, 3.7, nil, true, 3.7},
+		{"float from int", domain.ParameterFloat, 3, nil, true, float64(3)},
+		{"float from string", domain.ParameterFloat, "3.7", nil, false, nil},
+		{"json object", domain.ParameterJSON, map[string]interface{}{"$key": []interface{}{1, "a"}}, nil, true, map[string]interface{}{"$key": []interface{}{float64(1), "a"}}},
+		{"json string", domain.ParameterJSON, "value", nil, true, "value"},
+		{"json not marshalable", domain.ParameterJSON, func() {}, nil, false, nil},
+		{"list", domain.ParameterList, []interface{}{"a", "b"}, nil, true, []string{"a", "b"}},
+		{"list of strings", domain.ParameterList, []string{}, nil, true, []string{}},
+		{"list of ints", domain.ParameterList, []interface{}{"a", 1}, nil, false, nil},
+		{"list from string", domain.ParameterList, "a,b", nil, false, nil},
+		{"duration", domain.ParameterDuration, "90m", nil, true, "1h30m0s"},
+		{"duration not valid", domain.ParameterDuration, "90", nil, false, nil},
+		{"duration from int", domain.ParameterDuration, 90, nil, false, nil},
+		{"semver", domain.ParameterSemver, "v1.2.3-rc.1", nil, true, "1.2.3-rc.1"},
+		{"semver not valid", domain.ParameterSemver, "1.2", nil, false, nil},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, err := objApi.Create(&api.ObjectInfo{
+				Code: "obj1",
+				Parameters: []*domain.Parameter{
+					{
+						Code:          "param1",
+						Type:          tc.pType,
+						Value:         tc.value,
+						AllowedValues: tc.allowed,
+					},
+				},
+			})
+			if !tc.valid {
+				assert.IsType(&api.ErrObjectParameter{}, err)
+				assert.Equal("param1", err.(*api.ErrObjectParameter).Name)
+				assert.Nil(obj)
+				return
+			}
+			assert.Nil(err)
+			assert.Equal(tc.norm, obj.Parameters[0].Value)
+			objApi.Delete("obj1", 0)
+		})
+	}
+
+	objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "param1",
+				Type:  domain.ParameterInt,
+				Value: 1,
+			},
+		},
+	})
+
+	_, err := objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "param1",
+				Type:  domain.ParameterInt,
+				Value: 3.7,
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `param1` error: Value `3.7` is not valid int", err.Error())
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "param1",
+				Type:  domain.ParameterInt,
+				Value: "banana",
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+
+	obj, err := objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "param1",
+				Type:  domain.ParameterInt,
+				Value: float64(2),
+			},
+		},
+	})
+	assert.Nil(err)
+	assert.Len(obj.Parameters, 1)
+	assert.Equal(int64(2), obj.Parameters[0].Value)
+
+	AfterTest()
+}
+
+func TestParameterEnum(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	inherits := &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"}
+
+	_, err := objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:          "color",
+				Type:          domain.ParameterEnum,
+				Value:         "red",
+				AllowedValues: []string{"red", "green", "blue"},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: inherits,
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "color",
+				Type:  domain.ParameterEnum,
+				Value: "black",
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `color` error: Value `black` is not valid enum", err.Error())
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: inherits,
+		Parameters: []*domain.Parameter{
+			{
+				Code:          "color",
+				Type:          domain.ParameterEnum,
+				Value:         "black",
+				AllowedValues: []string{"black"},
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `color` error: Enum allowed values are defined by parent object", err.Error())
+
+	obj, err := objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: inherits,
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "color",
+				Type:  domain.ParameterEnum,
+				Value: "blue",
+			},
+		},
+	})
+	assert.Nil(err)
+	assert.Len(obj.Parameters, 1)
+	assert.Equal("blue", obj.Parameters[0].Value)
+	assert.Equal([]string{"red", "green", "blue"}, obj.Parameters[0].AllowedValues)
+
+	_, err = objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:          "color",
+				Type:          domain.ParameterEnum,
+				Value:         "red",
+				AllowedValues: []string{"red", "green"},
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `color` error: Object parameter is not valid for inheritors: p1:env_1:obj2", err.Error())
+
+	obj, err = objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:          "color",
+				Type:          domain.ParameterEnum,
+				Value:         "red",
+				AllowedValues: []string{"red", "blue", "white"},
+			},
+		},
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"red", "blue", "white"}, obj.Parameters[0].AllowedValues)
+
+	AfterTest()
+}
+
+func TestParameterEnumSavedWithoutAllowedValues(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	color := &domain.Parameter{Code: "color", Type: domain.ParameterEnum, Value: "red"}
+	assert.Nil(dataStorage.ForOwner(ow).Projects().For(ProjectCode).Environments().For(envCode).Objects().Save(&domain.Object{
+		Code:        "obj1",
+		Owner:       ow,
+		ProjectCode: ProjectCode,
+		EnvCode:     envCode,
+		Parameters:  []*domain.Parameter{color},
+		Version:     1,
+	}))
+
+	obj, err := objApi.Update(&api.ObjectInfo{Code: "obj1", Description: "updated", Parameters: []*domain.Parameter{color}})
+	assert.Nil(err, "enum saved without allowed values is kept")
+	assert.Equal("updated", obj.Description)
+
+	_, err = objApi.Update(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{
+		color,
+		{Code: "size", Type: domain.ParameterEnum, Value: "s"},
+	}})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `size` error: Enum allowed values not specified", err.Error())
+
+	obj, err = objApi.Update(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{
+		{Code: "color", Type: domain.ParameterEnum, Value: "red", AllowedValues: []string{"red", "blue"}},
+	}})
+	assert.Nil(err)
+	assert.Equal([]string{"red", "blue"}, obj.Parameters[0].AllowedValues)
+
+	_, err = objApi.Update(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{color}})
+	assert.IsType(&api.ErrObjectParameter{}, err, "allowed values can't be removed")
+
+	AfterTest()
+}
+
+func TestParameterJSONSchema(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	schema := []byte(`{"type": "object", "required": ["limit"], "properties": {"limit": {"type": "integer", "minimum": 1}}}`)
+
+	_, err := objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterString, Value: "value", Schema: schema},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `param1` error: Schema can be specified for json parameter only", err.Error())
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterJSON, Value: map[string]interface{}{}, Schema: []byte(`{"type": "unknown"}`)},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterJSON, Value: map[string]interface{}{"limit": 0}, Schema: schema},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `param1` error: Value doesn't match schema: /limit: expected minimum 1", err.Error())
+
+	obj, err := objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterJSON, Value: map[string]interface{}{"limit": 10}, Schema: schema},
+		},
+	})
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"limit": float64(10)}, obj.Parameters[0].Value)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterJSON, Value: map[string]interface{}{"size": 10}},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `param1` error: Value doesn't match schema: /: property `limit` is required", err.Error())
+
+	obj, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{Code: "param1", Type: domain.ParameterJSON, Value: map[string]interface{}{"limit": 20}},
+		},
+	})
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"limit": float64(20)}, obj.Parameters[0].Value)
+	assert.JSONEq(string(schema), string(obj.Parameters[0].Schema))
+
+	AfterTest()
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestParameterConstraints(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	tt := []struct {
+		name        string
+		pType       domain.ParameterType
+		value       interface{}
+		constraints *domain.ParameterConstraints
+		err         string
+	}{
+		{"int in range", domain.ParameterInt, 5, &domain.ParameterConstraints{Min: floatPtr(1), Max: floatPtr(10)}, ""},
+		{"int less than min", domain.ParameterInt, 0, &domain.ParameterConstraints{Min: floatPtr(1)}, "Value `0` is less than 1"},
+		{"int greater than max", domain.ParameterInt, 11, &domain.ParameterConstraints{Max: floatPtr(10)}, "Value `11` is greater than 10"},
+		{"float greater than max", domain.ParameterFloat, 0.6, &domain.ParameterConstraints{Max: floatPtr(0.5)}, "Value `0.6` is greater than 0.5"},
+		{"min greater than max", domain.ParameterInt, 5, &domain.ParameterConstraints{Min: floatPtr(10), Max: floatPtr(1)}, "Min constraint is greater than max"},
+		{"min for string", domain.ParameterString, "value", &domain.ParameterConstraints{Min: floatPtr(1)}, "Min and max constraints can be specified for int and float parameters only"},
+		{"string matches pattern", domain.ParameterString, "abc", &domain.ParameterConstraints{Pattern: "^[a-z]+$"}, ""},
+		{"string doesn't match pattern", domain.ParameterString, "ABC", &domain.ParameterConstraints{Pattern: "^[a-z]+$"}, "Value `ABC` doesn't match pattern `^[a-z]+$`"},
+		{"wrong pattern", domain.ParameterString, "abc", &domain.ParameterConstraints{Pattern: "("}, "Pattern constraint error: error parsing regexp: missing closing ): `(`"},
+		{"string length", domain.ParameterString, "abc", &domain.ParameterConstraints{MinLength: intPtr(1), MaxLength: intPtr(3)}, ""},
+		{"string too short", domain.ParameterString, "ab", &domain.ParameterConstraints{MinLength: intPtr(3)}, "Value length is less than 3"},
+		{"string too long", domain.ParameterString, "abcd", &domain.ParameterConstraints{MaxLength: intPtr(3)}, "Value length is greater than 3"},
+		{"negative length", domain.ParameterString, "abc", &domain.ParameterConstraints{MaxLength: intPtr(-1)}, "Length constraint can't be negative"},
+		{"length for int", domain.ParameterInt, 1, &domain.ParameterConstraints{MaxLength: intPtr(3)}, "Pattern and length constraints can be specified for string parameter only"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := objApi.Create(&api.ObjectInfo{
+				Code: "obj1",
+				Parameters: []*domain.Parameter{
+					{Code: "param1", Type: tc.pType, Value: tc.value, Constraints: tc.constraints},
+				},
+			})
+			if tc.err != "" {
+				assert.IsType(&api.ErrObjectParameter{}, err)
+				assert.Equal("Object parameter `param1` error: "+tc.err, err.Error())
+				return
+			}
+			assert.Nil(err)
+			objApi.Delete("obj1", 0)
+		})
+	}
+
+	_, err := objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 5, Constraints: &domain.ParameterConstraints{Min: floatPtr(1), Max: floatPtr(100)}},
+		},
+	})
+	assert.Nil(err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 200},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `limit` error: Value `200` is greater than 100", err.Error())
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 50, Constraints: &domain.ParameterConstraints{Max: floatPtr(1000)}},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `limit` error: Constraints are defined by parent object", err.Error())
+
+	for code, value := range map[domain.ObjectCode]int{"obj2": 50, "obj3": 80} {
+		_, err = objApi.Create(&api.ObjectInfo{
+			Code:     code,
+			Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+			Parameters: []*domain.Parameter{
+				{Code: "limit", Type: domain.ParameterInt, Value: value},
+			},
+		})
+		assert.Nil(err)
+	}
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj4",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj2"},
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 200},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+
+	_, err = objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 5, Constraints: &domain.ParameterConstraints{Min: floatPtr(1), Max: floatPtr(10)}},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Contains(err.Error(), "Object parameter `limit` error: Object parameter is not valid for inheritors: ")
+	assert.Contains(err.Error(), "p1:env_1:obj2")
+	assert.Contains(err.Error(), "p1:env_1:obj3")
+
+	_, err = objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 5, Constraints: &domain.ParameterConstraints{Min: floatPtr(1), Max: floatPtr(60)}},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `limit` error: Object parameter is not valid for inheritors: p1:env_1:obj3", err.Error())
+
+	obj, err := objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 5, Constraints: &domain.ParameterConstraints{Min: floatPtr(1), Max: floatPtr(80)}},
+		},
+	})
+	assert.Nil(err)
+	assert.Equal(floatPtr(80), obj.Parameters[0].Constraints.Max)
+
+	obj, err = objApi.Get("obj3")
+	assert.Nil(err)
+	assert.Equal(floatPtr(80), obj.Parameters[0].Constraints.Max)
+
+	AfterTest()
+}
@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/globalsign/mgo/bson"
 )
 
@@ -15,6 +15,35 @@ type ProjectAPI struct {
 	OwnerAPI
 }
 
+type projectAccess int
+
+const (
+	accessRead projectAccess = iota
+	accessWrite
+	accessEvaluate
+)
+
+// check returns error if project doesn't exist or its status denies the access.
+// Disabled project is read-only, it is unavailable for evaluation in DisabledProjectUnavailable mode.
+func (p *ProjectAPI) check(code domain.ProjectCode, access projectAccess) error {
+	project, err := p.Get(code)
+	if err != nil {
+		return err
+	}
+	if project.Status != domain.ProjectStatusDisabled {
+		return nil
+	}
+	switch access {
+	case accessWrite:
+		return api.ErrProjectDisabled
+	case accessEvaluate:
+		if p.DisabledProjectMode == DisabledProjectUnavailable {
+			return api.ErrProjectDisabled
+		}
+	}
+	return nil
+}
+
 func (p *ProjectAPI) storage() storage.ProjectStorage {
 	return (*p.Storage).ForOwner(p.Owner).Projects()
 }
@@ -57,10 +86,12 @@ func (p *ProjectAPI) Create(info *api.ProjectInfo) (*domain.Project, error) {
 		Description: description,
 		RegDate:     bson.Now().In(time.UTC),
 		Status:      status,
+		Version:     1,
 	}
 	if err := p.storage().Save(newProj); err != nil {
 		return nil, err
 	}
+	p.publishProject(domain.EventProjectCreate, code, newProj)
 	return newProj, nil
 }
 
@@ -76,20 +107,25 @@ func (p *ProjectAPI) Update(info *api.ProjectInfo) (*domain.Project, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(info.Version, pr.Version); err != nil {
+		return nil, err
+	}
 	newProj := &domain.Project{
 		OwnerID:     p.Owner,
 		Code:        code,
 		Description: description,
 		RegDate:     pr.RegDate,
 		Status:      status,
+		Version:     pr.Version,
 	}
 	if err := p.storage().Update(newProj); err != nil {
-		return nil, err
+		return nil, updateError(err, api.ErrProjectNotFound)
 	}
+	p.publishProject(domain.EventProjectUpdate, code, newProj)
 	return newProj, nil
 }
 
-// Delete Project
+// Delete Project, environments, segments and webhooks are deleted first
 func (p *ProjectAPI) Delete(code domain.ProjectCode) error {
 	envList, err := p.For(code).Environments().List()
 	if err != nil {
@@ -98,11 +134,37 @@ func (p *ProjectAPI) Delete(code domain.ProjectCode) error {
 	if len(envList) > 0 {
 		return api.ErrProjectNotEmpty
 	}
+	segments, err := p.storage().For(code).Segments().List()
+	if err != nil {
+		return err
+	}
+	if len(segments) > 0 {
+		return api.ErrProjectNotEmpty
+	}
+	hooks, err := p.storage().For(code).Webhooks().List()
+	if err != nil {
+		return err
+	}
+	if len(hooks) > 0 {
+		return api.ErrProjectNotEmpty
+	}
 	err = p.storage().Delete(code)
 	if err == storage.ErrNotFound {
 		return api.ErrProjectNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	p.publishProject(domain.EventProjectDelete, code, nil)
+	return nil
+}
+
+func (p *ProjectAPI) publishProject(eventType domain.EventType, code domain.ProjectCode, project *domain.Project) {
+	event := p.newEvent(eventType, code)
+	if project != nil {
+		event.Data = project
+	}
+	p.publish(event)
 }
 
 // For returns environment api for specified project
@@ -132,3 +194,23 @@ func (fp *ForProjectAPI) Environments() api.EnvironmentAPI {
 		ProjectAPI:  fp.ProjectAPI,
 	}
 }
+
+// Webhooks returns Webhooks API
+func (fp *ForProjectAPI) Webhooks() api.WebhookAPI {
+	return &WebhookAPI{
+		Owner:       fp.Owner,
+		ProjectCode: fp.ProjectCode,
+		Storage:     fp.Storage,
+		ProjectAPI:  fp.ProjectAPI,
+	}
+}
+
+// Segments returns Segments API
+func (fp *ForProjectAPI) Segments() api.SegmentAPI {
+	return &SegmentAPI{
+		Owner:       fp.Owner,
+		ProjectCode: fp.ProjectCode,
+		Storage:     fp.Storage,
+		ProjectAPI:  fp.ProjectAPI,
+	}
+}
@@ -3,8 +3,9 @@ package engine_test
 import (
 	"testing"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 
 	"github.com/Toggly/core/internal/pkg/storage"
 
@@ -96,14 +97,103 @@ func TestProject(t *testing.T) {
 	assert.Nil(pr2u)
 	assert.Equal(api.ErrProjectNotFound, err)
 
+	_, err = pApi.For(ProjectCode).Environments().Create(&api.EnvironmentInfo{Code: "env_code"})
+	assert.Equal(api.ErrProjectDisabled, err)
+
+	_, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+
 	pApi.For(ProjectCode).Environments().Create(&api.EnvironmentInfo{Code: "env_code"})
 
 	assert.Equal(api.ErrProjectNotEmpty, pApi.Delete("p1"))
 
 	assert.Nil(pApi.For(ProjectCode).Environments().Delete("env_code"))
+	hook, err := pApi.For(ProjectCode).Webhooks().Create(&api.WebhookInfo{URL: "https://example.com/hook"})
+	assert.Nil(err)
+	assert.Equal(api.ErrProjectNotEmpty, pApi.Delete(ProjectCode), "webhooks are deleted first")
+
+	assert.Nil(pApi.For(ProjectCode).Webhooks().Delete(hook.ID))
+	_, err = pApi.For(ProjectCode).Segments().Create(&api.SegmentInfo{Code: "beta", Include: []string{"user1"}})
+	assert.Nil(err)
+	assert.Equal(api.ErrProjectNotEmpty, pApi.Delete(ProjectCode), "segments are deleted first")
+
+	assert.Nil(pApi.For(ProjectCode).Segments().Delete("beta"))
 	assert.Nil(pApi.Delete(ProjectCode))
 
 	assert.Equal(api.ErrProjectNotFound, pApi.Delete(ProjectCode))
 
 	AfterTest()
 }
+
+func TestDisabledProject(t *testing.T) {
+	assert := asserts.New(t)
+
+	for _, mode := range []engine.DisabledProjectMode{engine.DisabledProjectReadOnly, engine.DisabledProjectUnavailable} {
+		t.Run(string(mode), func(t *testing.T) {
+			BeforeTest()
+
+			pApi := GetEngine(mode).ForOwner(ow).Projects()
+			envApi := pApi.For(ProjectCode).Environments()
+			segApi := pApi.For(ProjectCode).Segments()
+			objApi := envApi.For(envCode).Objects()
+
+			pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+			envApi.Create(&api.EnvironmentInfo{Code: envCode})
+			segApi.Create(&api.SegmentInfo{Code: "beta"})
+			objInfo := &api.ObjectInfo{
+				Code:       "obj1",
+				Parameters: []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: true}},
+			}
+			_, err := objApi.Create(objInfo)
+			assert.Nil(err)
+
+			_, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusDisabled})
+			assert.Nil(err)
+
+			_, err = envApi.Get(envCode)
+			assert.Nil(err)
+			_, err = segApi.Get("beta")
+			assert.Nil(err)
+			_, err = objApi.Get("obj1")
+			assert.Nil(err)
+
+			_, err = envApi.Create(&api.EnvironmentInfo{Code: "env_2"})
+			assert.Equal(api.ErrProjectDisabled, err)
+			_, err = envApi.Update(&api.EnvironmentInfo{Code: envCode})
+			assert.Equal(api.ErrProjectDisabled, err)
+			assert.Equal(api.ErrProjectDisabled, envApi.Delete(envCode))
+
+			_, err = segApi.Create(&api.SegmentInfo{Code: "alpha"})
+			assert.Equal(api.ErrProjectDisabled, err)
+			_, err = segApi.Update(&api.SegmentInfo{Code: "beta"})
+			assert.Equal(api.ErrProjectDisabled, err)
+			assert.Equal(api.ErrProjectDisabled, segApi.Delete("beta"))
+
+			_, err = objApi.Create(&api.ObjectInfo{Code: "obj2"})
+			assert.Equal(api.ErrProjectDisabled, err)
+			_, err = objApi.Update(objInfo)
+			assert.Equal(api.ErrProjectDisabled, err)
+			assert.Equal(api.ErrProjectDisabled, objApi.Delete("obj1", 0))
+			_, err = envApi.For(envCode).OverrideProtection().Objects().Update(objInfo)
+			assert.Equal(api.ErrProjectDisabled, err)
+
+			values, err := objApi.Evaluate("obj1", &domain.EvaluationContext{})
+			all, errAll := objApi.EvaluateAll(&domain.EvaluationContext{})
+			if mode == engine.DisabledProjectUnavailable {
+				assert.Equal(api.ErrProjectDisabled, err)
+				assert.Equal(api.ErrProjectDisabled, errAll)
+			} else {
+				assert.Nil(err)
+				assert.Equal(domain.ObjectValues{"param1": true}, values)
+				assert.Nil(errAll)
+				assert.Equal(domain.EnvironmentValues{"obj1": values}, all)
+			}
+
+			_, err = pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+			assert.Nil(err)
+			assert.Nil(objApi.Delete("obj1", 0))
+
+			AfterTest()
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+// revisionAttempts limits retries of concurrent revisions numbering
+const revisionAttempts = 3
+
+func (o *ObjectAPI) revisions() storage.RevisionStorage {
+	return (*o.Storage).ForOwner(o.Owner).Projects().For(o.ProjectCode).Environments().For(o.EnvCode).Revisions()
+}
+
+// History returns object revisions, deleted objects keep their history
+func (o *ObjectAPI) History(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	if err := o.envExists(); err != nil {
+		return nil, err
+	}
+	list, err := o.revisions().List(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		if _, err := o.storage().Get(code); err != nil {
+			if err == storage.ErrNotFound {
+				return nil, api.ErrObjectNotFound
+			}
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Revert restores object state of the revision. Deleted object is created again.
+// Restored state is checked the same way as on update.
+func (o *ObjectAPI) Revert(code domain.ObjectCode, revision int64) (*domain.Object, error) {
+	if err := o.envExists(); err != nil {
+		return nil, err
+	}
+	rev, err := o.revisions().Get(code, revision)
+	if err == storage.ErrNotFound {
+		return nil, api.ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rev.Action == domain.RevisionDelete {
+		return nil, api.NewBadRequestError("Object was deleted in the revision, revert to the previous one")
+	}
+	info, err := revisionInfo(rev)
+	if err != nil {
+		return nil, err
+	}
+	inheritors := o.inheritorsState(code)
+	old, err := o.storage().Get(code)
+	var obj *domain.Object
+	switch err {
+	case nil:
+		obj, err = o.update(info)
+	case storage.ErrNotFound:
+		old = nil
+		obj, err = o.create(info)
+	}
+	if err != nil {
+		return nil, err
+	}
+	o.record(domain.RevisionRevert, code, old, revision)
+	o.publishObject(domain.EventObjectRevert, code, obj, inheritors)
+	return obj, nil
+}
+
+// revisionInfo returns object info of the revision state.
+// Parameters are passed through JSON to get the same values as in api requests.
+func revisionInfo(rev *domain.ObjectRevision) (*api.ObjectInfo, error) {
+	data, err := json.Marshal(rev.Object.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	var parameters []*domain.Parameter
+	if err := json.Unmarshal(data, &parameters); err != nil {
+		return nil, err
+	}
+	return &api.ObjectInfo{
+		Code:        rev.ObjectCode,
+		Description: rev.Object.Description,
+		Inherits:    rev.Object.Inherits,
+		Parameters:  parameters,
+	}, nil
+}
+
+// record saves object revision. Old is the stored object state before the change.
+// The change is already applied, so failure is logged only.
+func (o *ObjectAPI) record(action domain.RevisionAction, code domain.ObjectCode, old *domain.Object, revertedTo int64) {
+	if err := o.saveRevision(action, code, old, revertedTo); err != nil {
+		log.Printf("[ERROR] Can't save revision of object %s: %v", code, err)
+	}
+}
+
+func (o *ObjectAPI) saveRevision(action domain.RevisionAction, code domain.ObjectCode, old *domain.Object, revertedTo int64) error {
+	var current *domain.Object
+	if action != domain.RevisionDelete {
+		var err error
+		if current, err = o.storage().Get(code); err != nil {
+			return err
+		}
+	}
+	snapshot := current
+	if snapshot == nil {
+		snapshot = old
+	}
+	rev := &domain.ObjectRevision{
+		Owner:       o.Owner,
+		ProjectCode: o.ProjectCode,
+		EnvCode:     o.EnvCode,
+		ObjectCode:  code,
+		Action:      action,
+		RevertedTo:  revertedTo,
+		Actor:       o.EnvironmentAPI.ProjectAPI.Actor,
+		Date:        bson.Now().In(time.UTC),
+		Object:      snapshot,
+		Diff:        objectDiff(old, current),
+	}
+	revisions := o.revisions()
+	var latest *domain.ObjectRevision
+	var err error
+	for i := 0; i < revisionAttempts; i++ {
+		rev.Revision = 1
+		latest, err = revisions.Latest(code)
+		if err == nil {
+			rev.Revision = latest.Revision + 1
+		} else if err != storage.ErrNotFound {
+			return err
+		}
+		err = revisions.Save(rev)
+		if _, ok := err.(*storage.UniqueIndexError); !ok {
+			return err
+		}
+	}
+	return err
+}
+
+// objectDiff returns changes between object states, nil state stands for missing object
+func objectDiff(old, new *domain.Object) []*domain.Change {
+	if old == nil {
+		old = &domain.Object{}
+	}
+	if new == nil {
+		new = &domain.Object{}
+	}
+	changes := make([]*domain.Change, 0)
+	if old.Description != new.Description {
+		changes = append(changes, &domain.Change{Field: "description", Old: old.Description, New: new.Description})
+	}
+	if !reflect.DeepEqual(old.Inherits, new.Inherits) {
+		change := &domain.Change{Field: "inherits"}
+		if old.Inherits != nil {
+			change.Old = old.Inherits
+		}
+		if new.Inherits != nil {
+			change.New = new.Inherits
+		}
+		changes = append(changes, change)
+	}
+	newParams := make(map[domain.ParameterCode]*domain.Parameter, len(new.Parameters))
+	for _, p := range new.Parameters {
+		newParams[p.Code] = p
+	}
+	for _, p := range old.Parameters {
+		np, ok := newParams[p.Code]
+		switch {
+		case !ok:
+			changes = append(changes, &domain.Change{Field: "parameters." + string(p.Code), Old: p})
+		case !reflect.DeepEqual(p, np):
+			changes = append(changes, &domain.Change{Field: "parameters." + string(p.Code), Old: p, New: np})
+		}
+		delete(newParams, p.Code)
+	}
+	for _, p := range new.Parameters {
+		if _, ok := newParams[p.Code]; ok {
+			changes = append(changes, &domain.Change{Field: "parameters." + string(p.Code), New: p})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,179 @@
+package engine_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func boolParam(value bool) []*domain.Parameter {
+	return []*domain.Parameter{{Code: "enabled", Type: domain.ParameterBool, Value: value}}
+}
+
+func TestObjectsHistory(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetEngine(engine.DisabledProjectReadOnly).WithActor("key_1").ForOwner(ow).Projects()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	_, err := objApi.History(objCode)
+	assert.Equal(api.ErrProjectNotFound, err)
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	_, err = objApi.History(objCode)
+	assert.Equal(api.ErrObjectNotFound, err)
+
+	_, err = objApi.Create(&api.ObjectInfo{Code: objCode, Parameters: boolParam(false)})
+	assert.Nil(err)
+	_, err = objApi.Update(&api.ObjectInfo{Code: objCode, Description: "changed", Parameters: boolParam(true)})
+	assert.Nil(err)
+	assert.Nil(objApi.Delete(objCode, 0))
+
+	list, err := objApi.History(objCode)
+	assert.Nil(err)
+	assert.Len(list, 3)
+
+	assert.Equal(int64(1), list[0].Revision)
+	assert.Equal(domain.RevisionCreate, list[0].Action)
+	assert.Equal("key_1", list[0].Actor)
+	assert.Equal(ow, list[0].Owner)
+	assert.Equal(int64(1), list[0].Object.Version)
+	assert.Len(list[0].Diff, 1)
+	assert.Equal("parameters.enabled", list[0].Diff[0].Field)
+	assert.Nil(list[0].Diff[0].Old)
+
+	assert.Equal(int64(2), list[1].Revision)
+	assert.Equal(domain.RevisionUpdate, list[1].Action)
+	assert.Equal("changed", list[1].Object.Description)
+	assert.Len(list[1].Diff, 2)
+	assert.Equal("description", list[1].Diff[0].Field)
+	assert.Equal("", list[1].Diff[0].Old)
+	assert.Equal("changed", list[1].Diff[0].New)
+	assert.Equal("parameters.enabled", list[1].Diff[1].Field)
+
+	assert.Equal(int64(3), list[2].Revision)
+	assert.Equal(domain.RevisionDelete, list[2].Action)
+	assert.Equal("changed", list[2].Object.Description)
+	assert.Len(list[2].Diff, 2)
+
+	AfterTest()
+}
+
+func TestObjectsRevert(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	_, err := objApi.Revert(objCode, 1)
+	assert.Equal(api.ErrRevisionNotFound, err)
+
+	objApi.Create(&api.ObjectInfo{Code: objCode, Description: "first", Parameters: boolParam(false)})
+	objApi.Update(&api.ObjectInfo{Code: objCode, Description: "second", Parameters: boolParam(true)})
+
+	obj, err := objApi.Revert(objCode, 1)
+	assert.Nil(err)
+	assert.Equal("first", obj.Description)
+	assert.Equal(false, obj.Parameters[0].Value)
+	assert.Equal(int64(3), obj.Version)
+
+	_, err = objApi.Revert(objCode, 10)
+	assert.Equal(api.ErrRevisionNotFound, err)
+
+	assert.Nil(objApi.Delete(objCode, 0))
+	_, err = objApi.Revert(objCode, 4)
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	obj, err = objApi.Revert(objCode, 2)
+	assert.Nil(err)
+	assert.Equal("second", obj.Description)
+	assert.Equal(true, obj.Parameters[0].Value)
+
+	list, err := objApi.History(objCode)
+	assert.Nil(err)
+	assert.Len(list, 5)
+	assert.Equal(domain.RevisionRevert, list[2].Action)
+	assert.Equal(int64(1), list[2].RevertedTo)
+	assert.Equal(domain.RevisionRevert, list[4].Action)
+	assert.Equal(int64(2), list[4].RevertedTo)
+	assert.Equal("", list[4].Actor)
+
+	// restored state is checked the same way as on update
+	objApi.Create(&api.ObjectInfo{Code: "parent", Parameters: boolParam(false)})
+	inherits := &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "parent"}
+	objApi.Create(&api.ObjectInfo{Code: "child", Inherits: inherits})
+	objApi.Update(&api.ObjectInfo{Code: "child"})
+	objApi.Delete("parent", 0)
+	_, err = objApi.Revert("child", 1)
+	assert.Equal(api.ErrObjectParentNotExists, err)
+
+	AfterTest()
+}
+
+// conflictStorage fails every revision save with unique index error, as if other instances saved the revision first
+type conflictStorage struct{ storage.DataStorage }
+type conflictOwner struct{ storage.OwnerStorage }
+type conflictProjects struct{ storage.ProjectStorage }
+type conflictForProject struct{ storage.ForProject }
+type conflictEnvs struct{ storage.EnvironmentStorage }
+type conflictForEnv struct{ storage.ForEnvironment }
+type conflictRevisions struct{ storage.RevisionStorage }
+
+func (s conflictStorage) ForOwner(id string) storage.OwnerStorage {
+	return conflictOwner{s.DataStorage.ForOwner(id)}
+}
+func (s conflictOwner) Projects() storage.ProjectStorage {
+	return conflictProjects{s.OwnerStorage.Projects()}
+}
+func (s conflictProjects) For(code domain.ProjectCode) storage.ForProject {
+	return conflictForProject{s.ProjectStorage.For(code)}
+}
+func (s conflictForProject) Environments() storage.EnvironmentStorage {
+	return conflictEnvs{s.ForProject.Environments()}
+}
+func (s conflictEnvs) For(code domain.EnvironmentCode) storage.ForEnvironment {
+	return conflictForEnv{s.EnvironmentStorage.For(code)}
+}
+func (s conflictForEnv) Revisions() storage.RevisionStorage {
+	return conflictRevisions{s.ForEnvironment.Revisions()}
+}
+func (s conflictRevisions) Save(rev *domain.ObjectRevision) error {
+	return &storage.UniqueIndexError{Type: "revision", Key: string(rev.ObjectCode)}
+}
+
+func TestRevisionNumberingConflict(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	var conflicts storage.DataStorage = conflictStorage{dataStorage}
+	pApi := (&engine.Engine{Storage: &conflicts}).ForOwner(ow).Projects()
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	pApi.For(ProjectCode).Environments().Create(&api.EnvironmentInfo{Code: envCode})
+
+	out := &bytes.Buffer{}
+	log.SetOutput(out)
+	defer log.SetOutput(os.Stderr)
+	_, err := pApi.For(ProjectCode).Environments().For(envCode).Objects().Create(&api.ObjectInfo{Code: objCode, Parameters: boolParam(false)})
+	assert.Nil(err, "object is saved")
+	assert.Contains(out.String(), "[ERROR] Can't save revision of object "+string(objCode)+": Unique index error")
+
+	AfterTest()
+}
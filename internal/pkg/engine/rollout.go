@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// bucketsCount defines rollout precision, 1000 buckets per percent
+const bucketsCount = 100000
+
+func checkRollout(p *domain.Parameter) error {
+	r := p.Rollout
+	if r == nil {
+		return nil
+	}
+	fail := func(msg string) error {
+		return api.NewObjectParameterError(string(p.Code), "Rollout: "+msg)
+	}
+	if p.Type != domain.ParameterBool && p.Type != domain.ParameterEnum {
+		return fail("can be specified for bool and enum parameters only")
+	}
+	if len(r.Variations) == 0 {
+		return fail("variations not specified")
+	}
+	total := 0.0
+	for i, v := range r.Variations {
+		if v == nil || v.Value == nil {
+			return fail(fmt.Sprintf("variation %d value not specified", i+1))
+		}
+		if v.Weight < 0 || math.IsNaN(v.Weight) || math.IsInf(v.Weight, 0) {
+			return fail(fmt.Sprintf("variation %d weight is not valid", i+1))
+		}
+		total += v.Weight
+	}
+	if math.Abs(total-100) > 1e-9 {
+		return fail("variation weights must sum up to 100")
+	}
+	return nil
+}
+
+func normalizeRollout(def *domain.Parameter, r *domain.Rollout) (*domain.Rollout, error) {
+	if r == nil {
+		return nil, nil
+	}
+	nr := *r
+	nr.Variations = make([]*domain.Variation, len(r.Variations))
+	for i, v := range r.Variations {
+		nv := *v
+		var err error
+		if nv.Value, err = parameterValue(def, v.Value); err != nil {
+			return nil, err
+		}
+		nr.Variations[i] = &nv
+	}
+	return &nr, nil
+}
+
+// bucket maps the key to a percentage in [0, 100) range.
+// Result depends on salt and key only, so the same user lands in the same bucket on every instance.
+func bucket(salt, key string) float64 {
+	sum := sha1.Sum([]byte(salt + ":" + key))
+	return float64(binary.BigEndian.Uint64(sum[:8])%bucketsCount) * 100 / bucketsCount
+}
+
+// evaluateRollout returns variation value for the context.
+// Bucketing attribute defaults to user id, salt defaults to parameter code.
+// False is returned if the context has no bucketing attribute.
+func evaluateRollout(p *domain.Parameter, ctx *domain.EvaluationContext) (interface{}, bool) {
+	r := p.Rollout
+	attr := r.Attribute
+	if attr == "" {
+		attr = domain.ContextUserID
+	}
+	key, ok := ctx.Attribute(attr)
+	if !ok || key == "" {
+		return nil, false
+	}
+	salt := r.Salt
+	if salt == "" {
+		salt = string(p.Code)
+	}
+	b := bucket(salt, key)
+	cumulative := 0.0
+	for _, v := range r.Variations {
+		cumulative += v.Weight
+		if b < cumulative {
+			return v.Value, true
+		}
+	}
+	return nil, false
+}
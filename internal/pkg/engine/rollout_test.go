@@ -0,0 +1,180 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRolloutValidation(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	variations := func(values ...interface{}) []*domain.Variation {
+		res := make([]*domain.Variation, 0, len(values)/2)
+		for i := 0; i < len(values); i += 2 {
+			res = append(res, &domain.Variation{Value: values[i], Weight: values[i+1].(float64)})
+		}
+		return res
+	}
+
+	tt := []struct {
+		name  string
+		param *domain.Parameter
+		err   string
+	}{
+		{"bool", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{Variations: variations(true, 10.0, false, 90.0)}}, ""},
+		{"enum", &domain.Parameter{Type: domain.ParameterEnum, Value: "red", AllowedValues: []string{"red", "blue"}, Rollout: &domain.Rollout{Attribute: "team", Salt: "s1", Variations: variations("blue", 33.3, "red", 66.7)}}, ""},
+		{"wrong type", &domain.Parameter{Type: domain.ParameterInt, Value: 1, Rollout: &domain.Rollout{Variations: variations(2, 100.0)}}, "Rollout: can be specified for bool and enum parameters only"},
+		{"no variations", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{}}, "Rollout: variations not specified"},
+		{"no value", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{Variations: variations(nil, 100.0)}}, "Rollout: variation 1 value not specified"},
+		{"negative weight", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{Variations: variations(true, 110.0, false, -10.0)}}, "Rollout: variation 2 weight is not valid"},
+		{"wrong sum", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{Variations: variations(true, 10.0, false, 80.0)}}, "Rollout: variation weights must sum up to 100"},
+		{"wrong value", &domain.Parameter{Type: domain.ParameterBool, Value: false, Rollout: &domain.Rollout{Variations: variations("yes", 100.0)}}, "Value `yes` is not valid bool"},
+		{"not allowed value", &domain.Parameter{Type: domain.ParameterEnum, Value: "red", AllowedValues: []string{"red", "blue"}, Rollout: &domain.Rollout{Variations: variations("green", 100.0)}}, "Value `green` is not valid enum"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.param.Code = "param1"
+			_, err := objApi.Create(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{tc.param}})
+			if tc.err != "" {
+				assert.IsType(&api.ErrObjectParameter{}, err)
+				assert.Equal("Object parameter `param1` error: "+tc.err, err.Error())
+				return
+			}
+			assert.Nil(err)
+			objApi.Delete("obj1", 0)
+		})
+	}
+
+	AfterTest()
+}
+
+func TestRolloutEvaluate(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	_, err := objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "new_ui",
+				Type:  domain.ParameterBool,
+				Value: false,
+				Rules: []*domain.Rule{
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"beta"}},
+						},
+						Value: true,
+					},
+				},
+				Rollout: &domain.Rollout{
+					Variations: []*domain.Variation{{Value: true, Weight: 20}, {Value: false, Weight: 80}},
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	countEnabled := func(code domain.ObjectCode) (int, map[string]bool) {
+		enabled := 0
+		users := make(map[string]bool)
+		for i := 0; i < 1000; i++ {
+			user := fmt.Sprintf("user-%d", i)
+			values, err := objApi.Evaluate(code, &domain.EvaluationContext{UserID: user})
+			assert.Nil(err)
+			if values["new_ui"] == true {
+				enabled++
+				users[user] = true
+			}
+		}
+		return enabled, users
+	}
+
+	enabled, users := countEnabled("obj1")
+	assert.InDelta(200, enabled, 50)
+
+	again, sameUsers := countEnabled("obj1")
+	assert.Equal(enabled, again)
+	assert.Equal(users, sameUsers)
+
+	values, err := objApi.Evaluate("obj1", &domain.EvaluationContext{})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": false}, values)
+
+	values, err = objApi.Evaluate("obj1", &domain.EvaluationContext{UserID: "user-1", Attributes: map[string]string{"plan": "beta"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true}, values)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "new_ui",
+				Type:  domain.ParameterBool,
+				Value: false,
+				Rollout: &domain.Rollout{
+					Variations: []*domain.Variation{{Value: true, Weight: 50}, {Value: false, Weight: 50}},
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	inherited, inheritedUsers := countEnabled("obj2")
+	assert.InDelta(500, inherited, 60)
+	for user := range users {
+		assert.True(inheritedUsers[user], "user %s should stay in rollout when percentage grows", user)
+	}
+
+	values, err = objApi.Evaluate("obj2", &domain.EvaluationContext{UserID: "user-1", Attributes: map[string]string{"plan": "beta"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true}, values)
+
+	cleared := &domain.Parameter{}
+	assert.Nil(json.Unmarshal([]byte(`{"code":"new_ui","type":"bool","value":false,"rules":[],"rollout":null}`), cleared))
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:       "obj3",
+		Inherits:   &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{cleared},
+	})
+	assert.Nil(err)
+
+	none, _ := countEnabled("obj3")
+	assert.Equal(0, none, "inherited rollout is cleared")
+	values, err = objApi.Evaluate("obj3", &domain.EvaluationContext{UserID: "user-1", Attributes: map[string]string{"plan": "beta"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": false}, values, "inherited rules are cleared")
+
+	obj, err := objApi.Get("obj3")
+	assert.Nil(err)
+	data, err := json.Marshal(obj.Parameters[0])
+	assert.Nil(err)
+	assert.Equal(`{"code":"new_ui","description":"","type":"bool","value":false,"rules":[],"rollout":null}`, string(data))
+
+	AfterTest()
+}
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Toggly/core/internal/pkg/semver"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+func isRuleOperator(op domain.RuleOperator) bool {
+	switch op {
+	case domain.RuleOpEquals,
+		domain.RuleOpNotEquals,
+		domain.RuleOpIn,
+		domain.RuleOpNotIn,
+		domain.RuleOpRegex,
+		domain.RuleOpSemverEq,
+		domain.RuleOpSemverGt,
+		domain.RuleOpSemverGte,
+		domain.RuleOpSemverLt,
+		domain.RuleOpSemverLte:
+		return true
+	}
+	return false
+}
+
+func isMultiValueOperator(op domain.RuleOperator) bool {
+	return op == domain.RuleOpIn || op == domain.RuleOpNotIn
+}
+
+func isSemverOperator(op domain.RuleOperator) bool {
+	switch op {
+	case domain.RuleOpSemverEq, domain.RuleOpSemverGt, domain.RuleOpSemverGte, domain.RuleOpSemverLt, domain.RuleOpSemverLte:
+		return true
+	}
+	return false
+}
+
+func checkRules(p *domain.Parameter) error {
+	for i, r := range p.Rules {
+		fail := func(msg string) error {
+			return api.NewObjectParameterError(string(p.Code), fmt.Sprintf("Rule %d: %s", i+1, msg))
+		}
+		if r == nil {
+			return fail("rule not specified")
+		}
+		if r.Value == nil {
+			return fail("value not specified")
+		}
+		if len(r.Conditions) == 0 && r.Segment == "" {
+			return fail("conditions not specified")
+		}
+		for _, c := range r.Conditions {
+			if msg := checkCondition(c); msg != "" {
+				return fail(msg)
+			}
+		}
+	}
+	return nil
+}
+
+// checkCondition returns description of the condition error or empty string if condition is valid
+func checkCondition(c *domain.RuleCondition) string {
+	if c == nil || c.Attribute == "" {
+		return "condition attribute not specified"
+	}
+	if !isRuleOperator(c.Operator) {
+		return fmt.Sprintf("unknown operator `%s`", c.Operator)
+	}
+	if len(c.Values) == 0 {
+		return fmt.Sprintf("operator `%s` requires values", c.Operator)
+	}
+	if !isMultiValueOperator(c.Operator) && len(c.Values) != 1 {
+		return fmt.Sprintf("operator `%s` requires single value", c.Operator)
+	}
+	if c.Operator == domain.RuleOpRegex {
+		if _, err := regexp.Compile(c.Values[0]); err != nil {
+			return fmt.Sprintf("regex error: %v", err)
+		}
+	}
+	if isSemverOperator(c.Operator) {
+		if _, err := semver.Parse(c.Values[0]); err != nil {
+			return fmt.Sprintf("value `%s` is not valid semver", c.Values[0])
+		}
+	}
+	return ""
+}
+
+// Evaluate returns object parameter values for the context.
+// Rules of each parameter are checked in order, value of the first matched rule is used.
+// Rollout variation is used if no rule matched, parameter value is used otherwise.
+// Segments referenced by rules are looked up in segments map.
+func Evaluate(obj *domain.Object, segments map[domain.SegmentCode]*domain.Segment, ctx *domain.EvaluationContext) domain.ObjectValues {
+	values := make(domain.ObjectValues, len(obj.Parameters))
+	for _, p := range obj.Parameters {
+		values[p.Code] = evaluateParameter(p, segments, ctx)
+	}
+	return values
+}
+
+// EvaluateAll returns parameter values of the objects for the context
+func EvaluateAll(objects []*domain.Object, segmentAPI api.SegmentAPI, ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	segments, err := ObjectSegments(segmentAPI, objects...)
+	if err != nil {
+		return nil, err
+	}
+	values := make(domain.EnvironmentValues, len(objects))
+	for _, obj := range objects {
+		values[obj.Code] = Evaluate(obj, segments, ctx)
+	}
+	return values, nil
+}
+
+func evaluateParameter(p *domain.Parameter, segments map[domain.SegmentCode]*domain.Segment, ctx *domain.EvaluationContext) interface{} {
+	for _, r := range p.Rules {
+		if matchRule(r, segments, ctx) {
+			return r.Value
+		}
+	}
+	if p.Rollout != nil {
+		if v, ok := evaluateRollout(p, ctx); ok {
+			return v
+		}
+	}
+	return p.Value
+}
+
+func matchRule(r *domain.Rule, segments map[domain.SegmentCode]*domain.Segment, ctx *domain.EvaluationContext) bool {
+	if r.Segment != "" && !matchSegment(segments[r.Segment], ctx) {
+		return false
+	}
+	return matchConditions(r.Conditions, ctx)
+}
+
+func matchConditions(conditions []*domain.RuleCondition, ctx *domain.EvaluationContext) bool {
+	for _, c := range conditions {
+		if !matchCondition(c, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCondition(c *domain.RuleCondition, ctx *domain.EvaluationContext) bool {
+	value, ok := ctx.Attribute(c.Attribute)
+	if !ok || len(c.Values) == 0 {
+		return false
+	}
+	switch c.Operator {
+	case domain.RuleOpEquals:
+		return value == c.Values[0]
+	case domain.RuleOpNotEquals:
+		return value != c.Values[0]
+	case domain.RuleOpIn:
+		return contains(c.Values, value)
+	case domain.RuleOpNotIn:
+		return !contains(c.Values, value)
+	case domain.RuleOpRegex:
+		re, err := regexp.Compile(c.Values[0])
+		return err == nil && re.MatchString(value)
+	}
+	if isSemverOperator(c.Operator) {
+		return matchSemver(c.Operator, value, c.Values[0])
+	}
+	return false
+}
+
+func matchSemver(op domain.RuleOperator, value, expected string) bool {
+	v, err := semver.Parse(value)
+	if err != nil {
+		return false
+	}
+	e, err := semver.Parse(expected)
+	if err != nil {
+		return false
+	}
+	cmp := v.Compare(e)
+	switch op {
+	case domain.RuleOpSemverEq:
+		return cmp == 0
+	case domain.RuleOpSemverGt:
+		return cmp > 0
+	case domain.RuleOpSemverGte:
+		return cmp >= 0
+	case domain.RuleOpSemverLt:
+		return cmp < 0
+	case domain.RuleOpSemverLte:
+		return cmp <= 0
+	}
+	return false
+}
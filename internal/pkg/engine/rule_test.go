@@ -0,0 +1,265 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRulesValidation(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	cond := func(attr string, op domain.RuleOperator, values ...string) []*domain.RuleCondition {
+		return []*domain.RuleCondition{{Attribute: attr, Operator: op, Values: values}}
+	}
+
+	tt := []struct {
+		name string
+		rule *domain.Rule
+		err  string
+	}{
+		{"valid", &domain.Rule{Conditions: cond("country", domain.RuleOpIn, "US", "CA"), Value: true}, ""},
+		{"no value", &domain.Rule{Conditions: cond("country", domain.RuleOpIn, "US")}, "Rule 1: value not specified"},
+		{"wrong value", &domain.Rule{Conditions: cond("country", domain.RuleOpIn, "US"), Value: "yes"}, "Value `yes` is not valid bool"},
+		{"no conditions", &domain.Rule{Value: true}, "Rule 1: conditions not specified"},
+		{"no attribute", &domain.Rule{Conditions: cond("", domain.RuleOpEquals, "US"), Value: true}, "Rule 1: condition attribute not specified"},
+		{"unknown operator", &domain.Rule{Conditions: cond("country", "like", "US"), Value: true}, "Rule 1: unknown operator `like`"},
+		{"no values", &domain.Rule{Conditions: cond("country", domain.RuleOpIn), Value: true}, "Rule 1: operator `in` requires values"},
+		{"many values", &domain.Rule{Conditions: cond("country", domain.RuleOpEquals, "US", "CA"), Value: true}, "Rule 1: operator `equals` requires single value"},
+		{"wrong regex", &domain.Rule{Conditions: cond("email", domain.RuleOpRegex, "("), Value: true}, "Rule 1: regex error: error parsing regexp: missing closing ): `(`"},
+		{"wrong semver", &domain.Rule{Conditions: cond("app_version", domain.RuleOpSemverGte, "1.2"), Value: true}, "Rule 1: value `1.2` is not valid semver"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := objApi.Create(&api.ObjectInfo{
+				Code: "obj1",
+				Parameters: []*domain.Parameter{
+					{Code: "param1", Type: domain.ParameterBool, Value: false, Rules: []*domain.Rule{tc.rule}},
+				},
+			})
+			if tc.err != "" {
+				assert.IsType(&api.ErrObjectParameter{}, err)
+				assert.Equal("Object parameter `param1` error: "+tc.err, err.Error())
+				return
+			}
+			assert.Nil(err)
+			objApi.Delete("obj1", 0)
+		})
+	}
+
+	AfterTest()
+}
+
+func TestEvaluate(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	_, err := objApi.Evaluate("obj1", &domain.EvaluationContext{})
+	assert.Equal(api.ErrObjectNotFound, err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "new_ui",
+				Type:  domain.ParameterBool,
+				Value: false,
+				Rules: []*domain.Rule{
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: domain.ContextUserID, Operator: domain.RuleOpIn, Values: []string{"u1", "u2"}},
+						},
+						Value: true,
+					},
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: "email", Operator: domain.RuleOpRegex, Values: []string{"@toggly\\.io$"}},
+							{Attribute: "app_version", Operator: domain.RuleOpSemverGte, Values: []string{"2.0.0"}},
+						},
+						Value: true,
+					},
+				},
+			},
+			{
+				Code:  "limit",
+				Type:  domain.ParameterInt,
+				Value: 10,
+				Rules: []*domain.Rule{
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"pro"}},
+						},
+						Value: 100,
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	tt := []struct {
+		name  string
+		ctx   *domain.EvaluationContext
+		newUI bool
+		limit int64
+	}{
+		{"default", &domain.EvaluationContext{}, false, 10},
+		{"user id", &domain.EvaluationContext{UserID: "u2"}, true, 10},
+		{"user id attribute", &domain.EvaluationContext{Attributes: map[string]string{"user_id": "u1"}}, true, 10},
+		{"all conditions", &domain.EvaluationContext{Attributes: map[string]string{"email": "dev@toggly.io", "app_version": "2.1.0"}}, true, 10},
+		{"not all conditions", &domain.EvaluationContext{Attributes: map[string]string{"email": "dev@toggly.io", "app_version": "1.9.9"}}, false, 10},
+		{"wrong semver", &domain.EvaluationContext{Attributes: map[string]string{"email": "dev@toggly.io", "app_version": "latest"}}, false, 10},
+		{"plan", &domain.EvaluationContext{UserID: "u3", Attributes: map[string]string{"plan": "pro"}}, false, 100},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := objApi.Evaluate("obj1", tc.ctx)
+			assert.Nil(err)
+			assert.Equal(domain.ObjectValues{"new_ui": tc.newUI, "limit": tc.limit}, values)
+		})
+	}
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "limit",
+				Type:  domain.ParameterInt,
+				Value: 20,
+				Rules: []*domain.Rule{
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: "plan", Operator: domain.RuleOpNotIn, Values: []string{"free"}},
+						},
+						Value: 200,
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	values, err := objApi.Evaluate("obj2", &domain.EvaluationContext{UserID: "u1", Attributes: map[string]string{"plan": "pro"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true, "limit": int64(200)}, values)
+
+	values, err = objApi.Evaluate("obj2", &domain.EvaluationContext{Attributes: map[string]string{"plan": "free"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": false, "limit": int64(20)}, values)
+
+	_, err = objApi.Update(&api.ObjectInfo{
+		Code: "obj1",
+		Parameters: []*domain.Parameter{
+			{Code: "new_ui", Type: domain.ParameterBool, Value: false},
+			{
+				Code:        "limit",
+				Type:        domain.ParameterInt,
+				Value:       10,
+				Constraints: &domain.ParameterConstraints{Max: floatPtr(100)},
+			},
+		},
+	})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `limit` error: Object parameter is not valid for inheritors: p1:env_1:obj2", err.Error())
+
+	AfterTest()
+}
+
+func TestEvaluateAll(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	_, err := objApi.EvaluateAll(&domain.EvaluationContext{})
+	assert.Equal(api.ErrProjectNotFound, err)
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+
+	_, err = objApi.EvaluateAll(&domain.EvaluationContext{})
+	assert.Equal(api.ErrEnvironmentNotFound, err)
+
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+	envApi.Create(&api.EnvironmentInfo{Code: "env_2"})
+
+	values, err := objApi.EvaluateAll(&domain.EvaluationContext{})
+	assert.Nil(err)
+	assert.Empty(values)
+
+	_, err = envApi.For("env_2").Objects().Create(&api.ObjectInfo{
+		Code: "base",
+		Parameters: []*domain.Parameter{
+			{Code: "color", Type: domain.ParameterString, Value: "red"},
+		},
+	})
+	assert.Nil(err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj1",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: "env_2", ObjectCode: "base"},
+		Parameters: []*domain.Parameter{
+			{
+				Code:  "new_ui",
+				Type:  domain.ParameterBool,
+				Value: false,
+				Rules: []*domain.Rule{
+					{
+						Conditions: []*domain.RuleCondition{
+							{Attribute: domain.ContextUserID, Operator: domain.RuleOpEquals, Values: []string{"u1"}},
+						},
+						Value: true,
+					},
+				},
+			},
+		},
+	})
+	assert.Nil(err)
+
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "obj2",
+		Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "obj1"},
+		Parameters: []*domain.Parameter{
+			{Code: "limit", Type: domain.ParameterInt, Value: 5},
+		},
+	})
+	assert.Nil(err)
+
+	values, err = objApi.EvaluateAll(&domain.EvaluationContext{UserID: "u1"})
+	assert.Nil(err)
+	assert.Equal(domain.EnvironmentValues{
+		"obj1": {"color": "red", "new_ui": true},
+		"obj2": {"color": "red", "new_ui": true, "limit": int64(5)},
+	}, values)
+
+	values, err = objApi.EvaluateAll(&domain.EvaluationContext{UserID: "u2"})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"color": "red", "new_ui": false, "limit": int64(5)}, values["obj2"])
+
+	AfterTest()
+}
@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+// SegmentAPI type
+type SegmentAPI struct {
+	Owner       string
+	ProjectCode domain.ProjectCode
+	Storage     *storage.DataStorage
+	ProjectAPI  *ProjectAPI
+}
+
+func (s *SegmentAPI) projectExists(access projectAccess) error {
+	return s.ProjectAPI.check(s.ProjectCode, access)
+}
+
+func (s *SegmentAPI) storage() storage.SegmentStorage {
+	return (*s.Storage).ForOwner(s.Owner).Projects().For(s.ProjectCode).Segments()
+}
+
+// List returns list of project segments
+func (s *SegmentAPI) List() ([]*domain.Segment, error) {
+	if err := s.projectExists(accessRead); err != nil {
+		return nil, err
+	}
+	return s.storage().List()
+}
+
+// Get returns segment by code
+func (s *SegmentAPI) Get(code domain.SegmentCode) (*domain.Segment, error) {
+	if err := s.projectExists(accessRead); err != nil {
+		return nil, err
+	}
+	segment, err := s.storage().Get(code)
+	if err == storage.ErrNotFound {
+		return nil, api.ErrSegmentNotFound
+	}
+	return segment, err
+}
+
+func checkSegmentParams(info *api.SegmentInfo) error {
+	if info.Code == "" {
+		return api.NewBadRequestError("Segment code not specified")
+	}
+	for i, r := range info.Rules {
+		if r == nil || len(r.Conditions) == 0 {
+			return api.NewBadRequestError(fmt.Sprintf("Segment rule %d: conditions not specified", i+1))
+		}
+		for _, c := range r.Conditions {
+			if msg := checkCondition(c); msg != "" {
+				return api.NewBadRequestError(fmt.Sprintf("Segment rule %d: %s", i+1, msg))
+			}
+		}
+	}
+	return nil
+}
+
+// Create segment
+func (s *SegmentAPI) Create(info *api.SegmentInfo) (*domain.Segment, error) {
+	if err := s.projectExists(accessWrite); err != nil {
+		return nil, err
+	}
+	if err := checkSegmentParams(info); err != nil {
+		return nil, err
+	}
+	newSegment := &domain.Segment{
+		OwnerID:     s.Owner,
+		ProjectCode: s.ProjectCode,
+		Code:        info.Code,
+		Description: info.Description,
+		Include:     info.Include,
+		Exclude:     info.Exclude,
+		Rules:       info.Rules,
+		RegDate:     bson.Now().In(time.UTC),
+	}
+	if err := s.storage().Save(newSegment); err != nil {
+		return nil, err
+	}
+	s.publish(domain.EventSegmentCreate, info.Code, newSegment)
+	return newSegment, nil
+}
+
+// Update segment
+func (s *SegmentAPI) Update(info *api.SegmentInfo) (*domain.Segment, error) {
+	if err := s.projectExists(accessWrite); err != nil {
+		return nil, err
+	}
+	if err := checkSegmentParams(info); err != nil {
+		return nil, err
+	}
+	segment, err := s.Get(info.Code)
+	if err != nil {
+		return nil, err
+	}
+	newSegment := &domain.Segment{
+		OwnerID:     s.Owner,
+		ProjectCode: s.ProjectCode,
+		Code:        info.Code,
+		Description: info.Description,
+		Include:     info.Include,
+		Exclude:     info.Exclude,
+		Rules:       info.Rules,
+		RegDate:     segment.RegDate,
+	}
+	if err := s.storage().Update(newSegment); err != nil {
+		return nil, err
+	}
+	s.publish(domain.EventSegmentUpdate, info.Code, newSegment)
+	return newSegment, nil
+}
+
+// Delete segment. Segment referenced by object rules can't be deleted.
+func (s *SegmentAPI) Delete(code domain.SegmentCode) error {
+	if err := s.projectExists(accessWrite); err != nil {
+		return err
+	}
+	used, err := s.isUsed(code)
+	if err != nil {
+		return err
+	}
+	if used {
+		return api.ErrSegmentInUse
+	}
+	err = s.storage().Delete(code)
+	if err == storage.ErrNotFound {
+		return api.ErrSegmentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.publish(domain.EventSegmentDelete, code, nil)
+	return nil
+}
+
+func (s *SegmentAPI) publish(eventType domain.EventType, code domain.SegmentCode, segment *domain.Segment) {
+	event := s.ProjectAPI.newEvent(eventType, s.ProjectCode)
+	event.SegmentCode = code
+	if segment != nil {
+		event.Data = segment
+	}
+	s.ProjectAPI.publish(event)
+}
+
+func (s *SegmentAPI) isUsed(code domain.SegmentCode) (bool, error) {
+	envStorage := (*s.Storage).ForOwner(s.Owner).Projects().For(s.ProjectCode).Environments()
+	envList, err := envStorage.List()
+	if err != nil {
+		return false, err
+	}
+	for _, env := range envList {
+		objList, err := envStorage.For(env.Code).Objects().List()
+		if err != nil {
+			return false, err
+		}
+		for _, obj := range objList {
+			for _, p := range obj.Parameters {
+				for _, r := range p.Rules {
+					if r != nil && r.Segment == code {
+						return true, nil
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// ObjectSegments returns segments referenced by parameter rules of the objects.
+// Segments which don't exist are skipped, rules referencing them never match.
+func ObjectSegments(segmentAPI api.SegmentAPI, objects ...*domain.Object) (map[domain.SegmentCode]*domain.Segment, error) {
+	segments := make(map[domain.SegmentCode]*domain.Segment)
+	for _, obj := range objects {
+		for _, p := range obj.Parameters {
+			for _, r := range p.Rules {
+				if r.Segment == "" {
+					continue
+				}
+				if _, ok := segments[r.Segment]; ok {
+					continue
+				}
+				segment, err := segmentAPI.Get(r.Segment)
+				if err != nil && err != api.ErrSegmentNotFound {
+					return nil, err
+				}
+				segments[r.Segment] = segment
+			}
+		}
+	}
+	return segments, nil
+}
+
+func matchSegment(s *domain.Segment, ctx *domain.EvaluationContext) bool {
+	if s == nil {
+		return false
+	}
+	if userID, ok := ctx.Attribute(domain.ContextUserID); ok && userID != "" {
+		if contains(s.Exclude, userID) {
+			return false
+		}
+		if contains(s.Include, userID) {
+			return true
+		}
+	}
+	for _, r := range s.Rules {
+		if matchConditions(r.Conditions, ctx) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const segmentCode = domain.SegmentCode("beta")
+
+func TestSegmentWithNoProject(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	segApi := GetApi().For(ProjectCode).Segments()
+
+	list, err := segApi.List()
+	assert.Equal(api.ErrProjectNotFound, err)
+	assert.Nil(list)
+
+	seg, err := segApi.Get(segmentCode)
+	assert.Equal(api.ErrProjectNotFound, err)
+	assert.Nil(seg)
+
+	seg, err = segApi.Create(&api.SegmentInfo{Code: segmentCode})
+	assert.Equal(api.ErrProjectNotFound, err)
+	assert.Nil(seg)
+
+	seg, err = segApi.Update(&api.SegmentInfo{Code: segmentCode})
+	assert.Equal(api.ErrProjectNotFound, err)
+	assert.Nil(seg)
+
+	err = segApi.Delete(segmentCode)
+	assert.Equal(api.ErrProjectNotFound, err)
+
+	AfterTest()
+}
+
+func TestSegment(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	segApi := pApi.For(ProjectCode).Segments()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+
+	list, err := segApi.List()
+	assert.Nil(err)
+	assert.Empty(list)
+
+	seg, err := segApi.Get(segmentCode)
+	assert.Equal(api.ErrSegmentNotFound, err)
+	assert.Nil(seg)
+
+	seg, err = segApi.Update(&api.SegmentInfo{Code: segmentCode})
+	assert.Equal(api.ErrSegmentNotFound, err)
+	assert.Nil(seg)
+
+	err = segApi.Delete(segmentCode)
+	assert.Equal(api.ErrSegmentNotFound, err)
+
+	_, err = segApi.Create(&api.SegmentInfo{})
+	assert.Equal(api.NewBadRequestError("Segment code not specified"), err)
+
+	_, err = segApi.Create(&api.SegmentInfo{Code: segmentCode, Rules: []*domain.SegmentRule{{}}})
+	assert.Equal(api.NewBadRequestError("Segment rule 1: conditions not specified"), err)
+
+	_, err = segApi.Create(&api.SegmentInfo{
+		Code:  segmentCode,
+		Rules: []*domain.SegmentRule{{Conditions: []*domain.RuleCondition{{Attribute: "plan", Operator: "like", Values: []string{"pro"}}}}},
+	})
+	assert.Equal(api.NewBadRequestError("Segment rule 1: unknown operator `like`"), err)
+
+	seg, err = segApi.Create(&api.SegmentInfo{
+		Code:        segmentCode,
+		Description: "Beta testers",
+		Include:     []string{"u1"},
+		Exclude:     []string{"u2"},
+		Rules:       []*domain.SegmentRule{{Conditions: []*domain.RuleCondition{{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"beta"}}}}},
+	})
+	assert.Nil(err)
+	assert.Equal(segmentCode, seg.Code)
+	assert.Equal(ProjectCode, seg.ProjectCode)
+	assert.Equal("Beta testers", seg.Description)
+
+	_, err = segApi.Create(&api.SegmentInfo{Code: segmentCode})
+	assert.IsType(&storage.UniqueIndexError{}, err)
+
+	seg, err = segApi.Update(&api.SegmentInfo{Code: segmentCode, Description: "Beta", Include: []string{"u1", "u3"}})
+	assert.Nil(err)
+	assert.Equal("Beta", seg.Description)
+
+	seg, err = segApi.Get(segmentCode)
+	assert.Nil(err)
+	assert.Equal([]string{"u1", "u3"}, seg.Include)
+	assert.Empty(seg.Rules)
+
+	list, err = segApi.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+
+	err = segApi.Delete(segmentCode)
+	assert.Nil(err)
+
+	AfterTest()
+}
+
+func TestSegmentRules(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	segApi := pApi.For(ProjectCode).Segments()
+	envApi := pApi.For(ProjectCode).Environments()
+	objApi := envApi.For(envCode).Objects()
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	envApi.Create(&api.EnvironmentInfo{Code: envCode})
+
+	param := &domain.Parameter{
+		Code:  "new_ui",
+		Type:  domain.ParameterBool,
+		Value: false,
+		Rules: []*domain.Rule{{Segment: segmentCode, Value: true}},
+	}
+
+	_, err := objApi.Create(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{param}})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `new_ui` error: Rule 1: segment `beta` not found", err.Error())
+
+	_, err = segApi.Create(&api.SegmentInfo{
+		Code:    segmentCode,
+		Include: []string{"u1", "u2"},
+		Exclude: []string{"u3"},
+		Rules: []*domain.SegmentRule{
+			{Conditions: []*domain.RuleCondition{{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"beta"}}}},
+			{Conditions: []*domain.RuleCondition{{Attribute: "email", Operator: domain.RuleOpRegex, Values: []string{"@toggly\\.io$"}}}},
+		},
+	})
+	assert.Nil(err)
+
+	_, err = objApi.Create(&api.ObjectInfo{Code: "obj1", Parameters: []*domain.Parameter{param}})
+	assert.Nil(err)
+
+	tt := []struct {
+		name  string
+		ctx   *domain.EvaluationContext
+		newUI bool
+	}{
+		{"default", &domain.EvaluationContext{}, false},
+		{"included", &domain.EvaluationContext{UserID: "u1"}, true},
+		{"excluded", &domain.EvaluationContext{UserID: "u3", Attributes: map[string]string{"plan": "beta"}}, false},
+		{"first rule", &domain.EvaluationContext{UserID: "u4", Attributes: map[string]string{"plan": "beta"}}, true},
+		{"second rule", &domain.EvaluationContext{Attributes: map[string]string{"email": "dev@toggly.io"}}, true},
+		{"no rule", &domain.EvaluationContext{UserID: "u4", Attributes: map[string]string{"plan": "free"}}, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			values, err := objApi.Evaluate("obj1", tc.ctx)
+			assert.Nil(err)
+			assert.Equal(domain.ObjectValues{"new_ui": tc.newUI}, values)
+		})
+	}
+
+	_, err = segApi.Update(&api.SegmentInfo{Code: segmentCode, Include: []string{"u4"}})
+	assert.Nil(err)
+
+	values, err := objApi.Evaluate("obj1", &domain.EvaluationContext{UserID: "u1"})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": false}, values)
+
+	values, err = objApi.Evaluate("obj1", &domain.EvaluationContext{UserID: "u4"})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true}, values)
+
+	err = segApi.Delete(segmentCode)
+	assert.Equal(api.ErrSegmentInUse, err)
+
+	err = objApi.Delete("obj1", 0)
+	assert.Nil(err)
+
+	err = segApi.Delete(segmentCode)
+	assert.Nil(err)
+
+	AfterTest()
+}
+
+func TestSegmentRulesOfOtherProject(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	pApi := GetApi()
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+	pApi.Create(&api.ProjectInfo{Code: "p2", Status: domain.ProjectStatusActive})
+	pApi.For(ProjectCode).Environments().Create(&api.EnvironmentInfo{Code: envCode})
+	pApi.For("p2").Environments().Create(&api.EnvironmentInfo{Code: envCode})
+	_, err := pApi.For(ProjectCode).Segments().Create(&api.SegmentInfo{Code: segmentCode, Include: []string{"u1"}})
+	assert.Nil(err)
+
+	segmentRules := []*domain.Rule{{Segment: segmentCode, Value: true}}
+	base := &api.ObjectInfo{Code: "base", Parameters: []*domain.Parameter{
+		{Code: "new_ui", Type: domain.ParameterBool, Value: false, Rules: segmentRules},
+		{Code: "beta", Type: domain.ParameterBool, Value: false},
+	}}
+	baseApi := pApi.For(ProjectCode).Environments().For(envCode).Objects()
+	_, err = baseApi.Create(base)
+	assert.Nil(err)
+	inherits := &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: envCode, ObjectCode: "base"}
+	_, err = baseApi.Create(&api.ObjectInfo{Code: "local", Inherits: inherits})
+	assert.Nil(err, "the same project inheritor uses the segment")
+
+	childApi := pApi.For("p2").Environments().For(envCode).Objects()
+	_, err = childApi.Create(&api.ObjectInfo{Code: "child", Inherits: inherits})
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `new_ui` error: Rules with segment `beta` can't be inherited from other project", err.Error())
+
+	ownRules := []*domain.Rule{{
+		Conditions: []*domain.RuleCondition{{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"beta"}}},
+		Value:      true,
+	}}
+	_, err = childApi.Create(&api.ObjectInfo{Code: "child", Inherits: inherits, Parameters: []*domain.Parameter{
+		{Code: "new_ui", Type: domain.ParameterBool, Value: false, Rules: ownRules},
+	}})
+	assert.Nil(err)
+	values, err := childApi.Evaluate("child", &domain.EvaluationContext{UserID: "u1", Attributes: map[string]string{"plan": "beta"}})
+	assert.Nil(err)
+	assert.Equal(domain.ObjectValues{"new_ui": true, "beta": false}, values)
+
+	base.Parameters[1].Rules = segmentRules
+	_, err = baseApi.Update(base)
+	assert.IsType(&api.ErrObjectParameter{}, err)
+	assert.Equal("Object parameter `beta` error: Rules with segment `beta` would be inherited by p2:"+string(envCode)+":child", err.Error())
+
+	assert.Equal(api.ErrSegmentInUse, pApi.For(ProjectCode).Segments().Delete(segmentCode))
+
+	AfterTest()
+}
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// errNoDryRunStorage is returned for dry run if engine has no storage for it
+var errNoDryRunStorage = errors.New("Dry run storage is not configured")
+
+// Export returns project document, inheritors keep own parameters only
+func (p *ProjectAPI) Export(code domain.ProjectCode) (*domain.Document, error) {
+	return transfer.Export(p, code)
+}
+
+// Import creates or updates document entities, parents are saved before inheritors.
+// Dry run imports the document to a copy of the projects it relates to in DryRunStorage, so it passes the same checks.
+func (p *ProjectAPI) Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error) {
+	if !dryRun {
+		return transfer.Import(p, doc, override)
+	}
+	dataStorage, err := p.copyStorage(doc)
+	if err != nil {
+		return nil, err
+	}
+	copied := &Engine{Storage: &dataStorage, DryRunStorage: p.DryRunStorage, DisabledProjectMode: p.DisabledProjectMode, Actor: p.Actor}
+	return transfer.Import(copied.ForOwner(p.Owner).Projects(), doc, override)
+}
+
+// copyStorage returns dry run storage with the document related projects, their segments, environments, objects
+// and latest object revisions
+func (p *ProjectAPI) copyStorage(doc *domain.Document) (storage.DataStorage, error) {
+	if p.DryRunStorage == nil {
+		return nil, errNoDryRunStorage
+	}
+	related, err := p.relatedProjects(doc)
+	if err != nil {
+		return nil, err
+	}
+	dataStorage := p.DryRunStorage()
+	to := dataStorage.ForOwner(p.Owner).Projects()
+	projects, err := p.storage().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, proj := range projects {
+		if !related[proj.Code] {
+			continue
+		}
+		if err := to.Save(proj); err != nil {
+			return nil, err
+		}
+		from := p.storage().For(proj.Code)
+		segments, err := from.Segments().List()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range segments {
+			if err := to.For(proj.Code).Segments().Save(s); err != nil {
+				return nil, err
+			}
+		}
+		envs, err := from.Environments().List()
+		if err != nil {
+			return nil, err
+		}
+		for _, env := range envs {
+			if err := to.For(proj.Code).Environments().Save(env); err != nil {
+				return nil, err
+			}
+			if err := copyObjects(from.Environments().For(env.Code), to.For(proj.Code).Environments().For(env.Code)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return dataStorage, nil
+}
+
+// relatedProjects returns codes of the document project, projects of inheritors of the document objects
+// and projects the objects inherit from
+func (p *ProjectAPI) relatedProjects(doc *domain.Document) (map[domain.ProjectCode]bool, error) {
+	related := make(map[domain.ProjectCode]bool)
+	queue := make([]domain.ProjectCode, 0)
+	add := func(code domain.ProjectCode) {
+		if !related[code] {
+			related[code] = true
+			queue = append(queue, code)
+		}
+	}
+	if doc.Project != nil {
+		add(doc.Project.Code)
+	}
+	for _, env := range doc.Environments {
+		for _, obj := range env.Objects {
+			if obj.Inherits != nil {
+				add(obj.Inherits.ProjectCode)
+			}
+			if doc.Project == nil {
+				continue
+			}
+			inheritors, err := listInheritors(p.storage(), doc.Project.Code, env.Code, obj.Code)
+			if err != nil {
+				return nil, err
+			}
+			for _, inh := range inheritors {
+				add(inh.ProjectCode)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		code := queue[0]
+		queue = queue[1:]
+		envs, err := p.storage().For(code).Environments().List()
+		if err != nil {
+			return nil, err
+		}
+		for _, env := range envs {
+			objects, err := p.storage().For(code).Environments().For(env.Code).Objects().List()
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range objects {
+				if obj.Inherits != nil {
+					add(obj.Inherits.ProjectCode)
+				}
+			}
+		}
+	}
+	return related, nil
+}
+
+func copyObjects(from, to storage.ForEnvironment) error {
+	objects, err := from.Objects().List()
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := to.Objects().Save(obj); err != nil {
+			return err
+		}
+		rev, err := from.Revisions().Latest(obj.Code)
+		if err == storage.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := to.Revisions().Save(rev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
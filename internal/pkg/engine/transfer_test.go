@@ -0,0 +1,161 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func importDocument() *domain.Document {
+	return &domain.Document{
+		Version: domain.DocumentVersion,
+		Project: &domain.DocumentProject{Code: ProjectCode, Status: domain.ProjectStatusActive},
+		Environments: []*domain.DocumentEnvironment{
+			{
+				Code:      "prod",
+				Protected: true,
+				Objects: []*domain.DocumentObject{{
+					Code:     "child",
+					Inherits: &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: "dev", ObjectCode: "base"},
+					Parameters: []*domain.Parameter{
+						{Code: "limit", Type: domain.ParameterInt, Value: 20},
+					},
+				}},
+			},
+			{
+				Code: "dev",
+				Objects: []*domain.DocumentObject{{
+					Code: "base",
+					Parameters: []*domain.Parameter{
+						{Code: "limit", Type: domain.ParameterInt, Value: 10},
+						{Code: "enabled", Type: domain.ParameterBool, Value: false},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	pApi := GetApi()
+
+	changes, err := pApi.Import(importDocument(), true, true)
+	assert.Nil(err)
+	assert.Len(changes, 5)
+	for _, c := range changes {
+		assert.Equal(domain.ImportCreate, c.Action)
+	}
+	assert.Equal("base", changes[3].Code, "parent goes first")
+	_, err = pApi.Get(ProjectCode)
+	assert.Equal(api.ErrProjectNotFound, err, "dry run doesn't change storage")
+
+	_, err = pApi.Import(importDocument(), true, false)
+	assert.Equal(api.ErrEnvironmentProtected, err, "protected environment objects are changed with override")
+
+	disabled := importDocument()
+	disabled.Project.Status = domain.ProjectStatusDisabled
+	changes, err = pApi.Import(disabled, true, true)
+	assert.Nil(err, "disabled project is imported")
+	assert.Len(changes, 5)
+
+	doc := importDocument()
+	doc.Environments[0].Objects[0].Parameters[0] = &domain.Parameter{Code: "limit", Type: domain.ParameterString, Value: "20"}
+	_, err = pApi.Import(doc, true, true)
+	assert.Equal(api.ErrObjectInheritorTypeMismatch, err, "engine checks are applied")
+
+	changes, err = pApi.Import(importDocument(), false, true)
+	assert.Nil(err)
+	assert.Len(changes, 5)
+	child, err := pApi.For(ProjectCode).Environments().For("prod").Objects().Get("child")
+	assert.Nil(err)
+	assert.Len(child.Parameters, 2)
+
+	doc = importDocument()
+	doc.Environments[1].Objects[0].Description = "updated"
+	changes, err = pApi.Import(doc, true, false)
+	assert.Nil(err)
+	actions := make([]domain.ImportAction, 0)
+	for _, c := range changes {
+		actions = append(actions, c.Action)
+	}
+	assert.Equal([]domain.ImportAction{
+		domain.ImportUnchanged,
+		domain.ImportUnchanged,
+		domain.ImportUnchanged,
+		domain.ImportUpdate,
+		domain.ImportUnchanged,
+	}, actions)
+	base, err := pApi.For(ProjectCode).Environments().For("dev").Objects().Get("base")
+	assert.Nil(err)
+	assert.Equal("", base.Description)
+
+	doc.Environments[0].Objects[0].Description = "updated"
+	_, err = pApi.Import(doc, false, false)
+	assert.Equal(api.ErrEnvironmentProtected, err)
+	base, err = pApi.For(ProjectCode).Environments().For("dev").Objects().Get("base")
+	assert.Nil(err)
+	assert.Equal("updated", base.Description, "changes before the error are kept")
+
+	AfterTest()
+}
+
+func TestImportDryRunOtherProjects(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	pApi := GetApi()
+
+	_, err := pApi.Import(importDocument(), false, true)
+	assert.Nil(err)
+	external := &domain.Document{
+		Version: domain.DocumentVersion,
+		Project: &domain.DocumentProject{Code: "p2", Status: domain.ProjectStatusActive},
+		Environments: []*domain.DocumentEnvironment{{
+			Code: "dev",
+			Objects: []*domain.DocumentObject{{
+				Code:       "ext",
+				Inherits:   &domain.ObjectInheritance{ProjectCode: ProjectCode, EnvCode: "dev", ObjectCode: "base"},
+				Parameters: []*domain.Parameter{{Code: "extra", Type: domain.ParameterString, Value: "on"}},
+			}},
+		}},
+	}
+	changes, err := pApi.Import(external, true, false)
+	assert.Nil(err, "parent project is copied")
+	assert.Len(changes, 3)
+	_, err = pApi.Import(external, false, false)
+	assert.Nil(err)
+
+	doc := importDocument()
+	base := doc.Environments[1].Objects[0]
+	base.Parameters = append(base.Parameters, &domain.Parameter{Code: "extra", Type: domain.ParameterBool, Value: true})
+	_, err = pApi.Import(doc, true, true)
+	assert.IsType(&api.ErrObjectParameter{}, err, "inheritors project is copied")
+
+	AfterTest()
+}
+
+func TestExport(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+	pApi := GetApi()
+
+	_, err := pApi.Export(ProjectCode)
+	assert.Equal(api.ErrProjectNotFound, err)
+
+	_, err = pApi.Import(importDocument(), false, true)
+	assert.Nil(err)
+	doc, err := pApi.Export(ProjectCode)
+	assert.Nil(err)
+	assert.Len(doc.Environments, 2)
+	for _, env := range doc.Environments {
+		if env.Code == "prod" {
+			assert.Len(env.Objects[0].Parameters, 1, "inherited parameters are not exported")
+			assert.True(env.Protected)
+		}
+	}
+
+	AfterTest()
+}
@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	webhookIDSize     = 8
+	webhookSecretSize = 32
+)
+
+// WebhookAPI servers project webhooks
+type WebhookAPI struct {
+	Owner       string
+	ProjectCode domain.ProjectCode
+	Storage     *storage.DataStorage
+	ProjectAPI  *ProjectAPI
+}
+
+func (w *WebhookAPI) projectExists(access projectAccess) error {
+	return w.ProjectAPI.check(w.ProjectCode, access)
+}
+
+func (w *WebhookAPI) storage() storage.WebhookStorage {
+	return (*w.Storage).ForOwner(w.Owner).Projects().For(w.ProjectCode).Webhooks()
+}
+
+// List returns list of project webhooks, secrets are hidden
+func (w *WebhookAPI) List() ([]*domain.Webhook, error) {
+	if err := w.projectExists(accessRead); err != nil {
+		return nil, err
+	}
+	list, err := w.storage().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range list {
+		hook.Secret = ""
+	}
+	return list, nil
+}
+
+// Get returns webhook by id, secret is hidden
+func (w *WebhookAPI) Get(id domain.WebhookID) (*domain.Webhook, error) {
+	hook, err := w.get(id)
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (w *WebhookAPI) get(id domain.WebhookID) (*domain.Webhook, error) {
+	if err := w.projectExists(accessRead); err != nil {
+		return nil, err
+	}
+	hook, err := w.storage().Get(id)
+	if err == storage.ErrNotFound {
+		return nil, api.ErrWebhookNotFound
+	}
+	return hook, err
+}
+
+func checkWebhookParams(info *api.WebhookInfo) error {
+	u, err := url.Parse(info.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return api.NewBadRequestError("Webhook url must be absolute http or https url")
+	}
+	for _, t := range info.Events {
+		if !t.Valid() {
+			return api.NewBadRequestError(fmt.Sprintf("Unknown event type `%s`", t))
+		}
+	}
+	return nil
+}
+
+// Create registers webhook. The secret is returned only once.
+func (w *WebhookAPI) Create(info *api.WebhookInfo) (*domain.Webhook, error) {
+	if err := w.projectExists(accessWrite); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookParams(info); err != nil {
+		return nil, err
+	}
+	id, err := randomHex(webhookIDSize)
+	if err != nil {
+		return nil, err
+	}
+	secret := info.Secret
+	if secret == "" {
+		if secret, err = randomHex(webhookSecretSize); err != nil {
+			return nil, err
+		}
+	}
+	hook := &domain.Webhook{
+		ID:          domain.WebhookID(id),
+		Owner:       w.Owner,
+		ProjectCode: w.ProjectCode,
+		URL:         info.URL,
+		Secret:      secret,
+		Events:      info.Events,
+		EnvCodes:    info.EnvCodes,
+		ObjectCodes: info.ObjectCodes,
+		RegDate:     bson.Now().In(time.UTC),
+	}
+	if err := w.storage().Save(hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Update changes webhook url and filters. Empty secret keeps the current one.
+func (w *WebhookAPI) Update(info *api.WebhookInfo) (*domain.Webhook, error) {
+	if err := w.projectExists(accessWrite); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookParams(info); err != nil {
+		return nil, err
+	}
+	hook, err := w.get(info.ID)
+	if err != nil {
+		return nil, err
+	}
+	secret := info.Secret
+	if secret == "" {
+		secret = hook.Secret
+	}
+	newHook := &domain.Webhook{
+		ID:          hook.ID,
+		Owner:       w.Owner,
+		ProjectCode: w.ProjectCode,
+		URL:         info.URL,
+		Secret:      secret,
+		Events:      info.Events,
+		EnvCodes:    info.EnvCodes,
+		ObjectCodes: info.ObjectCodes,
+		RegDate:     hook.RegDate,
+	}
+	if err := w.storage().Update(newHook); err != nil {
+		return nil, updateError(err, api.ErrWebhookNotFound)
+	}
+	res := *newHook
+	res.Secret = ""
+	return &res, nil
+}
+
+// Delete removes webhook with its deliveries
+func (w *WebhookAPI) Delete(id domain.WebhookID) error {
+	if err := w.projectExists(accessWrite); err != nil {
+		return err
+	}
+	err := w.storage().Delete(id)
+	if err == storage.ErrNotFound {
+		return api.ErrWebhookNotFound
+	}
+	return err
+}
+
+// Deliveries returns webhook delivery attempts
+func (w *WebhookAPI) Deliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	if _, err := w.get(id); err != nil {
+		return nil, err
+	}
+	return w.storage().ListDeliveries(id)
+}
+
+// Test delivers ping event to the webhook once and returns the delivery record
+func (w *WebhookAPI) Test(id domain.WebhookID) (*domain.WebhookDelivery, error) {
+	hook, err := w.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if w.ProjectAPI.Webhooks == nil {
+		return nil, api.NewBadRequestError("Webhook delivery is not configured")
+	}
+	event := w.ProjectAPI.newEvent(domain.EventPing, w.ProjectCode)
+	return w.ProjectAPI.Webhooks.Deliver(hook, event), nil
+}
@@ -0,0 +1,114 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+type fakeSender struct {
+	hook  *domain.Webhook
+	event *domain.Event
+}
+
+func (s *fakeSender) Deliver(hook *domain.Webhook, event *domain.Event) *domain.WebhookDelivery {
+	s.hook = hook
+	s.event = event
+	return &domain.WebhookDelivery{WebhookID: hook.ID, EventID: event.ID, EventType: event.Type, Attempt: 1, Success: true}
+}
+
+func TestWebhooks(t *testing.T) {
+	assert := asserts.New(t)
+
+	BeforeTest()
+
+	sender := &fakeSender{}
+	togglyAPI := &engine.Engine{Storage: &dataStorage, DisabledProjectMode: engine.DisabledProjectReadOnly, Webhooks: sender}
+	pApi := togglyAPI.ForOwner(ow).Projects()
+	hooks := pApi.For(ProjectCode).Webhooks()
+
+	_, err := hooks.List()
+	assert.Equal(api.ErrProjectNotFound, err)
+
+	pApi.Create(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+
+	list, err := hooks.List()
+	assert.Nil(err)
+	assert.Empty(list)
+
+	_, err = hooks.Create(&api.WebhookInfo{URL: "ftp://example.com"})
+	assert.IsType(&api.ErrBadRequest{}, err)
+	_, err = hooks.Create(&api.WebhookInfo{URL: "/hook"})
+	assert.IsType(&api.ErrBadRequest{}, err)
+	_, err = hooks.Create(&api.WebhookInfo{URL: "http://example.com", Events: []domain.EventType{"object.rename"}})
+	assert.IsType(&api.ErrBadRequest{}, err)
+	_, err = hooks.Create(&api.WebhookInfo{URL: "http://example.com", Events: []domain.EventType{domain.EventPing}})
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	hook, err := hooks.Create(&api.WebhookInfo{
+		URL:      "https://example.com/hook",
+		Events:   []domain.EventType{domain.EventObjectUpdate},
+		EnvCodes: []domain.EnvironmentCode{envCode},
+	})
+	assert.Nil(err)
+	assert.NotEmpty(hook.ID)
+	assert.Len(hook.Secret, 64)
+	assert.Equal(ow, hook.Owner)
+	assert.Equal(ProjectCode, hook.ProjectCode)
+	secret := hook.Secret
+
+	h, err := hooks.Get(hook.ID)
+	assert.Nil(err)
+	assert.Empty(h.Secret)
+	assert.Equal("https://example.com/hook", h.URL)
+	list, err = hooks.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+	assert.Empty(list[0].Secret)
+
+	h, err = hooks.Update(&api.WebhookInfo{ID: hook.ID, URL: "https://example.com/new"})
+	assert.Nil(err)
+	assert.Empty(h.Secret)
+	assert.Empty(h.Events)
+	assert.Equal(hook.RegDate, h.RegDate)
+	_, err = hooks.Update(&api.WebhookInfo{ID: "unknown", URL: "https://example.com/new"})
+	assert.Equal(api.ErrWebhookNotFound, err)
+
+	d, err := hooks.Test(hook.ID)
+	assert.Nil(err)
+	assert.True(d.Success)
+	assert.Equal(secret, sender.hook.Secret, "empty secret keeps the current one")
+	assert.Equal("https://example.com/new", sender.hook.URL)
+	assert.Equal(domain.EventPing, sender.event.Type)
+	assert.Equal(ProjectCode, sender.event.ProjectCode)
+
+	_, err = hooks.Update(&api.WebhookInfo{ID: hook.ID, URL: "https://example.com/new", Secret: "s2"})
+	assert.Nil(err)
+	hooks.Test(hook.ID)
+	assert.Equal("s2", sender.hook.Secret)
+
+	deliveries, err := hooks.Deliveries(hook.ID)
+	assert.Nil(err)
+	assert.Empty(deliveries, "deliveries are recorded by the sender")
+	_, err = hooks.Deliveries("unknown")
+	assert.Equal(api.ErrWebhookNotFound, err)
+
+	_, err = GetApi().For(ProjectCode).Webhooks().Test(hook.ID)
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusDisabled})
+	_, err = hooks.Create(&api.WebhookInfo{URL: "https://example.com/hook"})
+	assert.Equal(api.ErrProjectDisabled, err)
+	pApi.Update(&api.ProjectInfo{Code: ProjectCode, Status: domain.ProjectStatusActive})
+
+	assert.Nil(hooks.Delete(hook.ID))
+	assert.Equal(api.ErrWebhookNotFound, hooks.Delete(hook.ID))
+	_, err = hooks.Get(hook.ID)
+	assert.Equal(api.ErrWebhookNotFound, err)
+
+	AfterTest()
+}
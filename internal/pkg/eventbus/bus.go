@@ -0,0 +1,130 @@
+// Package eventbus distributes change events to in-process subscribers
+package eventbus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// Message is an event numbered by the bus. IDs grow by one starting from 1.
+type Message struct {
+	ID    uint64
+	Event *domain.Event
+}
+
+// Filter selects events delivered to the subscription
+type Filter func(event *domain.Event) bool
+
+// Bus keeps the last published messages and delivers new ones to subscribers.
+// Subscriber which doesn't keep up with the bus is closed, it can resume from the last received message.
+// Epoch is random for every bus, message IDs can be compared only within the same epoch.
+type Bus struct {
+	Epoch       string
+	lock        sync.Mutex
+	seq         uint64
+	history     []*Message
+	historySize int
+	bufferSize  int
+	subs        map[*Subscription]struct{}
+}
+
+// New returns bus keeping historySize last messages, bufferSize messages are buffered for every subscriber
+func New(historySize, bufferSize int) *Bus {
+	epoch := make([]byte, 4)
+	rand.Read(epoch)
+	return &Bus{
+		Epoch:       hex.EncodeToString(epoch),
+		history:     make([]*Message, 0, historySize),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subs:        make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish numbers the event and delivers it to subscribers without blocking
+func (b *Bus) Publish(event *domain.Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.seq++
+	msg := &Message{ID: b.seq, Event: event}
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			copy(b.history, b.history[1:])
+			b.history = b.history[:len(b.history)-1]
+		}
+		b.history = append(b.history, msg)
+	}
+	for s := range b.subs {
+		if !s.filter(event) {
+			continue
+		}
+		select {
+		case s.ch <- msg:
+		default:
+			b.remove(s)
+		}
+	}
+}
+
+// Subscribe returns subscription to events matching the filter. Non-zero lastID resumes the stream:
+// kept messages published after lastID are returned for replay. False is returned if messages
+// after lastID are not kept anymore or lastID is unknown to the bus, so some events are lost.
+func (b *Bus) Subscribe(filter Filter, lastID uint64) (*Subscription, []*Message, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	s := &Subscription{
+		Start:  b.seq,
+		ch:     make(chan *Message, b.bufferSize),
+		filter: filter,
+		bus:    b,
+	}
+	b.subs[s] = struct{}{}
+	if lastID == 0 {
+		return s, nil, true
+	}
+	if lastID > b.seq {
+		return s, nil, false
+	}
+	replay := make([]*Message, 0)
+	complete := lastID == b.seq
+	for _, msg := range b.history {
+		if msg.ID == lastID+1 {
+			complete = true
+		}
+		if msg.ID > lastID && filter(msg.Event) {
+			replay = append(replay, msg)
+		}
+	}
+	return s, replay, complete
+}
+
+// remove closes subscription channel, called under the bus lock
+func (b *Bus) remove(s *Subscription) {
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.ch)
+	}
+}
+
+// Subscription receives bus messages. Start is ID of the last message published before subscription.
+type Subscription struct {
+	Start  uint64
+	ch     chan *Message
+	filter Filter
+	bus    *Bus
+}
+
+// C returns channel of messages. It is closed when subscriber falls behind or the subscription is closed.
+func (s *Subscription) C() <-chan *Message {
+	return s.ch
+}
+
+// Close stops the subscription
+func (s *Subscription) Close() {
+	s.bus.lock.Lock()
+	defer s.bus.lock.Unlock()
+	s.bus.remove(s)
+}
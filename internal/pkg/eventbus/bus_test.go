@@ -0,0 +1,101 @@
+package eventbus_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func event(env domain.EnvironmentCode, code domain.ObjectCode) *domain.Event {
+	return &domain.Event{Type: domain.EventObjectUpdate, Owner: "ow", ProjectCode: "p1", EnvCode: env, ObjectCode: code}
+}
+
+func envFilter(env domain.EnvironmentCode) eventbus.Filter {
+	return func(e *domain.Event) bool { return e.EnvCode == env }
+}
+
+func ids(list []*eventbus.Message) []uint64 {
+	res := make([]uint64, 0, len(list))
+	for _, m := range list {
+		res = append(res, m.ID)
+	}
+	return res
+}
+
+func TestPublish(t *testing.T) {
+	assert := asserts.New(t)
+	bus := eventbus.New(10, 10)
+	assert.NotEmpty(bus.Epoch)
+	assert.NotEqual(bus.Epoch, eventbus.New(10, 10).Epoch)
+
+	dev, replay, ok := bus.Subscribe(envFilter("dev"), 0)
+	assert.True(ok)
+	assert.Empty(replay)
+	prod, _, _ := bus.Subscribe(envFilter("prod"), 0)
+
+	bus.Publish(event("dev", "o1"))
+	bus.Publish(event("prod", "o1"))
+	bus.Publish(event("dev", "o2"))
+
+	msg := <-dev.C()
+	assert.Equal(uint64(1), msg.ID)
+	assert.Equal(domain.ObjectCode("o1"), msg.Event.ObjectCode)
+	msg = <-dev.C()
+	assert.Equal(uint64(3), msg.ID)
+	msg = <-prod.C()
+	assert.Equal(uint64(2), msg.ID)
+
+	dev.Close()
+	dev.Close()
+	_, open := <-dev.C()
+	assert.False(open)
+	bus.Publish(event("dev", "o3"))
+	assert.Len(prod.C(), 0)
+}
+
+func TestResume(t *testing.T) {
+	assert := asserts.New(t)
+	bus := eventbus.New(3, 10)
+
+	for _, env := range []domain.EnvironmentCode{"dev", "prod", "dev", "dev", "prod"} {
+		bus.Publish(event(env, "o1"))
+	}
+
+	_, replay, ok := bus.Subscribe(envFilter("dev"), 2)
+	assert.True(ok)
+	assert.Equal([]uint64{3, 4}, ids(replay))
+
+	_, replay, ok = bus.Subscribe(envFilter("dev"), 5)
+	assert.True(ok)
+	assert.Empty(replay)
+
+	_, replay, ok = bus.Subscribe(envFilter("dev"), 1)
+	assert.False(ok, "message 2 is not kept")
+	assert.Equal([]uint64{3, 4}, ids(replay))
+
+	s, replay, ok := bus.Subscribe(envFilter("dev"), 10)
+	assert.False(ok, "unknown id")
+	assert.Empty(replay)
+	assert.Equal(uint64(5), s.Start)
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	assert := asserts.New(t)
+	bus := eventbus.New(10, 2)
+
+	slow, _, _ := bus.Subscribe(envFilter("dev"), 0)
+	for i := 0; i < 3; i++ {
+		bus.Publish(event("dev", "o1"))
+	}
+	assert.Equal(uint64(1), (<-slow.C()).ID)
+	assert.Equal(uint64(2), (<-slow.C()).ID)
+	_, open := <-slow.C()
+	assert.False(open, "subscriber is closed on overflow")
+	slow.Close()
+
+	_, replay, ok := bus.Subscribe(envFilter("dev"), 2)
+	assert.True(ok)
+	assert.Equal([]uint64{3}, ids(replay))
+}
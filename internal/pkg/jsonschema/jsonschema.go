@@ -0,0 +1,406 @@
+// Package jsonschema implements validation of JSON documents against a subset of JSON Schema (draft 7).
+//
+// Supported keywords: type, enum, const, properties, required, additionalProperties,
+// items, minItems, maxItems, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// minLength, maxLength, pattern, allOf, anyOf, not.
+// Annotation keywords ($schema, $id, title, description, default, examples, $comment) are ignored.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// Schema represents compiled JSON Schema
+type Schema struct {
+	always               *bool
+	types                []string
+	enum                 []interface{}
+	constant             *interface{}
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	items                *Schema
+	minItems             *int
+	maxItems             *int
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	allOf                []*Schema
+	anyOf                []*Schema
+	not                  *Schema
+}
+
+var annotations = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+}
+
+var jsonTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// Compile parses JSON Schema document
+func Compile(doc []byte) (*Schema, error) {
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, fmt.Errorf("Schema is not valid JSON: %v", err)
+	}
+	return compile(v, "#")
+}
+
+func compile(v interface{}, path string) (*Schema, error) {
+	if b, ok := v.(bool); ok {
+		return &Schema{always: &b}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", path)
+	}
+	s := &Schema{}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		val := m[k]
+		kPath := path + "/" + k
+		var err error
+		switch k {
+		case "type":
+			s.types, err = compileTypes(val, kPath)
+		case "enum":
+			list, ok := val.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("%s: must be a non-empty array", kPath)
+			}
+			s.enum = list
+		case "const":
+			c := val
+			s.constant = &c
+		case "properties":
+			props, ok := val.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", kPath)
+			}
+			s.properties = make(map[string]*Schema, len(props))
+			for name, ps := range props {
+				if s.properties[name], err = compile(ps, kPath+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			list, ok := val.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array of strings", kPath)
+			}
+			for _, r := range list {
+				name, ok := r.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: must be an array of strings", kPath)
+				}
+				s.required = append(s.required, name)
+			}
+		case "additionalProperties":
+			s.additionalProperties, err = compile(val, kPath)
+		case "items":
+			s.items, err = compile(val, kPath)
+		case "minItems":
+			s.minItems, err = compileCount(val, kPath)
+		case "maxItems":
+			s.maxItems, err = compileCount(val, kPath)
+		case "minLength":
+			s.minLength, err = compileCount(val, kPath)
+		case "maxLength":
+			s.maxLength, err = compileCount(val, kPath)
+		case "minimum":
+			s.minimum, err = compileNumber(val, kPath)
+		case "maximum":
+			s.maximum, err = compileNumber(val, kPath)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum, err = compileNumber(val, kPath)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum, err = compileNumber(val, kPath)
+		case "pattern":
+			p, ok := val.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", kPath)
+			}
+			if s.pattern, err = regexp.Compile(p); err != nil {
+				return nil, fmt.Errorf("%s: %v", kPath, err)
+			}
+		case "allOf":
+			s.allOf, err = compileList(val, kPath)
+		case "anyOf":
+			s.anyOf, err = compileList(val, kPath)
+		case "not":
+			s.not, err = compile(val, kPath)
+		default:
+			if !annotations[k] {
+				return nil, fmt.Errorf("%s: keyword not supported", kPath)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func compileTypes(v interface{}, path string) ([]string, error) {
+	var list []interface{}
+	switch t := v.(type) {
+	case string:
+		list = []interface{}{t}
+	case []interface{}:
+		list = t
+	default:
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", path)
+	}
+	types := make([]string, 0, len(list))
+	for _, t := range list {
+		name, ok := t.(string)
+		if !ok || !jsonTypes[name] {
+			return nil, fmt.Errorf("%s: unknown type `%v`", path, t)
+		}
+		types = append(types, name)
+	}
+	return types, nil
+}
+
+func compileCount(v interface{}, path string) (*int, error) {
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", path)
+	}
+	n := int(f)
+	return &n, nil
+}
+
+func compileNumber(v interface{}, path string) (*float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", path)
+	}
+	return &f, nil
+}
+
+func compileList(v interface{}, path string) ([]*Schema, error) {
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("%s: must be a non-empty array", path)
+	}
+	res := make([]*Schema, len(list))
+	for i, item := range list {
+		s, err := compile(item, fmt.Sprintf("%s/%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		res[i] = s
+	}
+	return res, nil
+}
+
+// Validate checks decoded JSON value against schema.
+// Value has to be in the form produced by json.Unmarshal into interface{}.
+func (s *Schema) Validate(value interface{}) error {
+	return s.validate(value, "")
+}
+
+func fail(path, format string, args ...interface{}) error {
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+}
+
+func (s *Schema) validate(value interface{}, path string) error {
+	if s.always != nil {
+		if !*s.always {
+			return fail(path, "value is not allowed")
+		}
+		return nil
+	}
+	if len(s.types) > 0 && !s.matchType(value) {
+		return fail(path, "expected %v", s.types)
+	}
+	if s.enum != nil {
+		found := false
+		for _, e := range s.enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fail(path, "value is not one of enum values")
+		}
+	}
+	if s.constant != nil && !reflect.DeepEqual(*s.constant, value) {
+		return fail(path, "value is not equal to const")
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := s.validateObject(v, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := s.validateArray(v, path); err != nil {
+			return err
+		}
+	case string:
+		if err := s.validateString(v, path); err != nil {
+			return err
+		}
+	case float64:
+		if err := s.validateNumber(v, path); err != nil {
+			return err
+		}
+	}
+	for _, sub := range s.allOf {
+		if err := sub.validate(value, path); err != nil {
+			return err
+		}
+	}
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if sub.validate(value, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail(path, "value doesn't match any schema")
+		}
+	}
+	if s.not != nil && s.not.validate(value, path) == nil {
+		return fail(path, "value must not match schema")
+	}
+	return nil
+}
+
+func (s *Schema) matchType(value interface{}) bool {
+	for _, t := range s.types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Schema) validateObject(v map[string]interface{}, path string) error {
+	for _, r := range s.required {
+		if _, ok := v[r]; !ok {
+			return fail(path, "property `%s` is required", r)
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ps, ok := s.properties[k]; ok {
+			if err := ps.validate(v[k], path+"/"+k); err != nil {
+				return err
+			}
+		} else if s.additionalProperties != nil {
+			if err := s.additionalProperties.validate(v[k], path+"/"+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateArray(v []interface{}, path string) error {
+	if s.minItems != nil && len(v) < *s.minItems {
+		return fail(path, "expected at least %d items", *s.minItems)
+	}
+	if s.maxItems != nil && len(v) > *s.maxItems {
+		return fail(path, "expected at most %d items", *s.maxItems)
+	}
+	if s.items != nil {
+		for i, item := range v {
+			if err := s.items.validate(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateString(v string, path string) error {
+	l := utf8.RuneCountInString(v)
+	if s.minLength != nil && l < *s.minLength {
+		return fail(path, "expected at least %d characters", *s.minLength)
+	}
+	if s.maxLength != nil && l > *s.maxLength {
+		return fail(path, "expected at most %d characters", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(v) {
+		return fail(path, "value doesn't match pattern `%s`", s.pattern)
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(v float64, path string) error {
+	if s.minimum != nil && v < *s.minimum {
+		return fail(path, "expected minimum %v", *s.minimum)
+	}
+	if s.maximum != nil && v > *s.maximum {
+		return fail(path, "expected maximum %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+		return fail(path, "expected greater than %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+		return fail(path, "expected less than %v", *s.exclusiveMaximum)
+	}
+	return nil
+}
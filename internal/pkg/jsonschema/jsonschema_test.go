@@ -0,0 +1,77 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/jsonschema"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const schemaDoc = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"required": ["name", "limits"],
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+		"mode": {"enum": ["fast", "safe"]},
+		"limits": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"type": "integer", "minimum": 0, "exclusiveMaximum": 100}
+		}
+	},
+	"additionalProperties": false
+}`
+
+func TestCompileErrors(t *testing.T) {
+	assert := asserts.New(t)
+
+	for _, doc := range []string{
+		`not json`,
+		`"string"`,
+		`{"type": "unknown"}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"$ref": "#/definitions/a"}`,
+		`{"properties": {"a": 1}}`,
+	} {
+		_, err := jsonschema.Compile([]byte(doc))
+		assert.NotNil(err, doc)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert := asserts.New(t)
+
+	s, err := jsonschema.Compile([]byte(schemaDoc))
+	assert.Nil(err)
+
+	tt := []struct {
+		doc   string
+		valid bool
+	}{
+		{`{"name": "ab", "limits": [0, 99]}`, true},
+		{`{"name": "ab", "mode": "safe", "limits": [1]}`, true},
+		{`{"name": "ab"}`, false},
+		{`{"name": "a", "limits": [1]}`, false},
+		{`{"name": "AB", "limits": [1]}`, false},
+		{`{"name": "ab", "mode": "slow", "limits": [1]}`, false},
+		{`{"name": "ab", "limits": []}`, false},
+		{`{"name": "ab", "limits": [1.5]}`, false},
+		{`{"name": "ab", "limits": [100]}`, false},
+		{`{"name": "ab", "limits": [1], "extra": true}`, false},
+		{`[1, 2]`, false},
+	}
+
+	for _, tc := range tt {
+		var v interface{}
+		assert.Nil(json.Unmarshal([]byte(tc.doc), &v))
+		err := s.Validate(v)
+		if tc.valid {
+			assert.Nil(err, tc.doc)
+		} else {
+			assert.NotNil(err, tc.doc)
+		}
+	}
+}
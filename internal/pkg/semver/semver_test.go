@@ -0,0 +1,54 @@
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/semver"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := asserts.New(t)
+
+	v, err := semver.Parse("v1.2.3-beta.1+build.5")
+	assert.Nil(err)
+	assert.Equal(uint64(1), v.Major)
+	assert.Equal(uint64(2), v.Minor)
+	assert.Equal(uint64(3), v.Patch)
+	assert.Equal([]string{"beta", "1"}, v.PreRelease)
+	assert.Equal("build.5", v.Build)
+	assert.Equal("1.2.3-beta.1+build.5", v.String())
+
+	for _, s := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.3-", "1.2.3-01", "a.b.c"} {
+		_, err = semver.Parse(s)
+		assert.Equal(semver.ErrInvalidVersion, err, s)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	assert := asserts.New(t)
+
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := semver.Parse(ordered[i-1])
+		b, _ := semver.Parse(ordered[i])
+		assert.Equal(-1, a.Compare(b), "%s < %s", ordered[i-1], ordered[i])
+		assert.Equal(1, b.Compare(a), "%s > %s", ordered[i], ordered[i-1])
+	}
+
+	a, _ := semver.Parse("1.0.0+build.1")
+	b, _ := semver.Parse("1.0.0+build.2")
+	assert.Equal(0, a.Compare(b))
+}
@@ -0,0 +1,210 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	bbolt "go.etcd.io/bbolt"
+)
+
+const (
+	projectBucket  = "project"
+	envBucket      = "env"
+	segmentBucket  = "segment"
+	objectBucket   = "object"
+	keyBucket      = "apikey"
+	inheritsBucket = "object_inherits"
+	keyHashBucket  = "apikey_hash"
+	revisionBucket = "revision"
+	auditBucket    = "audit"
+	webhookBucket  = "webhook"
+	deliveryBucket = "webhook_delivery"
+)
+
+var buckets = []string{projectBucket, envBucket, segmentBucket, objectBucket, keyBucket, inheritsBucket, keyHashBucket, revisionBucket, auditBucket, webhookBucket, deliveryBucket}
+
+// NewBoltStorage implements DataStorage interface on top of embedded bbolt database file.
+// Every change is written in a single transaction which is synced to disk on commit.
+func NewBoltStorage(path string) (storage.DataStorage, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Can't open %s", path)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrapf(err, "Can't initialize %s", path)
+	}
+	return &boltStorage{db: db}, nil
+}
+
+func key(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+// records are stored as insertion sequence followed by BSON document,
+// so lists keep insertion order the same way Mongo does
+func encode(seq uint64, doc interface{}) ([]byte, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	v := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(v, seq)
+	return append(v, data...), nil
+}
+
+func decode(v []byte, out interface{}) error {
+	return bson.Unmarshal(v[8:], out)
+}
+
+func seqOf(v []byte) uint64 {
+	return binary.BigEndian.Uint64(v[:8])
+}
+
+func getRecord(tx *bbolt.Tx, name, k string, out interface{}) error {
+	v := tx.Bucket([]byte(name)).Get([]byte(k))
+	if v == nil {
+		return storage.ErrNotFound
+	}
+	return decode(v, out)
+}
+
+// insertRecord returns false if the key already exists
+func insertRecord(tx *bbolt.Tx, name, k string, doc interface{}) (bool, error) {
+	b := tx.Bucket([]byte(name))
+	if b.Get([]byte(k)) != nil {
+		return false, nil
+	}
+	seq, err := b.NextSequence()
+	if err != nil {
+		return false, err
+	}
+	v, err := encode(seq, doc)
+	if err != nil {
+		return false, err
+	}
+	return true, b.Put([]byte(k), v)
+}
+
+func updateRecord(tx *bbolt.Tx, name, k string, doc interface{}) error {
+	b := tx.Bucket([]byte(name))
+	old := b.Get([]byte(k))
+	if old == nil {
+		return storage.ErrNotFound
+	}
+	v, err := encode(seqOf(old), doc)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(k), v)
+}
+
+// versioned reads version of stored document
+type versioned struct {
+	Version int64 `bson:"version"`
+}
+
+// updateVersion replaces document only if its stored version equals version
+func updateVersion(tx *bbolt.Tx, name, k string, version int64, doc interface{}) error {
+	var stored versioned
+	if err := getRecord(tx, name, k, &stored); err != nil {
+		return err
+	}
+	if stored.Version != version {
+		return storage.ErrVersionConflict
+	}
+	return updateRecord(tx, name, k, doc)
+}
+
+func removeRecord(tx *bbolt.Tx, name, k string) error {
+	b := tx.Bucket([]byte(name))
+	if b.Get([]byte(k)) == nil {
+		return storage.ErrNotFound
+	}
+	return b.Delete([]byte(k))
+}
+
+// scan calls fn for each key with prefix in key order
+func scan(tx *bbolt.Tx, name, prefix string, fn func(k, v []byte) error) error {
+	c := tx.Bucket([]byte(name)).Cursor()
+	p := []byte(prefix)
+	for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findRecords calls fn for each record with key prefix in insertion order
+func findRecords(tx *bbolt.Tx, name, prefix string, fn func(v []byte) error) error {
+	list := make([][]byte, 0)
+	scan(tx, name, prefix, func(k, v []byte) error {
+		list = append(list, v)
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return seqOf(list[i]) < seqOf(list[j]) })
+	for _, v := range list {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type boltStorage struct {
+	db *bbolt.DB
+}
+
+// Close closes database file
+func (s *boltStorage) Close() error {
+	return s.db.Close()
+}
+
+func (s *boltStorage) ForOwner(ownerID string) storage.OwnerStorage {
+	return &boltOwnerStorage{owner: ownerID, db: s.db}
+}
+
+func (s *boltStorage) Keys() storage.KeyLookupStorage {
+	return &boltKeyLookupStorage{db: s.db}
+}
+
+type boltOwnerStorage struct {
+	owner string
+	db    *bbolt.DB
+}
+
+func (s *boltOwnerStorage) Projects() storage.ProjectStorage {
+	return &boltProjectStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
+
+func (s *boltOwnerStorage) Keys() storage.KeyStorage {
+	return &boltKeyStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
+
+func (s *boltOwnerStorage) Audit() storage.AuditStorage {
+	return &boltAuditStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
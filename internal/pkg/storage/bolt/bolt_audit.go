@@ -0,0 +1,44 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltAuditStorage struct {
+	owner string
+	db    *bbolt.DB
+}
+
+func (s *boltAuditStorage) List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	items := make([]*domain.AuditRecord, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, auditBucket, key(s.owner, ""), func(v []byte) error {
+			var rec *domain.AuditRecord
+			if err := decode(v, &rec); err != nil {
+				return err
+			}
+			if filter.Match(rec) {
+				items = append(items, rec)
+			}
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltAuditStorage) Save(rec *domain.AuditRecord) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, auditBucket, key(s.owner, rec.ID), rec)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{Type: "AuditRecord", Key: fmt.Sprintf("id: %s", rec.ID)}
+		}
+		return nil
+	})
+}
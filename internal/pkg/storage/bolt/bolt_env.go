@@ -0,0 +1,112 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltEnvStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *bbolt.DB
+}
+
+func (s *boltEnvStorage) key(code domain.EnvironmentCode) string {
+	return key(s.owner, string(s.projectCode), string(code))
+}
+
+func (s *boltEnvStorage) List() ([]*domain.Environment, error) {
+	items := make([]*domain.Environment, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, envBucket, key(s.owner, string(s.projectCode), ""), func(v []byte) error {
+			var e *domain.Environment
+			if err := decode(v, &e); err != nil {
+				return err
+			}
+			items = append(items, e)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltEnvStorage) Get(code domain.EnvironmentCode) (env *domain.Environment, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, envBucket, s.key(code), &env)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (s *boltEnvStorage) Delete(code domain.EnvironmentCode) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return removeRecord(tx, envBucket, s.key(code))
+	})
+}
+
+func (s *boltEnvStorage) Save(env *domain.Environment) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, envBucket, s.key(env.Code), env)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "Environment",
+				Key:  fmt.Sprintf("project_code: %s, code: %s", env.ProjectCode, env.Code),
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltEnvStorage) Update(env *domain.Environment) error {
+	version := env.Version
+	env.Version++
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return updateVersion(tx, envBucket, s.key(env.Code), version, env)
+	})
+	if err != nil {
+		env.Version = version
+	}
+	return err
+}
+
+func (s *boltEnvStorage) For(code domain.EnvironmentCode) storage.ForEnvironment {
+	return &boltForEnvironment{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     code,
+		db:          s.db,
+	}
+}
+
+type boltForEnvironment struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *bbolt.DB
+}
+
+func (s *boltForEnvironment) Objects() storage.ObjectStorage {
+	return &boltObjectStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     s.envCode,
+		db:          s.db,
+	}
+}
+
+func (s *boltForEnvironment) Revisions() storage.RevisionStorage {
+	return &boltRevisionStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     s.envCode,
+		db:          s.db,
+	}
+}
@@ -0,0 +1,94 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltKeyLookupStorage struct {
+	db *bbolt.DB
+}
+
+func (s *boltKeyLookupStorage) GetByHash(hash string) (k *domain.APIKey, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		recordKey := tx.Bucket([]byte(keyHashBucket)).Get([]byte(hash))
+		if recordKey == nil {
+			return storage.ErrNotFound
+		}
+		return getRecord(tx, keyBucket, string(recordKey), &k)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+type boltKeyStorage struct {
+	owner string
+	db    *bbolt.DB
+}
+
+func (s *boltKeyStorage) key(id domain.APIKeyID) string {
+	return key(s.owner, string(id))
+}
+
+func (s *boltKeyStorage) List() ([]*domain.APIKey, error) {
+	items := make([]*domain.APIKey, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, keyBucket, key(s.owner, ""), func(v []byte) error {
+			var k *domain.APIKey
+			if err := decode(v, &k); err != nil {
+				return err
+			}
+			items = append(items, k)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltKeyStorage) Get(id domain.APIKeyID) (k *domain.APIKey, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, keyBucket, s.key(id), &k)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (s *boltKeyStorage) Delete(id domain.APIKeyID) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		var k *domain.APIKey
+		if err := getRecord(tx, keyBucket, s.key(id), &k); err != nil {
+			return err
+		}
+		if err := tx.Bucket([]byte(keyHashBucket)).Delete([]byte(k.Hash)); err != nil {
+			return err
+		}
+		return removeRecord(tx, keyBucket, s.key(id))
+	})
+}
+
+func (s *boltKeyStorage) Save(k *domain.APIKey) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		hashes := tx.Bucket([]byte(keyHashBucket))
+		ok := hashes.Get([]byte(k.Hash)) == nil
+		if ok {
+			var err error
+			if ok, err = insertRecord(tx, keyBucket, s.key(k.ID), k); err != nil {
+				return err
+			}
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "APIKey",
+				Key:  fmt.Sprintf("id: %s", k.ID),
+			}
+		}
+		return hashes.Put([]byte(k.Hash), []byte(s.key(k.ID)))
+	})
+}
@@ -0,0 +1,153 @@
+package bolt
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltObjectStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *bbolt.DB
+}
+
+func (s *boltObjectStorage) key(code domain.ObjectCode) string {
+	return key(s.owner, string(s.projectCode), string(s.envCode), string(code))
+}
+
+// inheritsKey is a secondary index key which maps parent object to its inheritor
+func (s *boltObjectStorage) inheritsKey(obj *domain.Object) string {
+	i := obj.Inherits
+	return key(s.owner, string(i.ProjectCode), string(i.EnvCode), string(i.ObjectCode), string(s.projectCode), string(s.envCode), string(obj.Code))
+}
+
+func (s *boltObjectStorage) putInherits(tx *bbolt.Tx, obj *domain.Object) error {
+	if obj.Inherits == nil {
+		return nil
+	}
+	return tx.Bucket([]byte(inheritsBucket)).Put([]byte(s.inheritsKey(obj)), []byte(s.key(obj.Code)))
+}
+
+func (s *boltObjectStorage) deleteInherits(tx *bbolt.Tx, obj *domain.Object) error {
+	if obj.Inherits == nil {
+		return nil
+	}
+	return tx.Bucket([]byte(inheritsBucket)).Delete([]byte(s.inheritsKey(obj)))
+}
+
+func (s *boltObjectStorage) List() ([]*domain.Object, error) {
+	items := make([]*domain.Object, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, objectBucket, key(s.owner, string(s.projectCode), string(s.envCode), ""), func(v []byte) error {
+			var o *domain.Object
+			if err := decode(v, &o); err != nil {
+				return err
+			}
+			items = append(items, o)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltObjectStorage) Get(code domain.ObjectCode) (obj *domain.Object, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, objectBucket, s.key(code), &obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *boltObjectStorage) ListInheritors(code domain.ObjectCode) ([]*domain.Object, error) {
+	items := make([]*domain.Object, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		objects := tx.Bucket([]byte(objectBucket))
+		if objects.Get([]byte(s.key(code))) == nil {
+			return nil
+		}
+		records := make([][]byte, 0)
+		err := scan(tx, inheritsBucket, key(s.owner, string(s.projectCode), string(s.envCode), string(code), ""), func(k, objKey []byte) error {
+			if v := objects.Get(objKey); v != nil {
+				records = append(records, v)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		sort.Slice(records, func(i, j int) bool { return seqOf(records[i]) < seqOf(records[j]) })
+		for _, v := range records {
+			var o *domain.Object
+			if err := decode(v, &o); err != nil {
+				return err
+			}
+			items = append(items, o)
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (s *boltObjectStorage) Delete(code domain.ObjectCode, version int64) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		var obj *domain.Object
+		if err := getRecord(tx, objectBucket, s.key(code), &obj); err != nil {
+			return err
+		}
+		if obj.Version != version {
+			return storage.ErrVersionConflict
+		}
+		if err := s.deleteInherits(tx, obj); err != nil {
+			return err
+		}
+		return removeRecord(tx, objectBucket, s.key(code))
+	})
+}
+
+func (s *boltObjectStorage) Save(obj *domain.Object) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, objectBucket, s.key(obj.Code), obj)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "Object",
+				Key:  fmt.Sprintf("env_code: %s, code: %s", obj.EnvCode, obj.Code),
+			}
+		}
+		return s.putInherits(tx, obj)
+	})
+}
+
+func (s *boltObjectStorage) Update(obj *domain.Object) error {
+	version := obj.Version
+	obj.Version++
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var old *domain.Object
+		if err := getRecord(tx, objectBucket, s.key(obj.Code), &old); err != nil {
+			return err
+		}
+		if old.Version != version {
+			return storage.ErrVersionConflict
+		}
+		if err := s.deleteInherits(tx, old); err != nil {
+			return err
+		}
+		if err := updateRecord(tx, objectBucket, s.key(obj.Code), obj); err != nil {
+			return err
+		}
+		return s.putInherits(tx, obj)
+	})
+	if err != nil {
+		obj.Version = version
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltProjectStorage struct {
+	owner string
+	db    *bbolt.DB
+}
+
+func (s *boltProjectStorage) key(code domain.ProjectCode) string {
+	return key(s.owner, string(code))
+}
+
+func (s *boltProjectStorage) List() ([]*domain.Project, error) {
+	items := make([]*domain.Project, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, projectBucket, key(s.owner, ""), func(v []byte) error {
+			var p *domain.Project
+			if err := decode(v, &p); err != nil {
+				return err
+			}
+			items = append(items, p)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltProjectStorage) Get(code domain.ProjectCode) (project *domain.Project, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, projectBucket, s.key(code), &project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *boltProjectStorage) Delete(code domain.ProjectCode) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return removeRecord(tx, projectBucket, s.key(code))
+	})
+}
+
+func (s *boltProjectStorage) Save(project *domain.Project) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, projectBucket, s.key(project.Code), project)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "Project",
+				Key:  fmt.Sprintf("owner: %s, code: %s", project.OwnerID, project.Code),
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltProjectStorage) Update(project *domain.Project) error {
+	version := project.Version
+	project.Version++
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return updateVersion(tx, projectBucket, s.key(project.Code), version, project)
+	})
+	if err != nil {
+		project.Version = version
+	}
+	return err
+}
+
+func (s *boltProjectStorage) For(projectCode domain.ProjectCode) storage.ForProject {
+	return &boltForProject{
+		owner:       s.owner,
+		projectCode: projectCode,
+		db:          s.db,
+	}
+}
+
+type boltForProject struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *bbolt.DB
+}
+
+func (s *boltForProject) Environments() storage.EnvironmentStorage {
+	return &boltEnvStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
+
+func (s *boltForProject) Webhooks() storage.WebhookStorage {
+	return &boltWebhookStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
+
+func (s *boltForProject) Segments() storage.SegmentStorage {
+	return &boltSegmentStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
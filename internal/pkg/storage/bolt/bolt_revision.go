@@ -0,0 +1,87 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltRevisionStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *bbolt.DB
+}
+
+func (s *boltRevisionStorage) prefix(code domain.ObjectCode) string {
+	return key(s.owner, string(s.projectCode), string(s.envCode), string(code), "")
+}
+
+// key keeps revisions of the object ordered by number
+func (s *boltRevisionStorage) key(code domain.ObjectCode, revision int64) string {
+	return s.prefix(code) + fmt.Sprintf("%020d", revision)
+}
+
+func (s *boltRevisionStorage) List(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	items := make([]*domain.ObjectRevision, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return scan(tx, revisionBucket, s.prefix(code), func(k, v []byte) error {
+			var rev *domain.ObjectRevision
+			if err := decode(v, &rev); err != nil {
+				return err
+			}
+			items = append(items, rev)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltRevisionStorage) Get(code domain.ObjectCode, revision int64) (rev *domain.ObjectRevision, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, revisionBucket, s.key(code, revision), &rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (s *boltRevisionStorage) Latest(code domain.ObjectCode) (rev *domain.ObjectRevision, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		var last []byte
+		err := scan(tx, revisionBucket, s.prefix(code), func(k, v []byte) error {
+			last = v
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if last == nil {
+			return storage.ErrNotFound
+		}
+		return decode(last, &rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (s *boltRevisionStorage) Save(rev *domain.ObjectRevision) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, revisionBucket, s.key(rev.ObjectCode, rev.Revision), rev)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "ObjectRevision",
+				Key:  fmt.Sprintf("object_code: %s, revision: %d", rev.ObjectCode, rev.Revision),
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltSegmentStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *bbolt.DB
+}
+
+func (s *boltSegmentStorage) key(code domain.SegmentCode) string {
+	return key(s.owner, string(s.projectCode), string(code))
+}
+
+func (s *boltSegmentStorage) List() ([]*domain.Segment, error) {
+	items := make([]*domain.Segment, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, segmentBucket, key(s.owner, string(s.projectCode), ""), func(v []byte) error {
+			var seg *domain.Segment
+			if err := decode(v, &seg); err != nil {
+				return err
+			}
+			items = append(items, seg)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltSegmentStorage) Get(code domain.SegmentCode) (segment *domain.Segment, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, segmentBucket, s.key(code), &segment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+func (s *boltSegmentStorage) Delete(code domain.SegmentCode) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return removeRecord(tx, segmentBucket, s.key(code))
+	})
+}
+
+func (s *boltSegmentStorage) Save(segment *domain.Segment) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, segmentBucket, s.key(segment.Code), segment)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "Segment",
+				Key:  fmt.Sprintf("project_code: %s, code: %s", segment.ProjectCode, segment.Code),
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltSegmentStorage) Update(segment *domain.Segment) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return updateRecord(tx, segmentBucket, s.key(segment.Code), segment)
+	})
+}
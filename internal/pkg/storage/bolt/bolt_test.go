@@ -0,0 +1,139 @@
+package bolt_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/bolt"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const ow = "test_owner"
+
+var dir string
+
+func BeforeTest() {
+	dir, _ = ioutil.TempDir("", "toggly_bolt_test")
+}
+
+func AfterTest() {
+	os.RemoveAll(dir)
+}
+
+func openStorage(t *testing.T) storage.DataStorage {
+	s, err := bolt.NewBoltStorage(filepath.Join(dir, "toggly.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func closeStorage(s storage.DataStorage) {
+	s.(io.Closer).Close()
+}
+
+func TestPersistence(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	s := openStorage(t)
+	projects := s.ForOwner(ow).Projects()
+	assert.Nil(projects.Save(&domain.Project{OwnerID: ow, Code: "p2"}))
+	assert.Nil(projects.Save(&domain.Project{OwnerID: ow, Code: "p1", Description: "first"}))
+	err := projects.Save(&domain.Project{OwnerID: ow, Code: "p1"})
+	assert.Equal(&storage.UniqueIndexError{Type: "Project", Key: "owner: test_owner, code: p1"}, err)
+	assert.Equal(storage.ErrNotFound, projects.Update(&domain.Project{OwnerID: ow, Code: "p3"}))
+	assert.Equal(storage.ErrNotFound, projects.Delete("p3"))
+	closeStorage(s)
+
+	s = openStorage(t)
+	defer closeStorage(s)
+	list, err := s.ForOwner(ow).Projects().List()
+	assert.Nil(err)
+	assert.Len(list, 2)
+	assert.Equal(domain.ProjectCode("p2"), list[0].Code)
+	assert.Equal(domain.ProjectCode("p1"), list[1].Code)
+	assert.Equal("first", list[1].Description)
+
+	list, err = s.ForOwner("another_owner").Projects().List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = s.ForOwner("another_owner").Projects().Get("p1")
+	assert.Equal(storage.ErrNotFound, err)
+
+	AfterTest()
+}
+
+func TestInheritorsIndex(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	s := openStorage(t)
+	defer closeStorage(s)
+	dev := s.ForOwner(ow).Projects().For("p1").Environments().For("dev").Objects()
+	prod := s.ForOwner(ow).Projects().For("p1").Environments().For("prod").Objects()
+
+	parent := &domain.Object{Owner: ow, ProjectCode: "p1", EnvCode: "dev", Code: "parent"}
+	inherits := &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "parent"}
+	assert.Nil(dev.Save(parent))
+	assert.Nil(dev.Save(&domain.Object{Owner: ow, ProjectCode: "p1", EnvCode: "dev", Code: "child1", Inherits: inherits}))
+	assert.Nil(prod.Save(&domain.Object{Owner: ow, ProjectCode: "p1", EnvCode: "prod", Code: "child2", Inherits: inherits}))
+	assert.Nil(dev.Save(&domain.Object{Owner: ow, ProjectCode: "p1", EnvCode: "dev", Code: "parent2"}))
+
+	inheritors, err := dev.ListInheritors("parent")
+	assert.Nil(err)
+	assert.Len(inheritors, 2)
+	assert.Equal(domain.ObjectCode("child1"), inheritors[0].Code)
+	assert.Equal(domain.ObjectCode("child2"), inheritors[1].Code)
+
+	assert.Nil(dev.Update(&domain.Object{
+		Owner:       ow,
+		ProjectCode: "p1",
+		EnvCode:     "dev",
+		Code:        "child1",
+		Inherits:    &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "parent2"},
+	}))
+	assert.Nil(prod.Delete("child2", 0))
+
+	inheritors, err = dev.ListInheritors("parent")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+	inheritors, err = dev.ListInheritors("parent2")
+	assert.Nil(err)
+	assert.Len(inheritors, 1)
+	inheritors, err = dev.ListInheritors("unknown")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+
+	err = dev.Save(parent)
+	assert.Equal(&storage.UniqueIndexError{Type: "Object", Key: "env_code: dev, code: parent"}, err)
+
+	AfterTest()
+}
+
+func TestKeyHashIndex(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	s := openStorage(t)
+	defer closeStorage(s)
+	keys := s.ForOwner(ow).Keys()
+
+	assert.Nil(keys.Save(&domain.APIKey{ID: "k1", OwnerID: ow, Hash: "hash1"}))
+	assert.IsType(&storage.UniqueIndexError{}, keys.Save(&domain.APIKey{ID: "k2", OwnerID: ow, Hash: "hash1"}))
+
+	key, err := s.Keys().GetByHash("hash1")
+	assert.Nil(err)
+	assert.Equal(domain.APIKeyID("k1"), key.ID)
+
+	assert.Nil(keys.Delete("k1"))
+	_, err = s.Keys().GetByHash("hash1")
+	assert.Equal(storage.ErrNotFound, err)
+
+	AfterTest()
+}
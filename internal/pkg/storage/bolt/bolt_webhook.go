@@ -0,0 +1,118 @@
+package bolt
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	bbolt "go.etcd.io/bbolt"
+)
+
+type boltWebhookStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *bbolt.DB
+}
+
+func (s *boltWebhookStorage) key(id domain.WebhookID) string {
+	return key(s.owner, string(s.projectCode), string(id))
+}
+
+func (s *boltWebhookStorage) deliveryPrefix(id domain.WebhookID) string {
+	return key(s.owner, string(s.projectCode), string(id), "")
+}
+
+func (s *boltWebhookStorage) List() ([]*domain.Webhook, error) {
+	items := make([]*domain.Webhook, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, webhookBucket, key(s.owner, string(s.projectCode), ""), func(v []byte) error {
+			var hook *domain.Webhook
+			if err := decode(v, &hook); err != nil {
+				return err
+			}
+			items = append(items, hook)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltWebhookStorage) Get(id domain.WebhookID) (hook *domain.Webhook, err error) {
+	err = s.db.View(func(tx *bbolt.Tx) error {
+		return getRecord(tx, webhookBucket, s.key(id), &hook)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s *boltWebhookStorage) Delete(id domain.WebhookID) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		if err := removeRecord(tx, webhookBucket, s.key(id)); err != nil {
+			return err
+		}
+		keys := make([][]byte, 0)
+		scan(tx, deliveryBucket, s.deliveryPrefix(id), func(k, v []byte) error {
+			keys = append(keys, append([]byte{}, k...))
+			return nil
+		})
+		b := tx.Bucket([]byte(deliveryBucket))
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltWebhookStorage) Save(hook *domain.Webhook) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, webhookBucket, s.key(hook.ID), hook)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{
+				Type: "Webhook",
+				Key:  fmt.Sprintf("project_code: %s, id: %s", hook.ProjectCode, hook.ID),
+			}
+		}
+		return nil
+	})
+}
+
+func (s *boltWebhookStorage) Update(hook *domain.Webhook) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return updateRecord(tx, webhookBucket, s.key(hook.ID), hook)
+	})
+}
+
+func (s *boltWebhookStorage) ListDeliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	items := make([]*domain.WebhookDelivery, 0)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return findRecords(tx, deliveryBucket, s.deliveryPrefix(id), func(v []byte) error {
+			var d *domain.WebhookDelivery
+			if err := decode(v, &d); err != nil {
+				return err
+			}
+			items = append(items, d)
+			return nil
+		})
+	})
+	return items, err
+}
+
+func (s *boltWebhookStorage) SaveDelivery(d *domain.WebhookDelivery) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		ok, err := insertRecord(tx, deliveryBucket, s.deliveryPrefix(d.WebhookID)+d.ID, d)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &storage.UniqueIndexError{Type: "WebhookDelivery", Key: fmt.Sprintf("id: %s", d.ID)}
+		}
+		return nil
+	})
+}
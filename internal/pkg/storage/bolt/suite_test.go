@@ -0,0 +1,19 @@
+package bolt_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/storagetest"
+)
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (storage.DataStorage, func()) {
+		BeforeTest()
+		s := openStorage(t)
+		return s, func() {
+			closeStorage(s)
+			AfterTest()
+		}
+	})
+}
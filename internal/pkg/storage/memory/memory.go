@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/globalsign/mgo/bson"
+)
+
+// NewMemoryStorage implements DataStorage interface in memory
+func NewMemoryStorage() storage.DataStorage {
+	return &memStorage{
+		db: &memDB{
+			collections: make(map[string]map[string]*record),
+		},
+	}
+}
+
+// record keeps document encoded the same way Mongo does,
+// so values survive the storage round trip exactly as they do in Mongo
+type record struct {
+	seq  int
+	data []byte
+}
+
+type memDB struct {
+	sync.RWMutex
+	seq         int
+	collections map[string]map[string]*record
+}
+
+func key(parts ...string) string {
+	return strings.Join(parts, "\x00")
+}
+
+// collection returns collection by name creating it if missing, requires write lock
+func (db *memDB) collection(name string) map[string]*record {
+	c, ok := db.collections[name]
+	if !ok {
+		c = make(map[string]*record)
+		db.collections[name] = c
+	}
+	return c
+}
+
+func (db *memDB) get(name, k string, out interface{}) error {
+	db.RLock()
+	defer db.RUnlock()
+	r, ok := db.collections[name][k]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	return bson.Unmarshal(r.data, out)
+}
+
+func (db *memDB) insert(name, k string, doc interface{}) (bool, error) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return false, err
+	}
+	db.Lock()
+	defer db.Unlock()
+	c := db.collection(name)
+	if _, ok := c[k]; ok {
+		return false, nil
+	}
+	db.seq++
+	c[k] = &record{seq: db.seq, data: data}
+	return true, nil
+}
+
+func (db *memDB) update(name, k string, doc interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	db.Lock()
+	defer db.Unlock()
+	r, ok := db.collection(name)[k]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	r.data = data
+	return nil
+}
+
+// versioned reads version of stored document
+type versioned struct {
+	Version int64 `bson:"version"`
+}
+
+// updateVersion replaces document only if its stored version equals version
+func (db *memDB) updateVersion(name, k string, version int64, doc interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	db.Lock()
+	defer db.Unlock()
+	r, ok := db.collection(name)[k]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	var stored versioned
+	if err := bson.Unmarshal(r.data, &stored); err != nil {
+		return err
+	}
+	if stored.Version != version {
+		return storage.ErrVersionConflict
+	}
+	r.data = data
+	return nil
+}
+
+func (db *memDB) remove(name, k string) error {
+	db.Lock()
+	defer db.Unlock()
+	c := db.collection(name)
+	if _, ok := c[k]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(c, k)
+	return nil
+}
+
+// removeVersion removes the record only if it has the version
+func (db *memDB) removeVersion(name, k string, version int64) error {
+	db.Lock()
+	defer db.Unlock()
+	c := db.collection(name)
+	r, ok := c[k]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	var stored versioned
+	if err := bson.Unmarshal(r.data, &stored); err != nil {
+		return err
+	}
+	if stored.Version != version {
+		return storage.ErrVersionConflict
+	}
+	delete(c, k)
+	return nil
+}
+
+// removePrefix removes all records with key prefix
+func (db *memDB) removePrefix(name, prefix string) {
+	db.Lock()
+	defer db.Unlock()
+	c := db.collection(name)
+	for k := range c {
+		if strings.HasPrefix(k, prefix) {
+			delete(c, k)
+		}
+	}
+}
+
+// find calls fn for each record with key prefix in insertion order
+func (db *memDB) find(name, prefix string, fn func(data []byte) error) error {
+	db.RLock()
+	list := make([]record, 0)
+	for k, r := range db.collections[name] {
+		if strings.HasPrefix(k, prefix) {
+			list = append(list, *r)
+		}
+	}
+	db.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].seq < list[j].seq })
+	for _, r := range list {
+		if err := fn(r.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memStorage struct {
+	db *memDB
+}
+
+func (s *memStorage) ForOwner(ownerID string) storage.OwnerStorage {
+	return &memOwnerStorage{owner: ownerID, db: s.db}
+}
+
+func (s *memStorage) Keys() storage.KeyLookupStorage {
+	return &memKeyLookupStorage{db: s.db}
+}
+
+type memOwnerStorage struct {
+	owner string
+	db    *memDB
+}
+
+func (s *memOwnerStorage) Projects() storage.ProjectStorage {
+	return &memProjectStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
+
+func (s *memOwnerStorage) Keys() storage.KeyStorage {
+	return &memKeyStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
+
+func (s *memOwnerStorage) Audit() storage.AuditStorage {
+	return &memAuditStorage{
+		owner: s.owner,
+		db:    s.db,
+	}
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memAuditStorage struct {
+	owner string
+	db    *memDB
+}
+
+func (s *memAuditStorage) List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	items := make([]*domain.AuditRecord, 0)
+	err := s.db.find("audit", key(s.owner, ""), func(data []byte) error {
+		var rec *domain.AuditRecord
+		if err := bson.Unmarshal(data, &rec); err != nil {
+			return err
+		}
+		if filter.Match(rec) {
+			items = append(items, rec)
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (s *memAuditStorage) Save(rec *domain.AuditRecord) error {
+	ok, err := s.db.insert("audit", key(s.owner, rec.ID), rec)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{Type: "AuditRecord", Key: fmt.Sprintf("id: %s", rec.ID)}
+	}
+	return nil
+}
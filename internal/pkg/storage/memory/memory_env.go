@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memEnvStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *memDB
+}
+
+func (s *memEnvStorage) key(code domain.EnvironmentCode) string {
+	return key(s.owner, string(s.projectCode), string(code))
+}
+
+func (s *memEnvStorage) List() ([]*domain.Environment, error) {
+	items := make([]*domain.Environment, 0)
+	err := s.db.find("env", key(s.owner, string(s.projectCode), ""), func(data []byte) error {
+		var e *domain.Environment
+		if err := bson.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		items = append(items, e)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memEnvStorage) Get(code domain.EnvironmentCode) (env *domain.Environment, err error) {
+	if err = s.db.get("env", s.key(code), &env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (s *memEnvStorage) Delete(code domain.EnvironmentCode) error {
+	return s.db.remove("env", s.key(code))
+}
+
+func (s *memEnvStorage) Save(env *domain.Environment) error {
+	ok, err := s.db.insert("env", s.key(env.Code), env)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "Environment",
+			Key:  fmt.Sprintf("project_code: %s, code: %s", env.ProjectCode, env.Code),
+		}
+	}
+	return nil
+}
+
+func (s *memEnvStorage) Update(env *domain.Environment) error {
+	version := env.Version
+	env.Version++
+	if err := s.db.updateVersion("env", s.key(env.Code), version, env); err != nil {
+		env.Version = version
+		return err
+	}
+	return nil
+}
+
+func (s *memEnvStorage) For(code domain.EnvironmentCode) storage.ForEnvironment {
+	return &memForEnvironment{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     code,
+		db:          s.db,
+	}
+}
+
+type memForEnvironment struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *memDB
+}
+
+func (s *memForEnvironment) Objects() storage.ObjectStorage {
+	return &memObjectStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     s.envCode,
+		db:          s.db,
+	}
+}
+
+func (s *memForEnvironment) Revisions() storage.RevisionStorage {
+	return &memRevisionStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		envCode:     s.envCode,
+		db:          s.db,
+	}
+}
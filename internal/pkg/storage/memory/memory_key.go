@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memKeyLookupStorage struct {
+	db *memDB
+}
+
+func (s *memKeyLookupStorage) GetByHash(hash string) (k *domain.APIKey, err error) {
+	var idx hashIndex
+	if err = s.db.get("apikey_hash", hash, &idx); err != nil {
+		return nil, err
+	}
+	if err = s.db.get("apikey", idx.Key, &k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// hashIndex refers api key record by its hash
+type hashIndex struct {
+	Key string `bson:"key"`
+}
+
+type memKeyStorage struct {
+	owner string
+	db    *memDB
+}
+
+func (s *memKeyStorage) key(id domain.APIKeyID) string {
+	return key(s.owner, string(id))
+}
+
+func (s *memKeyStorage) List() ([]*domain.APIKey, error) {
+	items := make([]*domain.APIKey, 0)
+	err := s.db.find("apikey", key(s.owner, ""), func(data []byte) error {
+		var k *domain.APIKey
+		if err := bson.Unmarshal(data, &k); err != nil {
+			return err
+		}
+		items = append(items, k)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memKeyStorage) Get(id domain.APIKeyID) (k *domain.APIKey, err error) {
+	if err = s.db.get("apikey", s.key(id), &k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (s *memKeyStorage) Delete(id domain.APIKeyID) error {
+	k, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if err = s.db.remove("apikey", s.key(id)); err != nil {
+		return err
+	}
+	return s.db.remove("apikey_hash", k.Hash)
+}
+
+func (s *memKeyStorage) Save(k *domain.APIKey) error {
+	ok, err := s.db.insert("apikey_hash", k.Hash, &hashIndex{Key: s.key(k.ID)})
+	if err == nil && ok {
+		if ok, err = s.db.insert("apikey", s.key(k.ID), k); err != nil || !ok {
+			s.db.remove("apikey_hash", k.Hash)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "APIKey",
+			Key:  fmt.Sprintf("id: %s", k.ID),
+		}
+	}
+	return nil
+}
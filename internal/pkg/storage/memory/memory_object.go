@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memObjectStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *memDB
+}
+
+func (s *memObjectStorage) key(code domain.ObjectCode) string {
+	return key(s.owner, string(s.projectCode), string(s.envCode), string(code))
+}
+
+func (s *memObjectStorage) List() ([]*domain.Object, error) {
+	items := make([]*domain.Object, 0)
+	err := s.db.find("object", key(s.owner, string(s.projectCode), string(s.envCode), ""), func(data []byte) error {
+		var o *domain.Object
+		if err := bson.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		items = append(items, o)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memObjectStorage) Get(code domain.ObjectCode) (obj *domain.Object, err error) {
+	if err = s.db.get("object", s.key(code), &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *memObjectStorage) ListInheritors(code domain.ObjectCode) ([]*domain.Object, error) {
+	items := make([]*domain.Object, 0)
+	if _, err := s.Get(code); err != nil {
+		if err == storage.ErrNotFound {
+			return items, nil
+		}
+		return nil, err
+	}
+	err := s.db.find("object", key(s.owner, ""), func(data []byte) error {
+		var o *domain.Object
+		if err := bson.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		i := o.Inherits
+		if i != nil && i.ProjectCode == s.projectCode && i.EnvCode == s.envCode && i.ObjectCode == code {
+			items = append(items, o)
+		}
+		return nil
+	})
+	return items, err
+}
+
+func (s *memObjectStorage) Delete(code domain.ObjectCode, version int64) error {
+	return s.db.removeVersion("object", s.key(code), version)
+}
+
+func (s *memObjectStorage) Save(obj *domain.Object) error {
+	ok, err := s.db.insert("object", s.key(obj.Code), obj)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "Object",
+			Key:  fmt.Sprintf("env_code: %s, code: %s", obj.EnvCode, obj.Code),
+		}
+	}
+	return nil
+}
+
+func (s *memObjectStorage) Update(obj *domain.Object) error {
+	version := obj.Version
+	obj.Version++
+	if err := s.db.updateVersion("object", s.key(obj.Code), version, obj); err != nil {
+		obj.Version = version
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memProjectStorage struct {
+	owner string
+	db    *memDB
+}
+
+func (s *memProjectStorage) key(code domain.ProjectCode) string {
+	return key(s.owner, string(code))
+}
+
+func (s *memProjectStorage) List() ([]*domain.Project, error) {
+	items := make([]*domain.Project, 0)
+	err := s.db.find("project", key(s.owner, ""), func(data []byte) error {
+		var p *domain.Project
+		if err := bson.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		items = append(items, p)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memProjectStorage) Get(code domain.ProjectCode) (project *domain.Project, err error) {
+	if err = s.db.get("project", s.key(code), &project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *memProjectStorage) Delete(code domain.ProjectCode) error {
+	return s.db.remove("project", s.key(code))
+}
+
+func (s *memProjectStorage) Save(project *domain.Project) error {
+	ok, err := s.db.insert("project", s.key(project.Code), project)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "Project",
+			Key:  fmt.Sprintf("owner: %s, code: %s", project.OwnerID, project.Code),
+		}
+	}
+	return nil
+}
+
+func (s *memProjectStorage) Update(project *domain.Project) error {
+	version := project.Version
+	project.Version++
+	if err := s.db.updateVersion("project", s.key(project.Code), version, project); err != nil {
+		project.Version = version
+		return err
+	}
+	return nil
+}
+
+func (s *memProjectStorage) For(projectCode domain.ProjectCode) storage.ForProject {
+	return &memForProject{
+		owner:       s.owner,
+		projectCode: projectCode,
+		db:          s.db,
+	}
+}
+
+type memForProject struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *memDB
+}
+
+func (s *memForProject) Environments() storage.EnvironmentStorage {
+	return &memEnvStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
+
+func (s *memForProject) Webhooks() storage.WebhookStorage {
+	return &memWebhookStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
+
+func (s *memForProject) Segments() storage.SegmentStorage {
+	return &memSegmentStorage{
+		owner:       s.owner,
+		projectCode: s.projectCode,
+		db:          s.db,
+	}
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memRevisionStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	db          *memDB
+}
+
+// key keeps revisions of the object ordered by number
+func (s *memRevisionStorage) key(code domain.ObjectCode, revision int64) string {
+	return key(s.owner, string(s.projectCode), string(s.envCode), string(code), fmt.Sprintf("%020d", revision))
+}
+
+func (s *memRevisionStorage) List(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	items := make([]*domain.ObjectRevision, 0)
+	err := s.db.find("revision", key(s.owner, string(s.projectCode), string(s.envCode), string(code), ""), func(data []byte) error {
+		var rev *domain.ObjectRevision
+		if err := bson.Unmarshal(data, &rev); err != nil {
+			return err
+		}
+		items = append(items, rev)
+		return nil
+	})
+	sort.Slice(items, func(i, j int) bool { return items[i].Revision < items[j].Revision })
+	return items, err
+}
+
+func (s *memRevisionStorage) Get(code domain.ObjectCode, revision int64) (rev *domain.ObjectRevision, err error) {
+	if err = s.db.get("revision", s.key(code, revision), &rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (s *memRevisionStorage) Latest(code domain.ObjectCode) (*domain.ObjectRevision, error) {
+	list, err := s.List(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return list[len(list)-1], nil
+}
+
+func (s *memRevisionStorage) Save(rev *domain.ObjectRevision) error {
+	ok, err := s.db.insert("revision", s.key(rev.ObjectCode, rev.Revision), rev)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "ObjectRevision",
+			Key:  fmt.Sprintf("object_code: %s, revision: %d", rev.ObjectCode, rev.Revision),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memSegmentStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *memDB
+}
+
+func (s *memSegmentStorage) key(code domain.SegmentCode) string {
+	return key(s.owner, string(s.projectCode), string(code))
+}
+
+func (s *memSegmentStorage) List() ([]*domain.Segment, error) {
+	items := make([]*domain.Segment, 0)
+	err := s.db.find("segment", key(s.owner, string(s.projectCode), ""), func(data []byte) error {
+		var seg *domain.Segment
+		if err := bson.Unmarshal(data, &seg); err != nil {
+			return err
+		}
+		items = append(items, seg)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memSegmentStorage) Get(code domain.SegmentCode) (segment *domain.Segment, err error) {
+	if err = s.db.get("segment", s.key(code), &segment); err != nil {
+		return nil, err
+	}
+	return segment, nil
+}
+
+func (s *memSegmentStorage) Delete(code domain.SegmentCode) error {
+	return s.db.remove("segment", s.key(code))
+}
+
+func (s *memSegmentStorage) Save(segment *domain.Segment) error {
+	ok, err := s.db.insert("segment", s.key(segment.Code), segment)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "Segment",
+			Key:  fmt.Sprintf("project_code: %s, code: %s", segment.ProjectCode, segment.Code),
+		}
+	}
+	return nil
+}
+
+func (s *memSegmentStorage) Update(segment *domain.Segment) error {
+	return s.db.update("segment", s.key(segment.Code), segment)
+}
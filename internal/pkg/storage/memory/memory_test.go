@@ -0,0 +1,120 @@
+package memory_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const ow = "test_owner"
+
+func TestProjectStorage(t *testing.T) {
+	assert := asserts.New(t)
+	s := memory.NewMemoryStorage().ForOwner(ow).Projects()
+
+	_, err := s.Get("p1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, s.Delete("p1"))
+	assert.Equal(storage.ErrNotFound, s.Update(&domain.Project{OwnerID: ow, Code: "p1"}))
+
+	assert.Nil(s.Save(&domain.Project{OwnerID: ow, Code: "p1", Description: "first"}))
+	assert.Nil(s.Save(&domain.Project{OwnerID: ow, Code: "p2"}))
+	err = s.Save(&domain.Project{OwnerID: ow, Code: "p1"})
+	assert.Equal(&storage.UniqueIndexError{Type: "Project", Key: "owner: test_owner, code: p1"}, err)
+
+	p, err := s.Get("p1")
+	assert.Nil(err)
+	assert.Equal("first", p.Description)
+
+	p.Description = "changed"
+	list, err := s.List()
+	assert.Nil(err)
+	assert.Len(list, 2)
+	assert.Equal(domain.ProjectCode("p1"), list[0].Code)
+	assert.Equal("first", list[0].Description)
+
+	assert.Nil(s.Update(&domain.Project{OwnerID: ow, Code: "p1", Description: "updated"}))
+	p, err = s.Get("p1")
+	assert.Nil(err)
+	assert.Equal("updated", p.Description)
+
+	assert.Nil(s.Delete("p1"))
+	list, err = s.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+}
+
+func TestObjectStorage(t *testing.T) {
+	assert := asserts.New(t)
+	s := memory.NewMemoryStorage().ForOwner(ow).Projects().For("p1").Environments().For("dev").Objects()
+
+	parent := &domain.Object{
+		Owner:       ow,
+		ProjectCode: "p1",
+		EnvCode:     "dev",
+		Code:        "parent",
+		Parameters:  []*domain.Parameter{{Code: "param1", Type: domain.ParameterInt, Value: 1}},
+	}
+	assert.Nil(s.Save(parent))
+	err := s.Save(parent)
+	assert.Equal(&storage.UniqueIndexError{Type: "Object", Key: "env_code: dev, code: parent"}, err)
+
+	obj, err := s.Get("parent")
+	assert.Nil(err)
+	assert.Equal(parent.Parameters[0].Code, obj.Parameters[0].Code)
+
+	inheritors, err := s.ListInheritors("parent")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+
+	assert.Nil(s.Save(&domain.Object{
+		Owner:       ow,
+		ProjectCode: "p1",
+		EnvCode:     "dev",
+		Code:        "child",
+		Inherits:    &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "parent"},
+	}))
+	inheritors, err = s.ListInheritors("parent")
+	assert.Nil(err)
+	assert.Len(inheritors, 1)
+	assert.Equal(domain.ObjectCode("child"), inheritors[0].Code)
+
+	inheritors, err = s.ListInheritors("unknown")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	assert := asserts.New(t)
+	s := memory.NewMemoryStorage().ForOwner(ow).Projects()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			code := domain.ProjectCode(fmt.Sprintf("p%d", i%10))
+			if err := s.Save(&domain.Project{OwnerID: ow, Code: code}); err != nil {
+				errs <- err
+			}
+			s.List()
+			s.Get(code)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	list, err := s.List()
+	assert.Nil(err)
+	assert.Len(list, 10)
+	assert.Len(errs, 90)
+	for err := range errs {
+		assert.IsType(&storage.UniqueIndexError{}, err)
+	}
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+type memWebhookStorage struct {
+	owner       string
+	projectCode domain.ProjectCode
+	db          *memDB
+}
+
+func (s *memWebhookStorage) key(id domain.WebhookID) string {
+	return key(s.owner, string(s.projectCode), string(id))
+}
+
+func (s *memWebhookStorage) List() ([]*domain.Webhook, error) {
+	items := make([]*domain.Webhook, 0)
+	err := s.db.find("webhook", key(s.owner, string(s.projectCode), ""), func(data []byte) error {
+		var hook *domain.Webhook
+		if err := bson.Unmarshal(data, &hook); err != nil {
+			return err
+		}
+		items = append(items, hook)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memWebhookStorage) Get(id domain.WebhookID) (hook *domain.Webhook, err error) {
+	if err = s.db.get("webhook", s.key(id), &hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s *memWebhookStorage) Delete(id domain.WebhookID) error {
+	if err := s.db.remove("webhook", s.key(id)); err != nil {
+		return err
+	}
+	s.db.removePrefix("webhook_delivery", key(s.owner, string(s.projectCode), string(id), ""))
+	return nil
+}
+
+func (s *memWebhookStorage) Save(hook *domain.Webhook) error {
+	ok, err := s.db.insert("webhook", s.key(hook.ID), hook)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{
+			Type: "Webhook",
+			Key:  fmt.Sprintf("project_code: %s, id: %s", hook.ProjectCode, hook.ID),
+		}
+	}
+	return nil
+}
+
+func (s *memWebhookStorage) Update(hook *domain.Webhook) error {
+	return s.db.update("webhook", s.key(hook.ID), hook)
+}
+
+func (s *memWebhookStorage) ListDeliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	items := make([]*domain.WebhookDelivery, 0)
+	err := s.db.find("webhook_delivery", key(s.owner, string(s.projectCode), string(id), ""), func(data []byte) error {
+		var d *domain.WebhookDelivery
+		if err := bson.Unmarshal(data, &d); err != nil {
+			return err
+		}
+		items = append(items, d)
+		return nil
+	})
+	return items, err
+}
+
+func (s *memWebhookStorage) SaveDelivery(d *domain.WebhookDelivery) error {
+	ok, err := s.db.insert("webhook_delivery", key(s.owner, string(s.projectCode), string(d.WebhookID), d.ID), d)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &storage.UniqueIndexError{Type: "WebhookDelivery", Key: fmt.Sprintf("id: %s", d.ID)}
+	}
+	return nil
+}
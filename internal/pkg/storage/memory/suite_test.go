@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/internal/pkg/storage/storagetest"
+)
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (storage.DataStorage, func()) {
+		return memory.NewMemoryStorage(), func() {}
+	})
+}
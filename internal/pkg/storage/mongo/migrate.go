@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"log"
+	"strings"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+const (
+	codeNamespaceNotFound = 26
+	codeIndexNotFound     = 27
+)
+
+// legacyIndexes are indexes created before environments and objects were scoped by owner and project
+var legacyIndexes = []struct {
+	collection string
+	key        []string
+}{
+	{collection: "env", key: []string{"project_code", "code"}},
+	{collection: "object", key: []string{"env_code", "code"}},
+	{collection: "object", key: []string{"inherits.project_code", "inherits.env_code", "inherits.object_code"}},
+}
+
+// migrate upgrades existing data to owner scoped environments and objects.
+// It is safe to run it on every start.
+func migrate(session *mgo.Session) error {
+	conn := session.Copy()
+	defer conn.Close()
+
+	for _, idx := range legacyIndexes {
+		if err := dropIndex(getCollection(conn, idx.collection), idx.key...); err != nil {
+			return err
+		}
+	}
+	if err := backfillOwner(conn, "env", "project", func(doc bson.M) bson.M {
+		return bson.M{"code": doc["project_code"]}
+	}); err != nil {
+		return err
+	}
+	if err := backfillOwner(conn, "object", "env", func(doc bson.M) bson.M {
+		return bson.M{"project_code": doc["project_code"], "code": doc["env_code"]}
+	}); err != nil {
+		return err
+	}
+	ensureEnvIndex(getCollection(conn, "env"))
+	ensureObjIndex(getCollection(conn, "object"))
+	return nil
+}
+
+func dropIndex(collection *mgo.Collection, key ...string) error {
+	err := collection.DropIndex(key...)
+	if err == nil {
+		return nil
+	}
+	if qe, ok := err.(*mgo.QueryError); ok && (qe.Code == codeNamespaceNotFound || qe.Code == codeIndexNotFound) {
+		return nil
+	}
+	if strings.Contains(err.Error(), "not found") {
+		return nil
+	}
+	return err
+}
+
+// backfillOwner sets owner of documents saved without it. Owner is taken from the parent document,
+// documents with ambiguous parent are left untouched and reported.
+func backfillOwner(conn *mgo.Session, name, parentName string, parentQuery func(doc bson.M) bson.M) error {
+	collection := getCollection(conn, name)
+	parents := getCollection(conn, parentName)
+	iter := collection.Find(bson.M{"owner": bson.M{"$in": []interface{}{nil, ""}}}).Iter()
+	for {
+		var doc bson.M
+		if !iter.Next(&doc) {
+			break
+		}
+		var owners []string
+		if err := parents.Find(parentQuery(doc)).Distinct("owner", &owners); err != nil {
+			iter.Close()
+			return err
+		}
+		if len(owners) != 1 {
+			log.Printf("[WARN] Can't resolve owner of %s %v: %d owners found", name, doc["_id"], len(owners))
+			continue
+		}
+		if err := collection.UpdateId(doc["_id"], bson.M{"$set": bson.M{"owner": owners[0]}}); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
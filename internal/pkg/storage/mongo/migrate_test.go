@@ -0,0 +1,72 @@
+package mongo_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestMigrateLegacyData(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	session, err := mgo.Dial(MongoTestUrl)
+	assert.Nil(err)
+	defer session.Close()
+	db := session.DB("")
+
+	assert.Nil(db.C("env").EnsureIndex(mgo.Index{Key: []string{"project_code", "code"}, Unique: true}))
+	assert.Nil(db.C("object").EnsureIndex(mgo.Index{Key: []string{"env_code", "code"}, Unique: true}))
+	assert.Nil(db.C("project").Insert(
+		bson.M{"owner": "owner1", "code": "p1"},
+		bson.M{"owner": "owner2", "code": "p2"},
+		bson.M{"owner": "owner3", "code": "p2"},
+	))
+	assert.Nil(db.C("env").Insert(
+		bson.M{"owner": "owner1", "project_code": "p1", "code": "prod"},
+		bson.M{"project_code": "p2", "code": "prod"},
+	))
+	assert.Nil(db.C("object").Insert(
+		bson.M{"project_code": "p1", "env_code": "prod", "code": "obj1"},
+	))
+
+	s := getStorage()
+	assert.NotNil(s)
+
+	obj, err := s.ForOwner("owner1").Projects().For("p1").Environments().For("prod").Objects().Get("obj1")
+	assert.Nil(err)
+	assert.Equal("owner1", obj.Owner)
+
+	legacy, err := db.C("env").Find(bson.M{"project_code": "p2"}).Count()
+	assert.Nil(err)
+	assert.Equal(1, legacy)
+	resolved, err := db.C("env").Find(bson.M{"project_code": "p2", "owner": bson.M{"$exists": true}}).Count()
+	assert.Nil(err)
+	assert.Equal(0, resolved)
+
+	err = s.ForOwner("owner2").Projects().For("p1").Environments().Save(&domain.Environment{
+		OwnerID:     "owner2",
+		ProjectCode: "p1",
+		Code:        "prod",
+	})
+	assert.Nil(err)
+	err = s.ForOwner("owner2").Projects().For("p1").Environments().For("prod").Objects().Save(&domain.Object{
+		Owner:       "owner2",
+		ProjectCode: "p1",
+		EnvCode:     "prod",
+		Code:        "obj1",
+	})
+	assert.Nil(err)
+
+	getStorage()
+	indexes, err := db.C("object").Indexes()
+	assert.Nil(err)
+	for _, idx := range indexes {
+		assert.NotEqual([]string{"env_code", "code"}, idx.Key)
+	}
+
+	AfterTest()
+}
@@ -3,6 +3,7 @@ package mongo
 import (
 	"github.com/Toggly/core/internal/pkg/storage"
 	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
 	"github.com/pkg/errors"
 )
 
@@ -12,6 +13,9 @@ func NewMongoStorage(url string) (storage.DataStorage, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "Can't connect to %s", url)
 	}
+	if err = migrate(session); err != nil {
+		return nil, errors.Wrap(err, "Can't migrate storage")
+	}
 	return &mgStorage{
 		session: session,
 	}, nil
@@ -21,6 +25,53 @@ func getCollection(conn *mgo.Session, name string) *mgo.Collection {
 	return conn.DB("").C(name)
 }
 
+// updateVersion replaces document matching query only if its stored version equals version.
+// Documents stored before versioning have no version field and match version 0.
+func updateVersion(collection *mgo.Collection, query bson.M, version int64, doc interface{}) error {
+	selector := bson.M{"version": version}
+	if version == 0 {
+		selector["version"] = bson.M{"$in": []interface{}{0, nil}}
+	}
+	for k, v := range query {
+		selector[k] = v
+	}
+	err := collection.Update(selector, doc)
+	if err != mgo.ErrNotFound {
+		return err
+	}
+	n, err := collection.Find(query).Count()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return storage.ErrVersionConflict
+}
+
+// removeVersion removes the document only if it has the version, missing version matches zero
+func removeVersion(collection *mgo.Collection, query bson.M, version int64) error {
+	selector := bson.M{"version": version}
+	if version == 0 {
+		selector["version"] = bson.M{"$in": []interface{}{0, nil}}
+	}
+	for k, v := range query {
+		selector[k] = v
+	}
+	err := collection.Remove(selector)
+	if err != mgo.ErrNotFound {
+		return err
+	}
+	n, err := collection.Find(query).Count()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return storage.ErrNotFound
+	}
+	return storage.ErrVersionConflict
+}
+
 type mgStorage struct {
 	session *mgo.Session
 }
@@ -29,6 +80,10 @@ func (s *mgStorage) ForOwner(ownerID string) storage.OwnerStorage {
 	return &mgOwnerStorage{owner: ownerID, session: s.session}
 }
 
+func (s *mgStorage) Keys() storage.KeyLookupStorage {
+	return &mgKeyLookupStorage{session: s.session}
+}
+
 type mgOwnerStorage struct {
 	owner   string
 	session *mgo.Session
@@ -40,3 +95,17 @@ func (s *mgOwnerStorage) Projects() storage.ProjectStorage {
 		session: s.session,
 	}
 }
+
+func (s *mgOwnerStorage) Keys() storage.KeyStorage {
+	return &mgKeyStorage{
+		owner:   s.owner,
+		session: s.session,
+	}
+}
+
+func (s *mgOwnerStorage) Audit() storage.AuditStorage {
+	return &mgAuditStorage{
+		owner:   s.owner,
+		session: s.session,
+	}
+}
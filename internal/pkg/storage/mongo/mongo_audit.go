@@ -0,0 +1,76 @@
+package mongo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+type mgAuditStorage struct {
+	owner   string
+	session *mgo.Session
+}
+
+func (s *mgAuditStorage) query(filter *domain.AuditFilter) bson.M {
+	query := bson.M{"owner": s.owner}
+	if filter.Entity != "" {
+		query["$or"] = []bson.M{
+			{"entity": filter.Entity},
+			{"entity": bson.M{"$regex": "^" + regexp.QuoteMeta(strings.TrimSuffix(filter.Entity, "/")+"/")}},
+		}
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	date := bson.M{}
+	if !filter.From.IsZero() {
+		date["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		date["$lte"] = filter.To
+	}
+	if len(date) > 0 {
+		query["date"] = date
+	}
+	return query
+}
+
+func (s *mgAuditStorage) List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.AuditRecord, 0)
+	err := getCollection(conn, "audit").Find(s.query(filter)).Sort("date", "_id").All(&items)
+	return items, err
+}
+
+func ensureAuditIndex(collection *mgo.Collection) {
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"owner", "id"},
+		Unique: true,
+	})
+	collection.EnsureIndex(mgo.Index{
+		Key: []string{"owner", "date"},
+	})
+}
+
+func (s *mgAuditStorage) Save(rec *domain.AuditRecord) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "audit")
+	ensureAuditIndex(collection)
+
+	err := collection.Insert(rec)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{Type: "AuditRecord", Key: fmt.Sprintf("id: %s", rec.ID)}
+		}
+		return err
+	}
+	return nil
+}
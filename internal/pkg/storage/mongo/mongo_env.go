@@ -3,8 +3,8 @@ package mongo
 import (
 	"fmt"
 
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 )
@@ -19,14 +19,14 @@ func (s *mgoEnvStorage) List() ([]*domain.Environment, error) {
 	conn := s.session.Copy()
 	defer conn.Close()
 	items := make([]*domain.Environment, 0)
-	err := getCollection(conn, "env").Find(bson.M{"project_code": s.projectCode}).All(&items)
+	err := getCollection(conn, "env").Find(bson.M{"owner": s.owner, "project_code": s.projectCode}).All(&items)
 	return items, err
 }
 
 func (s *mgoEnvStorage) Get(code domain.EnvironmentCode) (env *domain.Environment, err error) {
 	conn := s.session.Copy()
 	defer conn.Close()
-	err = getCollection(conn, "env").Find(bson.M{"project_code": s.projectCode, "code": code}).One(&env)
+	err = getCollection(conn, "env").Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "code": code}).One(&env)
 	if err == mgo.ErrNotFound {
 		return nil, storage.ErrNotFound
 	}
@@ -36,7 +36,7 @@ func (s *mgoEnvStorage) Get(code domain.EnvironmentCode) (env *domain.Environmen
 func (s *mgoEnvStorage) Delete(code domain.EnvironmentCode) (err error) {
 	conn := s.session.Copy()
 	defer conn.Close()
-	err = getCollection(conn, "env").Remove(bson.M{"project_code": s.projectCode, "code": code})
+	err = getCollection(conn, "env").Remove(bson.M{"owner": s.owner, "project_code": s.projectCode, "code": code})
 	if err == mgo.ErrNotFound {
 		return storage.ErrNotFound
 	}
@@ -45,7 +45,7 @@ func (s *mgoEnvStorage) Delete(code domain.EnvironmentCode) (err error) {
 
 func ensureEnvIndex(collection *mgo.Collection) {
 	idx := mgo.Index{
-		Key:    []string{"project_code", "code"},
+		Key:    []string{"owner", "project_code", "code"},
 		Unique: true,
 	}
 	collection.EnsureIndex(idx)
@@ -78,11 +78,13 @@ func (s *mgoEnvStorage) Update(env *domain.Environment) error {
 	collection := getCollection(conn, "env")
 	ensureEnvIndex(collection)
 
-	err := collection.Update(bson.M{"project_code": env.ProjectCode, "code": env.Code}, env)
+	version := env.Version
+	env.Version++
+	err := updateVersion(collection, bson.M{"owner": s.owner, "project_code": s.projectCode, "code": env.Code}, version, env)
 	if err != nil {
-		return err
+		env.Version = version
 	}
-	return nil
+	return err
 }
 
 func (s *mgoEnvStorage) For(code domain.EnvironmentCode) storage.ForEnvironment {
@@ -109,3 +111,12 @@ func (s *mgoForEnvironment) Objects() storage.ObjectStorage {
 		owner:       s.owner,
 	}
 }
+
+func (s *mgoForEnvironment) Revisions() storage.RevisionStorage {
+	return &mgoRevisionStorage{
+		projectCode: s.projectCode,
+		envCode:     s.env,
+		session:     s.session,
+		owner:       s.owner,
+	}
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+type mgKeyLookupStorage struct {
+	session *mgo.Session
+}
+
+func (s *mgKeyLookupStorage) GetByHash(hash string) (key *domain.APIKey, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "apikey").Find(bson.M{"hash": hash}).One(&key)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return key, err
+}
+
+type mgKeyStorage struct {
+	owner   string
+	session *mgo.Session
+}
+
+func (s *mgKeyStorage) List() ([]*domain.APIKey, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.APIKey, 0)
+	err := getCollection(conn, "apikey").Find(bson.M{"owner": s.owner}).All(&items)
+	return items, err
+}
+
+func (s *mgKeyStorage) Get(id domain.APIKeyID) (key *domain.APIKey, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "apikey").Find(bson.M{"owner": s.owner, "id": id}).One(&key)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return key, err
+}
+
+func (s *mgKeyStorage) Delete(id domain.APIKeyID) (err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "apikey").Remove(bson.M{"owner": s.owner, "id": id})
+	if err == mgo.ErrNotFound {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+func ensureKeyIndex(collection *mgo.Collection) {
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"owner", "id"},
+		Unique: true,
+	})
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"hash"},
+		Unique: true,
+	})
+}
+
+func (s *mgKeyStorage) Save(key *domain.APIKey) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "apikey")
+	ensureKeyIndex(collection)
+
+	err := collection.Insert(key)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{
+				Type: "APIKey",
+				Key:  fmt.Sprintf("id: %s", key.ID),
+			}
+		}
+		return err
+	}
+	return nil
+}
@@ -5,7 +5,7 @@ import (
 
 	"github.com/Toggly/core/internal/pkg/storage"
 
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 )
@@ -21,18 +21,18 @@ func (s *mgoObjectStorage) List() ([]*domain.Object, error) {
 	conn := s.session.Copy()
 	defer conn.Close()
 	items := make([]*domain.Object, 0)
-	err := getCollection(conn, "object").Find(bson.M{"env_code": s.envCode}).All(&items)
+	err := getCollection(conn, "object").Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "env_code": s.envCode}).All(&items)
 	return items, err
 }
 
 func (s *mgoObjectStorage) Get(code domain.ObjectCode) (obj *domain.Object, err error) {
 	conn := s.session.Copy()
 	defer conn.Close()
-	err = getCollection(conn, "object").Find(bson.M{"env_code": s.envCode, "code": code}).One(&obj)
+	err = getCollection(conn, "object").Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "env_code": s.envCode, "code": code}).One(&obj)
 	if err == mgo.ErrNotFound {
 		return nil, storage.ErrNotFound
 	}
-	return obj, nil
+	return obj, err
 }
 
 func (s *mgoObjectStorage) ListInheritors(code domain.ObjectCode) ([]*domain.Object, error) {
@@ -51,6 +51,7 @@ func (s *mgoObjectStorage) ListInheritors(code domain.ObjectCode) ([]*domain.Obj
 		}
 	}
 	query := bson.M{
+		"owner":                 s.owner,
 		"inherits.project_code": obj.ProjectCode,
 		"inherits.env_code":     obj.EnvCode,
 		"inherits.object_code":  obj.Code,
@@ -59,23 +60,20 @@ func (s *mgoObjectStorage) ListInheritors(code domain.ObjectCode) ([]*domain.Obj
 	return items, err
 }
 
-func (s *mgoObjectStorage) Delete(code domain.ObjectCode) (err error) {
+func (s *mgoObjectStorage) Delete(code domain.ObjectCode, version int64) error {
 	conn := s.session.Copy()
 	defer conn.Close()
-	err = getCollection(conn, "object").Remove(bson.M{"env_code": s.envCode, "code": code})
-	if err == mgo.ErrNotFound {
-		return storage.ErrNotFound
-	}
-	return err
+	query := bson.M{"owner": s.owner, "project_code": s.projectCode, "env_code": s.envCode, "code": code}
+	return removeVersion(getCollection(conn, "object"), query, version)
 }
 
 func ensureObjIndex(collection *mgo.Collection) {
 	collection.EnsureIndex(mgo.Index{
-		Key:    []string{"env_code", "code"},
+		Key:    []string{"owner", "project_code", "env_code", "code"},
 		Unique: true,
 	})
 	collection.EnsureIndex(mgo.Index{
-		Key: []string{"inherits.project_code", "inherits.env_code", "inherits.object_code"},
+		Key: []string{"owner", "inherits.project_code", "inherits.env_code", "inherits.object_code"},
 	})
 }
 
@@ -106,9 +104,11 @@ func (s *mgoObjectStorage) Update(obj *domain.Object) error {
 	collection := getCollection(conn, "object")
 	ensureObjIndex(collection)
 
-	err := collection.Update(bson.M{"env_code": obj.EnvCode, "code": obj.Code}, obj)
+	version := obj.Version
+	obj.Version++
+	err := updateVersion(collection, bson.M{"owner": s.owner, "project_code": s.projectCode, "env_code": s.envCode, "code": obj.Code}, version, obj)
 	if err != nil {
-		return err
+		obj.Version = version
 	}
-	return nil
+	return err
 }
@@ -3,8 +3,8 @@ package mongo
 import (
 	"fmt"
 
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 )
@@ -77,17 +77,20 @@ func (s *mgProjectStorage) Update(project *domain.Project) error {
 	collection := getCollection(conn, "project")
 	ensureProjIndex(collection)
 
-	err := collection.Update(bson.M{"owner": project.OwnerID, "code": project.Code}, project)
+	version := project.Version
+	project.Version++
+	err := updateVersion(collection, bson.M{"owner": s.owner, "code": project.Code}, version, project)
 	if err != nil {
-		return err
+		project.Version = version
 	}
-	return nil
+	return err
 }
 
 func (s *mgProjectStorage) For(projectCode domain.ProjectCode) storage.ForProject {
 	return &mgForProject{
 		projectCode: projectCode,
 		session:     s.session,
+		owner:       s.owner,
 	}
 }
 
@@ -104,3 +107,19 @@ func (s *mgForProject) Environments() storage.EnvironmentStorage {
 		owner:       s.owner,
 	}
 }
+
+func (s *mgForProject) Webhooks() storage.WebhookStorage {
+	return &mgoWebhookStorage{
+		projectCode: s.projectCode,
+		session:     s.session,
+		owner:       s.owner,
+	}
+}
+
+func (s *mgForProject) Segments() storage.SegmentStorage {
+	return &mgoSegmentStorage{
+		projectCode: s.projectCode,
+		session:     s.session,
+		owner:       s.owner,
+	}
+}
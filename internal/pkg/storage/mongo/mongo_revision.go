@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+type mgoRevisionStorage struct {
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	session     *mgo.Session
+	owner       string
+}
+
+func (s *mgoRevisionStorage) query(code domain.ObjectCode) bson.M {
+	return bson.M{"owner": s.owner, "project_code": s.projectCode, "env_code": s.envCode, "object_code": code}
+}
+
+func (s *mgoRevisionStorage) List(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.ObjectRevision, 0)
+	err := getCollection(conn, "object_revision").Find(s.query(code)).Sort("revision").All(&items)
+	return items, err
+}
+
+func (s *mgoRevisionStorage) Get(code domain.ObjectCode, revision int64) (rev *domain.ObjectRevision, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	query := s.query(code)
+	query["revision"] = revision
+	err = getCollection(conn, "object_revision").Find(query).One(&rev)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return rev, err
+}
+
+func (s *mgoRevisionStorage) Latest(code domain.ObjectCode) (rev *domain.ObjectRevision, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "object_revision").Find(s.query(code)).Sort("-revision").One(&rev)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return rev, err
+}
+
+func ensureRevisionIndex(collection *mgo.Collection) {
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"owner", "project_code", "env_code", "object_code", "revision"},
+		Unique: true,
+	})
+}
+
+func (s *mgoRevisionStorage) Save(rev *domain.ObjectRevision) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "object_revision")
+	ensureRevisionIndex(collection)
+
+	err := collection.Insert(rev)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{
+				Type: "ObjectRevision",
+				Key:  fmt.Sprintf("object_code: %s, revision: %d", rev.ObjectCode, rev.Revision),
+			}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+type mgoSegmentStorage struct {
+	projectCode domain.ProjectCode
+	session     *mgo.Session
+	owner       string
+}
+
+func (s *mgoSegmentStorage) List() ([]*domain.Segment, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.Segment, 0)
+	err := getCollection(conn, "segment").Find(bson.M{"owner": s.owner, "project_code": s.projectCode}).All(&items)
+	return items, err
+}
+
+func (s *mgoSegmentStorage) Get(code domain.SegmentCode) (segment *domain.Segment, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "segment").Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "code": code}).One(&segment)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return segment, err
+}
+
+func (s *mgoSegmentStorage) Delete(code domain.SegmentCode) (err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "segment").Remove(bson.M{"owner": s.owner, "project_code": s.projectCode, "code": code})
+	if err == mgo.ErrNotFound {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+func ensureSegmentIndex(collection *mgo.Collection) {
+	idx := mgo.Index{
+		Key:    []string{"owner", "project_code", "code"},
+		Unique: true,
+	}
+	collection.EnsureIndex(idx)
+}
+
+func (s *mgoSegmentStorage) Save(segment *domain.Segment) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "segment")
+	ensureSegmentIndex(collection)
+
+	err := collection.Insert(segment)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{
+				Type: "Segment",
+				Key:  fmt.Sprintf("project_code: %s, code: %s", segment.ProjectCode, segment.Code),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *mgoSegmentStorage) Update(segment *domain.Segment) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "segment")
+	ensureSegmentIndex(collection)
+
+	err := collection.Update(bson.M{"owner": s.owner, "project_code": s.projectCode, "code": segment.Code}, segment)
+	if err == mgo.ErrNotFound {
+		return storage.ErrNotFound
+	}
+	return err
+}
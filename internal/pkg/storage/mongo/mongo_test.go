@@ -0,0 +1,27 @@
+package mongo_test
+
+import (
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/mongo"
+	"github.com/globalsign/mgo"
+)
+
+const MongoTestUrl = "mongodb://localhost:27017/toggly_storage_test"
+
+func getStorage() storage.DataStorage {
+	dataStorage, _ := mongo.NewMongoStorage(MongoTestUrl)
+	return dataStorage
+}
+
+func DropDB() {
+	session, _ := mgo.Dial(MongoTestUrl)
+	session.DB("").DropDatabase()
+}
+
+func BeforeTest() {
+	DropDB()
+}
+
+func AfterTest() {
+	DropDB()
+}
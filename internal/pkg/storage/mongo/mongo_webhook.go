@@ -0,0 +1,127 @@
+package mongo
+
+import (
+	"fmt"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+type mgoWebhookStorage struct {
+	projectCode domain.ProjectCode
+	session     *mgo.Session
+	owner       string
+}
+
+func (s *mgoWebhookStorage) List() ([]*domain.Webhook, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.Webhook, 0)
+	err := getCollection(conn, "webhook").Find(bson.M{"owner": s.owner, "project_code": s.projectCode}).Sort("reg_date", "_id").All(&items)
+	return items, err
+}
+
+func (s *mgoWebhookStorage) Get(id domain.WebhookID) (hook *domain.Webhook, err error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err = getCollection(conn, "webhook").Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "id": id}).One(&hook)
+	if err == mgo.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	return hook, err
+}
+
+func (s *mgoWebhookStorage) Delete(id domain.WebhookID) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+	err := getCollection(conn, "webhook").Remove(bson.M{"owner": s.owner, "project_code": s.projectCode, "id": id})
+	if err == mgo.ErrNotFound {
+		return storage.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = getCollection(conn, "webhook_delivery").RemoveAll(bson.M{"owner": s.owner, "project_code": s.projectCode, "webhook_id": id})
+	return err
+}
+
+func ensureWebhookIndex(collection *mgo.Collection) {
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"owner", "project_code", "id"},
+		Unique: true,
+	})
+}
+
+func (s *mgoWebhookStorage) Save(hook *domain.Webhook) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "webhook")
+	ensureWebhookIndex(collection)
+
+	err := collection.Insert(hook)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{
+				Type: "Webhook",
+				Key:  fmt.Sprintf("project_code: %s, id: %s", hook.ProjectCode, hook.ID),
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *mgoWebhookStorage) Update(hook *domain.Webhook) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "webhook")
+	ensureWebhookIndex(collection)
+
+	err := collection.Update(bson.M{"owner": s.owner, "project_code": s.projectCode, "id": hook.ID}, hook)
+	if err == mgo.ErrNotFound {
+		return storage.ErrNotFound
+	}
+	return err
+}
+
+func (s *mgoWebhookStorage) ListDeliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	conn := s.session.Copy()
+	defer conn.Close()
+	items := make([]*domain.WebhookDelivery, 0)
+	err := getCollection(conn, "webhook_delivery").
+		Find(bson.M{"owner": s.owner, "project_code": s.projectCode, "webhook_id": id}).
+		Sort("date", "_id").
+		All(&items)
+	return items, err
+}
+
+func ensureDeliveryIndex(collection *mgo.Collection) {
+	collection.EnsureIndex(mgo.Index{
+		Key:    []string{"owner", "id"},
+		Unique: true,
+	})
+	collection.EnsureIndex(mgo.Index{
+		Key: []string{"owner", "project_code", "webhook_id", "date"},
+	})
+}
+
+func (s *mgoWebhookStorage) SaveDelivery(d *domain.WebhookDelivery) error {
+	conn := s.session.Copy()
+	defer conn.Close()
+
+	collection := getCollection(conn, "webhook_delivery")
+	ensureDeliveryIndex(collection)
+
+	err := collection.Insert(d)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return &storage.UniqueIndexError{Type: "WebhookDelivery", Key: fmt.Sprintf("id: %s", d.ID)}
+		}
+		return err
+	}
+	return nil
+}
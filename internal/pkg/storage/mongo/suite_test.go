@@ -0,0 +1,15 @@
+package mongo_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/storagetest"
+)
+
+func TestStorageSuite(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (storage.DataStorage, func()) {
+		BeforeTest()
+		return getStorage(), AfterTest
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/domain"
 )
 
 // UniqueIndexError type
@@ -20,19 +20,45 @@ func (e *UniqueIndexError) Error() string {
 var (
 	// ErrNotFound error
 	ErrNotFound = errors.New("not found")
+	// ErrVersionConflict error
+	ErrVersionConflict = errors.New("version conflict")
 )
 
 // DataStorage defines storage interface
 type DataStorage interface {
 	ForOwner(ownerID string) OwnerStorage
+	Keys() KeyLookupStorage
+}
+
+// KeyLookupStorage defines api keys lookup interface
+type KeyLookupStorage interface {
+	GetByHash(hash string) (*domain.APIKey, error)
 }
 
 // OwnerStorage defines owner storage interface
 type OwnerStorage interface {
 	Projects() ProjectStorage
+	Keys() KeyStorage
+	Audit() AuditStorage
+}
+
+// AuditStorage defines append-only owner audit log interface.
+// List returns records matching the filter in the order they were saved.
+type AuditStorage interface {
+	List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error)
+	Save(rec *domain.AuditRecord) error
+}
+
+// KeyStorage defines owner api keys storage interface
+type KeyStorage interface {
+	List() ([]*domain.APIKey, error)
+	Get(id domain.APIKeyID) (*domain.APIKey, error)
+	Delete(id domain.APIKeyID) error
+	Save(key *domain.APIKey) error
 }
 
-// ProjectStorage defines projects storage interface
+// ProjectStorage defines projects storage interface.
+// Update succeeds only if stored version equals project version, then increments it.
 type ProjectStorage interface {
 	List() ([]*domain.Project, error)
 	Get(code domain.ProjectCode) (*domain.Project, error)
@@ -45,9 +71,33 @@ type ProjectStorage interface {
 // ForProject defines project dependencies interface
 type ForProject interface {
 	Environments() EnvironmentStorage
+	Segments() SegmentStorage
+	Webhooks() WebhookStorage
 }
 
-// EnvironmentStorage defines environment storage interface
+// WebhookStorage defines project webhooks storage interface.
+// Deliveries are listed in the order they were saved, Delete removes webhook deliveries as well.
+type WebhookStorage interface {
+	List() ([]*domain.Webhook, error)
+	Get(id domain.WebhookID) (*domain.Webhook, error)
+	Delete(id domain.WebhookID) error
+	Save(hook *domain.Webhook) error
+	Update(hook *domain.Webhook) error
+	ListDeliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error)
+	SaveDelivery(delivery *domain.WebhookDelivery) error
+}
+
+// SegmentStorage defines segment storage interface
+type SegmentStorage interface {
+	List() ([]*domain.Segment, error)
+	Get(code domain.SegmentCode) (*domain.Segment, error)
+	Delete(code domain.SegmentCode) error
+	Save(segment *domain.Segment) error
+	Update(segment *domain.Segment) error
+}
+
+// EnvironmentStorage defines environment storage interface.
+// Update succeeds only if stored version equals environment version, then increments it.
 type EnvironmentStorage interface {
 	List() ([]*domain.Environment, error)
 	Get(code domain.EnvironmentCode) (*domain.Environment, error)
@@ -60,14 +110,26 @@ type EnvironmentStorage interface {
 // ForEnvironment defines environment dependencies interface
 type ForEnvironment interface {
 	Objects() ObjectStorage
+	Revisions() RevisionStorage
+}
+
+// RevisionStorage defines object revisions storage interface.
+// Revisions are listed in ascending order, Save returns UniqueIndexError if the revision exists.
+type RevisionStorage interface {
+	List(code domain.ObjectCode) ([]*domain.ObjectRevision, error)
+	Get(code domain.ObjectCode, revision int64) (*domain.ObjectRevision, error)
+	Latest(code domain.ObjectCode) (*domain.ObjectRevision, error)
+	Save(rev *domain.ObjectRevision) error
 }
 
-// ObjectStorage defines object structure storage interface
+// ObjectStorage defines object structure storage interface.
+// Update succeeds only if stored version equals object version, then increments it.
+// Delete succeeds only if stored version equals the version.
 type ObjectStorage interface {
 	List() ([]*domain.Object, error)
 	Get(code domain.ObjectCode) (*domain.Object, error)
 	ListInheritors(code domain.ObjectCode) ([]*domain.Object, error)
-	Delete(code domain.ObjectCode) error
+	Delete(code domain.ObjectCode, version int64) error
 	Save(object *domain.Object) error
 	Update(object *domain.Object) error
 }
@@ -0,0 +1,752 @@
+// Package storagetest provides behavioural tests shared by all storage.DataStorage backends
+package storagetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+// Factory returns an empty storage and a function releasing it
+type Factory func(t *testing.T) (storage.DataStorage, func())
+
+const ow = "test_owner"
+
+// Run runs the suite against storages created by factory. Every test gets a new storage.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.DataStorage)
+	}{
+		{"Projects", testProjects},
+		{"Environments", testEnvironments},
+		{"Segments", testSegments},
+		{"Objects", testObjects},
+		{"Inheritors", testInheritors},
+		{"Keys", testKeys},
+		{"Versions", testVersions},
+		{"Revisions", testRevisions},
+		{"Audit", testAudit},
+		{"Webhooks", testWebhooks},
+		{"OwnerIsolation", testOwnerIsolation},
+		{"TenantIsolation", testTenantIsolation},
+	}
+	for _, tc := range tests {
+		fn := tc.fn
+		t.Run(tc.name, func(t *testing.T) {
+			s, release := factory(t)
+			defer release()
+			fn(t, s)
+		})
+	}
+}
+
+func testProjects(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	projects := s.ForOwner(ow).Projects()
+
+	list, err := projects.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = projects.Get("p1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, projects.Update(&domain.Project{OwnerID: ow, Code: "p1"}))
+	assert.Equal(storage.ErrNotFound, projects.Delete("p1"))
+
+	assert.Nil(projects.Save(&domain.Project{OwnerID: ow, Code: "p1", Description: "first", Status: domain.ProjectStatusActive}))
+	assert.Nil(projects.Save(&domain.Project{OwnerID: ow, Code: "p2"}))
+	err = projects.Save(&domain.Project{OwnerID: ow, Code: "p1"})
+	assert.Equal(&storage.UniqueIndexError{Type: "Project", Key: "owner: test_owner, code: p1"}, err)
+
+	p, err := projects.Get("p1")
+	assert.Nil(err)
+	assert.Equal(domain.ProjectCode("p1"), p.Code)
+	assert.Equal("first", p.Description)
+	assert.Equal(domain.ProjectStatusActive, p.Status)
+
+	list, err = projects.List()
+	assert.Nil(err)
+	assert.ElementsMatch([]domain.ProjectCode{"p1", "p2"}, projectCodes(list))
+
+	assert.Nil(projects.Update(&domain.Project{OwnerID: ow, Code: "p1", Description: "updated", Status: domain.ProjectStatusDisabled}))
+	p, err = projects.Get("p1")
+	assert.Nil(err)
+	assert.Equal("updated", p.Description)
+	assert.Equal(domain.ProjectStatusDisabled, p.Status)
+
+	assert.Nil(projects.Delete("p1"))
+	_, err = projects.Get("p1")
+	assert.Equal(storage.ErrNotFound, err)
+	list, err = projects.List()
+	assert.Nil(err)
+	assert.ElementsMatch([]domain.ProjectCode{"p2"}, projectCodes(list))
+}
+
+func testEnvironments(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	envs := s.ForOwner(ow).Projects().For("p1").Environments()
+
+	list, err := envs.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = envs.Get("dev")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, envs.Update(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev"}))
+	assert.Equal(storage.ErrNotFound, envs.Delete("dev"))
+
+	assert.Nil(envs.Save(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev", Description: "first"}))
+	assert.Nil(envs.Save(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "prod"}))
+	err = envs.Save(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev"})
+	assert.Equal(&storage.UniqueIndexError{Type: "Environment", Key: "project_code: p1, code: dev"}, err)
+
+	env, err := envs.Get("dev")
+	assert.Nil(err)
+	assert.Equal("first", env.Description)
+	assert.False(env.Protected)
+
+	list, err = envs.List()
+	assert.Nil(err)
+	assert.Len(list, 2)
+
+	assert.Nil(envs.Update(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev", Description: "updated", Protected: true}))
+	env, err = envs.Get("dev")
+	assert.Nil(err)
+	assert.Equal("updated", env.Description)
+	assert.True(env.Protected)
+
+	other, err := s.ForOwner(ow).Projects().For("p2").Environments().List()
+	assert.Nil(err)
+	assert.Empty(other)
+
+	assert.Nil(envs.Delete("dev"))
+	_, err = envs.Get("dev")
+	assert.Equal(storage.ErrNotFound, err)
+	list, err = envs.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+}
+
+func testSegments(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	segments := s.ForOwner(ow).Projects().For("p1").Segments()
+
+	list, err := segments.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = segments.Get("beta")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, segments.Update(&domain.Segment{OwnerID: ow, ProjectCode: "p1", Code: "beta"}))
+	assert.Equal(storage.ErrNotFound, segments.Delete("beta"))
+
+	assert.Nil(segments.Save(&domain.Segment{OwnerID: ow, ProjectCode: "p1", Code: "beta", Include: []string{"u1"}}))
+	err = segments.Save(&domain.Segment{OwnerID: ow, ProjectCode: "p1", Code: "beta"})
+	assert.IsType(&storage.UniqueIndexError{}, err)
+
+	seg, err := segments.Get("beta")
+	assert.Nil(err)
+	assert.Equal([]string{"u1"}, seg.Include)
+
+	assert.Nil(segments.Update(&domain.Segment{OwnerID: ow, ProjectCode: "p1", Code: "beta", Exclude: []string{"u2"}}))
+	seg, err = segments.Get("beta")
+	assert.Nil(err)
+	assert.Empty(seg.Include)
+	assert.Equal([]string{"u2"}, seg.Exclude)
+
+	other, err := s.ForOwner(ow).Projects().For("p2").Segments().List()
+	assert.Nil(err)
+	assert.Empty(other)
+
+	assert.Nil(segments.Delete("beta"))
+	list, err = segments.List()
+	assert.Nil(err)
+	assert.Empty(list)
+}
+
+func testObjects(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	objects := s.ForOwner(ow).Projects().For("p1").Environments().For("dev").Objects()
+
+	list, err := objects.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = objects.Get("obj1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, objects.Update(newObject(ow, "p1", "dev", "obj1", nil)))
+	assert.Equal(storage.ErrNotFound, objects.Delete("obj1", 0))
+
+	obj := newObject(ow, "p1", "dev", "obj1", nil)
+	obj.Parameters = []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: true}}
+	assert.Nil(objects.Save(obj))
+	assert.Nil(objects.Save(newObject(ow, "p1", "dev", "obj2", nil)))
+	err = objects.Save(newObject(ow, "p1", "dev", "obj1", nil))
+	assert.Equal(&storage.UniqueIndexError{Type: "Object", Key: "env_code: dev, code: obj1"}, err)
+
+	o, err := objects.Get("obj1")
+	assert.Nil(err)
+	assert.Equal(domain.ObjectCode("obj1"), o.Code)
+	assert.Len(o.Parameters, 1)
+	assert.Equal(domain.ParameterCode("param1"), o.Parameters[0].Code)
+	assert.Equal(true, o.Parameters[0].Value)
+
+	list, err = objects.List()
+	assert.Nil(err)
+	assert.ElementsMatch([]domain.ObjectCode{"obj1", "obj2"}, objectCodes(list))
+
+	updated := newObject(ow, "p1", "dev", "obj1", nil)
+	updated.Description = "updated"
+	assert.Nil(objects.Update(updated))
+	o, err = objects.Get("obj1")
+	assert.Nil(err)
+	assert.Equal("updated", o.Description)
+	assert.Empty(o.Parameters)
+
+	other, err := s.ForOwner(ow).Projects().For("p1").Environments().For("prod").Objects().List()
+	assert.Nil(err)
+	assert.Empty(other)
+
+	assert.Equal(storage.ErrVersionConflict, objects.Delete("obj1", 0))
+	assert.Nil(objects.Delete("obj1", 1))
+	_, err = objects.Get("obj1")
+	assert.Equal(storage.ErrNotFound, err)
+	list, err = objects.List()
+	assert.Nil(err)
+	assert.ElementsMatch([]domain.ObjectCode{"obj2"}, objectCodes(list))
+}
+
+func testInheritors(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	objects := func(owner string, project domain.ProjectCode, env domain.EnvironmentCode) storage.ObjectStorage {
+		return s.ForOwner(owner).Projects().For(project).Environments().For(env).Objects()
+	}
+	parent := &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "parent"}
+
+	inheritors, err := objects(ow, "p1", "dev").ListInheritors("parent")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+
+	assert.Nil(objects(ow, "p1", "dev").Save(newObject(ow, "p1", "dev", "parent", nil)))
+	assert.Nil(objects(ow, "p1", "dev").Save(newObject(ow, "p1", "dev", "other", nil)))
+	assert.Nil(objects(ow, "p1", "dev").Save(newObject(ow, "p1", "dev", "child", parent)))
+	assert.Nil(objects(ow, "p1", "prod").Save(newObject(ow, "p1", "prod", "child", parent)))
+	assert.Nil(objects(ow, "p2", "dev").Save(newObject(ow, "p2", "dev", "child", parent)))
+	assert.Nil(objects("another_owner", "p1", "dev").Save(newObject("another_owner", "p1", "dev", "child", parent)))
+
+	inheritors, err = objects(ow, "p1", "dev").ListInheritors("parent")
+	assert.Nil(err)
+	assert.Len(inheritors, 3)
+	found := make([]string, 0)
+	for _, o := range inheritors {
+		assert.Equal(ow, o.Owner)
+		assert.Equal(domain.ObjectCode("child"), o.Code)
+		found = append(found, string(o.ProjectCode)+"/"+string(o.EnvCode))
+	}
+	assert.ElementsMatch([]string{"p1/dev", "p1/prod", "p2/dev"}, found)
+
+	for _, env := range []domain.EnvironmentCode{"prod", "stage"} {
+		inheritors, err = objects(ow, "p1", env).ListInheritors("parent")
+		assert.Nil(err)
+		assert.Empty(inheritors)
+	}
+
+	moved := newObject(ow, "p1", "prod", "child", &domain.ObjectInheritance{ProjectCode: "p1", EnvCode: "dev", ObjectCode: "other"})
+	assert.Nil(objects(ow, "p1", "prod").Update(moved))
+	assert.Nil(objects(ow, "p2", "dev").Delete("child", 0))
+
+	inheritors, err = objects(ow, "p1", "dev").ListInheritors("parent")
+	assert.Nil(err)
+	assert.Len(inheritors, 1)
+	inheritors, err = objects(ow, "p1", "dev").ListInheritors("other")
+	assert.Nil(err)
+	assert.Len(inheritors, 1)
+	assert.Equal(domain.EnvironmentCode("prod"), inheritors[0].EnvCode)
+
+	inheritors, err = objects("another_owner", "p1", "dev").ListInheritors("parent")
+	assert.Nil(err)
+	assert.Empty(inheritors)
+}
+
+func testKeys(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	keys := s.ForOwner(ow).Keys()
+
+	list, err := keys.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = keys.Get("k1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, keys.Delete("k1"))
+	_, err = s.Keys().GetByHash("hash1")
+	assert.Equal(storage.ErrNotFound, err)
+
+	assert.Nil(keys.Save(&domain.APIKey{ID: "k1", OwnerID: ow, Hash: "hash1", Role: domain.RoleAdmin}))
+	assert.Nil(keys.Save(&domain.APIKey{ID: "k2", OwnerID: ow, Hash: "hash2", Role: domain.RoleEditor, ProjectCode: "p1"}))
+	assert.IsType(&storage.UniqueIndexError{}, keys.Save(&domain.APIKey{ID: "k1", OwnerID: ow, Hash: "hash3"}))
+	assert.IsType(&storage.UniqueIndexError{}, keys.Save(&domain.APIKey{ID: "k3", OwnerID: ow, Hash: "hash1"}))
+
+	k, err := keys.Get("k2")
+	assert.Nil(err)
+	assert.Equal("hash2", k.Hash)
+	assert.Equal(domain.RoleEditor, k.Role)
+	assert.Equal(domain.ProjectCode("p1"), k.ProjectCode)
+	assert.Empty(k.Key)
+
+	k, err = s.Keys().GetByHash("hash1")
+	assert.Nil(err)
+	assert.Equal(domain.APIKeyID("k1"), k.ID)
+	assert.Equal(ow, k.OwnerID)
+
+	list, err = keys.List()
+	assert.Nil(err)
+	assert.Len(list, 2)
+
+	assert.Nil(keys.Delete("k1"))
+	_, err = keys.Get("k1")
+	assert.Equal(storage.ErrNotFound, err)
+	_, err = s.Keys().GetByHash("hash1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Nil(keys.Save(&domain.APIKey{ID: "k1", OwnerID: ow, Hash: "hash1"}))
+}
+
+func testVersions(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	projects := s.ForOwner(ow).Projects()
+	envs := projects.For("p1").Environments()
+	objects := envs.For("dev").Objects()
+
+	assert.Nil(projects.Save(&domain.Project{OwnerID: ow, Code: "p1", Version: 1}))
+	assert.Nil(envs.Save(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev", Version: 1}))
+	assert.Nil(objects.Save(newVersionedObject("obj1", 1, "")))
+
+	p := &domain.Project{OwnerID: ow, Code: "p1", Description: "updated", Version: 1}
+	assert.Nil(projects.Update(p))
+	assert.Equal(int64(2), p.Version)
+	stale := &domain.Project{OwnerID: ow, Code: "p1", Description: "stale", Version: 1}
+	assert.Equal(storage.ErrVersionConflict, projects.Update(stale))
+	assert.Equal(int64(1), stale.Version)
+	p, err := projects.Get("p1")
+	assert.Nil(err)
+	assert.Equal(int64(2), p.Version)
+	assert.Equal("updated", p.Description)
+
+	env := &domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev", Protected: true, Version: 1}
+	assert.Nil(envs.Update(env))
+	assert.Equal(int64(2), env.Version)
+	assert.Equal(storage.ErrVersionConflict, envs.Update(&domain.Environment{OwnerID: ow, ProjectCode: "p1", Code: "dev", Version: 3}))
+	env, err = envs.Get("dev")
+	assert.Nil(err)
+	assert.Equal(int64(2), env.Version)
+	assert.True(env.Protected)
+
+	obj := newVersionedObject("obj1", 1, "updated")
+	assert.Nil(objects.Update(obj))
+	assert.Equal(int64(2), obj.Version)
+	assert.Equal(storage.ErrVersionConflict, objects.Update(newVersionedObject("obj1", 1, "stale")))
+	assert.Equal(storage.ErrNotFound, objects.Update(newVersionedObject("obj2", 1, "")))
+	obj, err = objects.Get("obj1")
+	assert.Nil(err)
+	assert.Equal(int64(2), obj.Version)
+	assert.Equal("updated", obj.Description)
+}
+
+func newVersionedObject(code domain.ObjectCode, version int64, description string) *domain.Object {
+	obj := newObject(ow, "p1", "dev", code, nil)
+	obj.Version = version
+	obj.Description = description
+	return obj
+}
+
+func testRevisions(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	revisions := s.ForOwner(ow).Projects().For("p1").Environments().For("dev").Revisions()
+	revision := func(code domain.ObjectCode, n int64, action domain.RevisionAction) *domain.ObjectRevision {
+		return &domain.ObjectRevision{
+			Owner:       ow,
+			ProjectCode: "p1",
+			EnvCode:     "dev",
+			ObjectCode:  code,
+			Revision:    n,
+			Action:      action,
+			Actor:       "key1",
+			Object:      newObject(ow, "p1", "dev", code, nil),
+			Diff:        []*domain.Change{{Field: "description", Old: "old", New: "new"}},
+		}
+	}
+
+	list, err := revisions.List("obj1")
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = revisions.Get("obj1", 1)
+	assert.Equal(storage.ErrNotFound, err)
+	_, err = revisions.Latest("obj1")
+	assert.Equal(storage.ErrNotFound, err)
+
+	assert.Nil(revisions.Save(revision("obj1", 2, domain.RevisionUpdate)))
+	assert.Nil(revisions.Save(revision("obj1", 10, domain.RevisionDelete)))
+	assert.Nil(revisions.Save(revision("obj1", 1, domain.RevisionCreate)))
+	assert.Nil(revisions.Save(revision("obj10", 11, domain.RevisionCreate)))
+	other := s.ForOwner(ow).Projects().For("p1").Environments().For("prod").Revisions()
+	assert.Nil(other.Save(revision("obj1", 12, domain.RevisionCreate)))
+	err = revisions.Save(revision("obj1", 2, domain.RevisionUpdate))
+	assert.Equal(&storage.UniqueIndexError{Type: "ObjectRevision", Key: "object_code: obj1, revision: 2"}, err)
+
+	list, err = revisions.List("obj1")
+	assert.Nil(err)
+	numbers := make([]int64, 0)
+	for _, rev := range list {
+		numbers = append(numbers, rev.Revision)
+	}
+	assert.Equal([]int64{1, 2, 10}, numbers)
+
+	rev, err := revisions.Get("obj1", 2)
+	assert.Nil(err)
+	assert.Equal(domain.RevisionUpdate, rev.Action)
+	assert.Equal("key1", rev.Actor)
+	assert.Equal(domain.ObjectCode("obj1"), rev.Object.Code)
+	assert.Len(rev.Diff, 1)
+	assert.Equal("description", rev.Diff[0].Field)
+	assert.Equal("old", rev.Diff[0].Old)
+	assert.Equal("new", rev.Diff[0].New)
+
+	rev, err = revisions.Latest("obj1")
+	assert.Nil(err)
+	assert.Equal(int64(10), rev.Revision)
+	assert.Equal(domain.RevisionDelete, rev.Action)
+
+	list, err = s.ForOwner("another_owner").Projects().For("p1").Environments().For("dev").Revisions().List("obj1")
+	assert.Nil(err)
+	assert.Empty(list)
+}
+
+func testAudit(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	audit := s.ForOwner(ow).Audit()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	record := func(id, entity, actor string, minutes int) *domain.AuditRecord {
+		return &domain.AuditRecord{
+			ID:         id,
+			Owner:      ow,
+			Actor:      actor,
+			RequestID:  "req-" + id,
+			Entity:     entity,
+			EntityType: domain.AuditObject,
+			Action:     domain.AuditUpdate,
+			Date:       start.Add(time.Duration(minutes) * time.Minute),
+			Before:     map[string]interface{}{"description": "old"},
+			After:      map[string]interface{}{"description": "new"},
+		}
+	}
+	ids := func(list []*domain.AuditRecord) []string {
+		res := make([]string, 0, len(list))
+		for _, rec := range list {
+			res = append(res, rec.ID)
+		}
+		return res
+	}
+
+	list, err := audit.List(&domain.AuditFilter{})
+	assert.Nil(err)
+	assert.Empty(list)
+
+	assert.Nil(audit.Save(record("a1", "/project/p1", "key1", 0)))
+	assert.Nil(audit.Save(record("a2", "/project/p1/env/dev/object/o1", "key2", 1)))
+	assert.Nil(audit.Save(record("a3", "/project/p10", "key1", 2)))
+	assert.Nil(audit.Save(record("a4", "/project/p1/segment/s1", "key1", 3)))
+	assert.Nil(s.ForOwner("another_owner").Audit().Save(record("a5", "/project/p1", "key1", 4)))
+	err = audit.Save(record("a1", "/project/p1", "key1", 5))
+	assert.Equal(&storage.UniqueIndexError{Type: "AuditRecord", Key: "id: a1"}, err)
+
+	list, err = audit.List(&domain.AuditFilter{})
+	assert.Nil(err)
+	assert.Equal([]string{"a1", "a2", "a3", "a4"}, ids(list))
+	assert.Equal("req-a2", list[1].RequestID)
+	assert.Equal(domain.AuditObject, list[1].EntityType)
+	assert.True(start.Add(time.Minute).Equal(list[1].Date))
+	assert.NotNil(list[1].After)
+
+	list, err = audit.List(&domain.AuditFilter{Entity: "/project/p1"})
+	assert.Nil(err)
+	assert.Equal([]string{"a1", "a2", "a4"}, ids(list))
+
+	list, err = audit.List(&domain.AuditFilter{Entity: "/project/p1/env/dev/object/o1", Actor: "key2"})
+	assert.Nil(err)
+	assert.Equal([]string{"a2"}, ids(list))
+
+	list, err = audit.List(&domain.AuditFilter{Actor: "key1", From: start.Add(time.Minute), To: start.Add(2 * time.Minute)})
+	assert.Nil(err)
+	assert.Equal([]string{"a3"}, ids(list))
+}
+
+func testWebhooks(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	hooks := s.ForOwner(ow).Projects().For("p1").Webhooks()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	hook := func(id domain.WebhookID, url string) *domain.Webhook {
+		return &domain.Webhook{
+			ID:          id,
+			Owner:       ow,
+			ProjectCode: "p1",
+			URL:         url,
+			Secret:      "secret",
+			Events:      []domain.EventType{domain.EventObjectUpdate},
+			EnvCodes:    []domain.EnvironmentCode{"dev"},
+			RegDate:     start,
+		}
+	}
+	delivery := func(id string, hookID domain.WebhookID, attempt int) *domain.WebhookDelivery {
+		return &domain.WebhookDelivery{
+			ID:          id,
+			Owner:       ow,
+			ProjectCode: "p1",
+			WebhookID:   hookID,
+			EventID:     "e1",
+			EventType:   domain.EventObjectUpdate,
+			Attempt:     attempt,
+			Date:        start.Add(time.Duration(attempt) * time.Minute),
+			StatusCode:  500,
+		}
+	}
+	deliveryIDs := func(id domain.WebhookID) []string {
+		list, err := hooks.ListDeliveries(id)
+		assert.Nil(err)
+		res := make([]string, 0, len(list))
+		for _, d := range list {
+			res = append(res, d.ID)
+		}
+		return res
+	}
+
+	list, err := hooks.List()
+	assert.Nil(err)
+	assert.Empty(list)
+	_, err = hooks.Get("h1")
+	assert.Equal(storage.ErrNotFound, err)
+	assert.Equal(storage.ErrNotFound, hooks.Update(hook("h1", "http://a")))
+	assert.Equal(storage.ErrNotFound, hooks.Delete("h1"))
+
+	assert.Nil(hooks.Save(hook("h1", "http://a")))
+	assert.Nil(hooks.Save(hook("h10", "http://b")))
+	err = hooks.Save(hook("h1", "http://c"))
+	assert.Equal(&storage.UniqueIndexError{Type: "Webhook", Key: "project_code: p1, id: h1"}, err)
+
+	h, err := hooks.Get("h1")
+	assert.Nil(err)
+	assert.Equal("http://a", h.URL)
+	assert.Equal("secret", h.Secret)
+	assert.Equal([]domain.EventType{domain.EventObjectUpdate}, h.Events)
+	assert.Equal([]domain.EnvironmentCode{"dev"}, h.EnvCodes)
+	assert.True(start.Equal(h.RegDate))
+
+	updated := hook("h1", "http://d")
+	updated.EnvCodes = nil
+	assert.Nil(hooks.Update(updated))
+	h, err = hooks.Get("h1")
+	assert.Nil(err)
+	assert.Equal("http://d", h.URL)
+	assert.Empty(h.EnvCodes)
+
+	other, err := s.ForOwner(ow).Projects().For("p2").Webhooks().List()
+	assert.Nil(err)
+	assert.Empty(other)
+
+	assert.Empty(deliveryIDs("h1"))
+	assert.Nil(hooks.SaveDelivery(delivery("d1", "h1", 1)))
+	assert.Nil(hooks.SaveDelivery(delivery("d2", "h10", 1)))
+	assert.Nil(hooks.SaveDelivery(delivery("d3", "h1", 2)))
+	assert.IsType(&storage.UniqueIndexError{}, hooks.SaveDelivery(delivery("d1", "h1", 3)))
+	assert.Equal([]string{"d1", "d3"}, deliveryIDs("h1"))
+	assert.Equal([]string{"d2"}, deliveryIDs("h10"))
+
+	d, err := hooks.ListDeliveries("h1")
+	assert.Nil(err)
+	assert.Equal(2, d[1].Attempt)
+	assert.Equal(500, d[1].StatusCode)
+	assert.Equal("e1", d[1].EventID)
+
+	assert.Nil(hooks.Delete("h1"))
+	list, err = hooks.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+	assert.Equal(domain.WebhookID("h10"), list[0].ID)
+	assert.Empty(deliveryIDs("h1"))
+	assert.Equal([]string{"d2"}, deliveryIDs("h10"))
+}
+
+func testOwnerIsolation(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	owners := []string{"owner1", "owner2"}
+
+	for _, owner := range owners {
+		assert.Nil(s.ForOwner(owner).Projects().Save(&domain.Project{OwnerID: owner, Code: "p1", Description: owner}))
+		assert.Nil(s.ForOwner(owner).Projects().For("p1").Segments().Save(&domain.Segment{OwnerID: owner, ProjectCode: "p1", Code: "s1", Description: owner}))
+		assert.Nil(s.ForOwner(owner).Keys().Save(&domain.APIKey{ID: "k1", OwnerID: owner, Hash: "hash-" + owner}))
+	}
+
+	for _, owner := range owners {
+		projects, err := s.ForOwner(owner).Projects().List()
+		assert.Nil(err)
+		assert.Len(projects, 1)
+		assert.Equal(owner, projects[0].Description)
+
+		segments, err := s.ForOwner(owner).Projects().For("p1").Segments().List()
+		assert.Nil(err)
+		assert.Len(segments, 1)
+		assert.Equal(owner, segments[0].Description)
+
+		keys, err := s.ForOwner(owner).Keys().List()
+		assert.Nil(err)
+		assert.Len(keys, 1)
+		assert.Equal(owner, keys[0].OwnerID)
+	}
+
+	first, second := owners[0], owners[1]
+	assert.Nil(s.ForOwner(first).Projects().Update(&domain.Project{OwnerID: first, Code: "p1", Description: "updated"}))
+	assert.Nil(s.ForOwner(first).Projects().For("p1").Segments().Delete("s1"))
+	assert.Nil(s.ForOwner(first).Keys().Delete("k1"))
+
+	p, err := s.ForOwner(second).Projects().Get("p1")
+	assert.Nil(err)
+	assert.Equal(second, p.Description)
+	_, err = s.ForOwner(second).Projects().For("p1").Segments().Get("s1")
+	assert.Nil(err)
+	_, err = s.ForOwner(second).Keys().Get("k1")
+	assert.Nil(err)
+
+	projects, err := s.ForOwner("unknown").Projects().List()
+	assert.Nil(err)
+	assert.Empty(projects)
+}
+
+type tenant struct {
+	owner   string
+	project domain.ProjectCode
+}
+
+const sharedEnv = domain.EnvironmentCode("prod")
+const sharedObj = domain.ObjectCode("obj1")
+
+func (t tenant) envs(s storage.DataStorage) storage.EnvironmentStorage {
+	return s.ForOwner(t.owner).Projects().For(t.project).Environments()
+}
+
+func (t tenant) objects(s storage.DataStorage) storage.ObjectStorage {
+	return t.envs(s).For(sharedEnv).Objects()
+}
+
+func (t tenant) description() string {
+	return t.owner + "/" + string(t.project)
+}
+
+func testTenantIsolation(t *testing.T, s storage.DataStorage) {
+	assert := asserts.New(t)
+	tenants := []tenant{
+		{owner: "owner1", project: "p1"},
+		{owner: "owner1", project: "p2"},
+		{owner: "owner2", project: "p1"},
+	}
+
+	for _, tn := range tenants {
+		assert.Nil(tn.envs(s).Save(&domain.Environment{
+			OwnerID:     tn.owner,
+			ProjectCode: tn.project,
+			Code:        sharedEnv,
+			Description: tn.description(),
+		}))
+		obj := newObject(tn.owner, tn.project, sharedEnv, sharedObj, nil)
+		obj.Description = tn.description()
+		assert.Nil(tn.objects(s).Save(obj))
+		assert.Nil(tn.objects(s).Save(newObject(tn.owner, tn.project, sharedEnv, "child",
+			&domain.ObjectInheritance{ProjectCode: tn.project, EnvCode: sharedEnv, ObjectCode: sharedObj})))
+	}
+
+	for _, tn := range tenants {
+		envs, err := tn.envs(s).List()
+		assert.Nil(err)
+		assert.Len(envs, 1)
+		assert.Equal(tn.description(), envs[0].Description)
+
+		env, err := tn.envs(s).Get(sharedEnv)
+		assert.Nil(err)
+		assert.Equal(tn.description(), env.Description)
+
+		objects, err := tn.objects(s).List()
+		assert.Nil(err)
+		assert.Len(objects, 2)
+
+		obj, err := tn.objects(s).Get(sharedObj)
+		assert.Nil(err)
+		assert.Equal(tn.description(), obj.Description)
+
+		inheritors, err := tn.objects(s).ListInheritors(sharedObj)
+		assert.Nil(err)
+		assert.Len(inheritors, 1)
+		assert.Equal(tn.owner, inheritors[0].Owner)
+		assert.Equal(tn.project, inheritors[0].ProjectCode)
+	}
+
+	first, rest := tenants[0], tenants[1:]
+
+	assert.Nil(first.envs(s).Update(&domain.Environment{
+		OwnerID:     first.owner,
+		ProjectCode: first.project,
+		Code:        sharedEnv,
+		Description: "updated",
+		Protected:   true,
+	}))
+	updated := newObject(first.owner, first.project, sharedEnv, sharedObj, nil)
+	updated.Description = "updated"
+	assert.Nil(first.objects(s).Update(updated))
+	for _, tn := range rest {
+		env, err := tn.envs(s).Get(sharedEnv)
+		assert.Nil(err)
+		assert.Equal(tn.description(), env.Description)
+		assert.False(env.Protected)
+		obj, err := tn.objects(s).Get(sharedObj)
+		assert.Nil(err)
+		assert.Equal(tn.description(), obj.Description)
+	}
+
+	assert.Nil(first.objects(s).Delete("child", 0))
+	assert.Nil(first.objects(s).Delete(sharedObj, 1))
+	assert.Nil(first.envs(s).Delete(sharedEnv))
+	assert.Equal(storage.ErrNotFound, first.objects(s).Delete(sharedObj, 1))
+	assert.Equal(storage.ErrNotFound, first.envs(s).Delete(sharedEnv))
+	for _, tn := range rest {
+		_, err := tn.envs(s).Get(sharedEnv)
+		assert.Nil(err)
+		objects, err := tn.objects(s).List()
+		assert.Nil(err)
+		assert.Len(objects, 2)
+	}
+
+	err := rest[0].objects(s).Save(newObject(rest[0].owner, rest[0].project, sharedEnv, sharedObj, nil))
+	assert.IsType(&storage.UniqueIndexError{}, err)
+}
+
+func newObject(owner string, project domain.ProjectCode, env domain.EnvironmentCode, code domain.ObjectCode, inherits *domain.ObjectInheritance) *domain.Object {
+	return &domain.Object{
+		Owner:       owner,
+		ProjectCode: project,
+		EnvCode:     env,
+		Code:        code,
+		Inherits:    inherits,
+	}
+}
+
+func projectCodes(list []*domain.Project) []domain.ProjectCode {
+	codes := make([]domain.ProjectCode, 0, len(list))
+	for _, p := range list {
+		codes = append(codes, p.Code)
+	}
+	return codes
+}
+
+func objectCodes(list []*domain.Object) []domain.ObjectCode {
+	codes := make([]domain.ObjectCode, 0, len(list))
+	for _, o := range list {
+		codes = append(codes, o.Code)
+	}
+	return codes
+}
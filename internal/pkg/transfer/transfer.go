@@ -0,0 +1,327 @@
+// Package transfer exports project configuration to a document and imports it back through the project API
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// Export returns document of the project. Inheritors keep own parameters only.
+func Export(projects api.ProjectAPI, code domain.ProjectCode) (*domain.Document, error) {
+	proj, err := projects.Get(code)
+	if err != nil {
+		return nil, err
+	}
+	doc := &domain.Document{
+		Version:      domain.DocumentVersion,
+		Project:      &domain.DocumentProject{Code: proj.Code, Description: proj.Description, Status: proj.Status},
+		Segments:     make([]*domain.DocumentSegment, 0),
+		Environments: make([]*domain.DocumentEnvironment, 0),
+	}
+	segments, err := projects.For(code).Segments().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range segments {
+		doc.Segments = append(doc.Segments, &domain.DocumentSegment{
+			Code:        s.Code,
+			Description: s.Description,
+			Include:     s.Include,
+			Exclude:     s.Exclude,
+			Rules:       s.Rules,
+		})
+	}
+	envs, err := projects.For(code).Environments().List()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range envs {
+		env := &domain.DocumentEnvironment{
+			Code:        e.Code,
+			Description: e.Description,
+			Protected:   e.Protected,
+			Objects:     make([]*domain.DocumentObject, 0),
+		}
+		objects := projects.For(code).Environments().For(e.Code).Objects()
+		list, err := objects.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range list {
+			params, err := ownParameters(projects, objects, obj)
+			if err != nil {
+				return nil, err
+			}
+			env.Objects = append(env.Objects, &domain.DocumentObject{
+				Code:        obj.Code,
+				Description: obj.Description,
+				Inherits:    obj.Inherits,
+				Parameters:  params,
+			})
+		}
+		doc.Environments = append(doc.Environments, env)
+	}
+	return doc, nil
+}
+
+// ownParameters returns parameters stored by the object. API returns inherited parameters as well,
+// so they are taken from the last revision, or compared with the parent for objects without history.
+func ownParameters(projects api.ProjectAPI, objects api.ObjectAPI, obj *domain.Object) ([]*domain.Parameter, error) {
+	if obj.Inherits == nil {
+		return obj.Parameters, nil
+	}
+	history, err := objects.History(obj.Code)
+	if err != nil {
+		return nil, err
+	}
+	var last *domain.ObjectRevision
+	for _, rev := range history {
+		if last == nil || rev.Revision > last.Revision {
+			last = rev
+		}
+	}
+	if last != nil && last.Action != domain.RevisionDelete && last.Object != nil {
+		return last.Object.Parameters, nil
+	}
+	parent, err := projects.For(obj.Inherits.ProjectCode).Environments().For(obj.Inherits.EnvCode).Objects().Get(obj.Inherits.ObjectCode)
+	if err != nil {
+		return nil, err
+	}
+	inherited := make(map[domain.ParameterCode]*domain.Parameter, len(parent.Parameters))
+	for _, p := range parent.Parameters {
+		inherited[p.Code] = p
+	}
+	params := make([]*domain.Parameter, 0)
+	for _, p := range obj.Parameters {
+		if !equal(p, inherited[p.Code]) {
+			params = append(params, p)
+		}
+	}
+	return params, nil
+}
+
+// equal compares values the way they are exported
+func equal(a, b interface{}) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(ja) == string(jb)
+}
+
+type objectRef struct {
+	env    *domain.DocumentEnvironment
+	object *domain.DocumentObject
+}
+
+// sortObjects returns objects of all environments with parents before inheritors
+func sortObjects(doc *domain.Document) ([]*objectRef, error) {
+	refs := make(map[domain.ObjectInheritance]*objectRef)
+	keys := make([]domain.ObjectInheritance, 0)
+	for _, env := range doc.Environments {
+		for _, obj := range env.Objects {
+			key := domain.ObjectInheritance{ProjectCode: doc.Project.Code, EnvCode: env.Code, ObjectCode: obj.Code}
+			if _, ok := refs[key]; ok {
+				return nil, api.NewBadRequestError(fmt.Sprintf("Object `%s` of environment `%s` is duplicated", obj.Code, env.Code))
+			}
+			refs[key] = &objectRef{env: env, object: obj}
+			keys = append(keys, key)
+		}
+	}
+	sorted := make([]*objectRef, 0, len(keys))
+	// visiting objects are being sorted, visited ones are added already
+	visiting := make(map[domain.ObjectInheritance]bool)
+	visited := make(map[domain.ObjectInheritance]bool)
+	var visit func(key domain.ObjectInheritance) error
+	visit = func(key domain.ObjectInheritance) error {
+		ref, ok := refs[key]
+		if !ok || visited[key] {
+			return nil
+		}
+		if visiting[key] {
+			return api.NewBadRequestError(fmt.Sprintf("Inheritance cycle of object `%s` in environment `%s`", key.ObjectCode, key.EnvCode))
+		}
+		visiting[key] = true
+		if ref.object.Inherits != nil {
+			if err := visit(*ref.object.Inherits); err != nil {
+				return err
+			}
+		}
+		visited[key] = true
+		sorted = append(sorted, ref)
+		return nil
+	}
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// Import creates or updates entities of the document: project, segments, environments and objects.
+// Parents are saved before inheritors, objects of protected environments are changed with override only.
+// Project is kept active while its entities are imported, status of the document is applied last.
+// Entities missing in the document are kept. Changes made before an error are returned with it.
+func Import(projects api.ProjectAPI, doc *domain.Document, override bool) ([]*domain.ImportChange, error) {
+	if doc.Version != domain.DocumentVersion {
+		return nil, api.NewBadRequestError(fmt.Sprintf("Unsupported document version %d", doc.Version))
+	}
+	if doc.Project == nil {
+		return nil, api.NewBadRequestError("Project is not defined")
+	}
+	if doc.Project.Status != domain.ProjectStatusActive && doc.Project.Status != domain.ProjectStatusDisabled {
+		msg := fmt.Sprintf("Project status can be `%s` or `%s`", domain.ProjectStatusActive, domain.ProjectStatusDisabled)
+		return nil, api.NewBadRequestError(msg)
+	}
+	objects, err := sortObjects(doc)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]*domain.ImportChange, 0)
+	add := func(entity domain.AuditEntity, env domain.EnvironmentCode, code string, action domain.ImportAction) {
+		changes = append(changes, &domain.ImportChange{Entity: entity, EnvCode: env, Code: code, Action: action})
+	}
+
+	p := doc.Project
+	action, err := importProject(projects, p)
+	if err != nil {
+		return changes, err
+	}
+	add(domain.AuditProject, "", string(p.Code), action)
+
+	forProject := projects.For(p.Code)
+	importContent := func() error {
+		for _, s := range doc.Segments {
+			if action, err = importSegment(forProject.Segments(), s); err != nil {
+				return err
+			}
+			add(domain.AuditSegment, "", string(s.Code), action)
+		}
+		for _, e := range doc.Environments {
+			if action, err = importEnvironment(forProject.Environments(), e); err != nil {
+				return err
+			}
+			add(domain.AuditEnvironment, "", string(e.Code), action)
+		}
+		for _, ref := range objects {
+			forObject := forProject.Environments().For(ref.env.Code)
+			if override {
+				forObject = forObject.OverrideProtection()
+			}
+			objectAPI := forObject.Objects()
+			if action, err = importObject(projects, objectAPI, ref.object); err != nil {
+				return err
+			}
+			add(domain.AuditObject, ref.env.Code, string(ref.object.Code), action)
+		}
+		return nil
+	}
+	err = importContent()
+	if statusErr := importProjectStatus(projects, p); err == nil {
+		err = statusErr
+	}
+	return changes, err
+}
+
+// importProject creates or updates the project as active, so its entities can be imported.
+// Returned action compares the stored project with the document.
+func importProject(projects api.ProjectAPI, p *domain.DocumentProject) (domain.ImportAction, error) {
+	info := &api.ProjectInfo{Code: p.Code, Description: p.Description, Status: domain.ProjectStatusActive}
+	current, err := projects.Get(p.Code)
+	switch {
+	case err == api.ErrProjectNotFound:
+		_, err = projects.Create(info)
+		return domain.ImportCreate, err
+	case err != nil:
+		return "", err
+	}
+	action := domain.ImportUpdate
+	if current.Description == p.Description && current.Status == p.Status {
+		action = domain.ImportUnchanged
+	}
+	if current.Description == p.Description && current.Status == domain.ProjectStatusActive {
+		return action, nil
+	}
+	_, err = projects.Update(info)
+	return action, err
+}
+
+// importProjectStatus disables the project after its entities are imported
+func importProjectStatus(projects api.ProjectAPI, p *domain.DocumentProject) error {
+	if p.Status == domain.ProjectStatusActive {
+		return nil
+	}
+	_, err := projects.Update(&api.ProjectInfo{Code: p.Code, Description: p.Description, Status: p.Status})
+	return err
+}
+
+func importSegment(segments api.SegmentAPI, s *domain.DocumentSegment) (domain.ImportAction, error) {
+	info := &api.SegmentInfo{Code: s.Code, Description: s.Description, Include: s.Include, Exclude: s.Exclude, Rules: s.Rules}
+	current, err := segments.Get(s.Code)
+	switch {
+	case err == api.ErrSegmentNotFound:
+		_, err = segments.Create(info)
+		return domain.ImportCreate, err
+	case err != nil:
+		return "", err
+	case equal(s, &domain.DocumentSegment{
+		Code:        current.Code,
+		Description: current.Description,
+		Include:     current.Include,
+		Exclude:     current.Exclude,
+		Rules:       current.Rules,
+	}):
+		return domain.ImportUnchanged, nil
+	}
+	_, err = segments.Update(info)
+	return domain.ImportUpdate, err
+}
+
+func importEnvironment(envs api.EnvironmentAPI, e *domain.DocumentEnvironment) (domain.ImportAction, error) {
+	info := &api.EnvironmentInfo{Code: e.Code, Description: e.Description, Protected: e.Protected}
+	current, err := envs.Get(e.Code)
+	switch {
+	case err == api.ErrEnvironmentNotFound:
+		_, err = envs.Create(info)
+		return domain.ImportCreate, err
+	case err != nil:
+		return "", err
+	case current.Description == e.Description && current.Protected == e.Protected:
+		return domain.ImportUnchanged, nil
+	}
+	_, err = envs.Update(info)
+	return domain.ImportUpdate, err
+}
+
+func importObject(projects api.ProjectAPI, objects api.ObjectAPI, o *domain.DocumentObject) (domain.ImportAction, error) {
+	params := o.Parameters
+	if params == nil {
+		params = make([]*domain.Parameter, 0)
+	}
+	info := &api.ObjectInfo{Code: o.Code, Description: o.Description, Inherits: o.Inherits, Parameters: params}
+	current, err := objects.Get(o.Code)
+	if err == api.ErrObjectNotFound {
+		_, err = objects.Create(info)
+		return domain.ImportCreate, err
+	}
+	if err != nil {
+		return "", err
+	}
+	own, err := ownParameters(projects, objects, current)
+	if err != nil {
+		return "", err
+	}
+	if current.Description == o.Description && equal(current.Inherits, o.Inherits) && equal(own, params) {
+		return domain.ImportUnchanged, nil
+	}
+	_, err = objects.Update(info)
+	return domain.ImportUpdate, err
+}
@@ -0,0 +1,226 @@
+package transfer_test
+
+import (
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const pCode = domain.ProjectCode("p1")
+
+func newAPI() api.ProjectAPI {
+	var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+	return (&engine.Engine{Storage: &dataStorage}).ForOwner("test_owner").Projects()
+}
+
+func fill(assert *asserts.Assertions, projects api.ProjectAPI) {
+	_, err := projects.Create(&api.ProjectInfo{Code: pCode, Description: "Project", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	_, err = projects.For(pCode).Segments().Create(&api.SegmentInfo{Code: "beta", Include: []string{"u1"}})
+	assert.Nil(err)
+	envs := projects.For(pCode).Environments()
+	_, err = envs.Create(&api.EnvironmentInfo{Code: "dev"})
+	assert.Nil(err)
+	_, err = envs.Create(&api.EnvironmentInfo{Code: "prod", Protected: true})
+	assert.Nil(err)
+	_, err = envs.For("dev").Objects().Create(&api.ObjectInfo{
+		Code: "base",
+		Parameters: []*domain.Parameter{
+			{Code: "a", Type: domain.ParameterBool, Value: true},
+			{Code: "b", Type: domain.ParameterString, Value: "x"},
+		},
+	})
+	assert.Nil(err)
+	_, err = envs.For("prod").OverrideProtection().Objects().Create(&api.ObjectInfo{
+		Code:     "child",
+		Inherits: &domain.ObjectInheritance{ProjectCode: pCode, EnvCode: "dev", ObjectCode: "base"},
+		Parameters: []*domain.Parameter{
+			{Code: "b", Type: domain.ParameterString, Value: "y"},
+		},
+	})
+	assert.Nil(err)
+}
+
+func TestExport(t *testing.T) {
+	assert := asserts.New(t)
+	projects := newAPI()
+	fill(assert, projects)
+
+	doc, err := transfer.Export(projects, pCode)
+	assert.Nil(err)
+	assert.Equal(domain.DocumentVersion, doc.Version)
+	assert.Equal(&domain.DocumentProject{Code: pCode, Description: "Project", Status: domain.ProjectStatusActive}, doc.Project)
+	assert.Len(doc.Segments, 1)
+	assert.Equal([]string{"u1"}, doc.Segments[0].Include)
+	assert.Len(doc.Environments, 2)
+
+	objects := make(map[domain.ObjectCode]*domain.DocumentObject)
+	for _, e := range doc.Environments {
+		for _, o := range e.Objects {
+			objects[o.Code] = o
+		}
+	}
+	assert.Len(objects["base"].Parameters, 2)
+	assert.Nil(objects["base"].Inherits)
+	assert.Len(objects["child"].Parameters, 1)
+	assert.Equal("y", objects["child"].Parameters[0].Value)
+	assert.Equal(domain.ObjectCode("base"), objects["child"].Inherits.ObjectCode)
+
+	_, err = transfer.Export(projects, "unknown")
+	assert.Equal(api.ErrProjectNotFound, err)
+}
+
+func TestImport(t *testing.T) {
+	assert := asserts.New(t)
+	source := newAPI()
+	fill(assert, source)
+	doc, err := transfer.Export(source, pCode)
+	assert.Nil(err)
+
+	// inheritors go first to check ordering
+	doc.Environments[0], doc.Environments[1] = doc.Environments[1], doc.Environments[0]
+
+	target := newAPI()
+	changes, err := transfer.Import(target, doc, true)
+	assert.Nil(err)
+	assert.Len(changes, 6)
+	for _, c := range changes {
+		assert.Equal(domain.ImportCreate, c.Action)
+	}
+	assert.Equal(&domain.ImportChange{Entity: domain.AuditObject, EnvCode: "dev", Code: "base", Action: domain.ImportCreate}, changes[4])
+	assert.Equal(&domain.ImportChange{Entity: domain.AuditObject, EnvCode: "prod", Code: "child", Action: domain.ImportCreate}, changes[5])
+
+	child, err := target.For(pCode).Environments().For("prod").Objects().Get("child")
+	assert.Nil(err)
+	assert.Len(child.Parameters, 2)
+
+	changes, err = transfer.Import(target, doc, true)
+	assert.Nil(err)
+	for _, c := range changes {
+		assert.Equal(domain.ImportUnchanged, c.Action, "%s %s", c.Entity, c.Code)
+	}
+
+	doc.Environments[0].Objects[0].Parameters[0].Value = "z"
+	changes, err = transfer.Import(target, doc, true)
+	assert.Nil(err)
+	assert.Equal(domain.ImportUpdate, changes[5].Action)
+	child, err = target.For(pCode).Environments().For("prod").Objects().Get("child")
+	assert.Nil(err)
+	for _, p := range child.Parameters {
+		if p.Code == "b" {
+			assert.Equal("z", p.Value)
+		}
+	}
+}
+
+func TestImportDisabledProject(t *testing.T) {
+	assert := asserts.New(t)
+	source := newAPI()
+	fill(assert, source)
+	_, err := source.Update(&api.ProjectInfo{Code: pCode, Description: "Project", Status: domain.ProjectStatusDisabled})
+	assert.Nil(err)
+	doc, err := transfer.Export(source, pCode)
+	assert.Nil(err)
+	assert.Equal(domain.ProjectStatusDisabled, doc.Project.Status)
+
+	target := newAPI()
+	changes, err := transfer.Import(target, doc, true)
+	assert.Nil(err)
+	assert.Len(changes, 6)
+	project, err := target.Get(pCode)
+	assert.Nil(err)
+	assert.Equal(domain.ProjectStatusDisabled, project.Status, "status is applied after entities")
+	_, err = target.For(pCode).Environments().For("prod").Objects().Get("child")
+	assert.Nil(err)
+
+	doc.Environments[0].Objects[0].Description = "updated"
+	changes, err = transfer.Import(target, doc, true)
+	assert.Nil(err)
+	assert.Equal(domain.ImportUnchanged, changes[0].Action)
+	project, err = target.Get(pCode)
+	assert.Nil(err)
+	assert.Equal(domain.ProjectStatusDisabled, project.Status)
+
+	doc.Project.Status = ""
+	_, err = transfer.Import(target, doc, true)
+	assert.IsType(&api.ErrBadRequest{}, err)
+}
+
+func TestImportErrors(t *testing.T) {
+	assert := asserts.New(t)
+	target := newAPI()
+
+	_, err := transfer.Import(target, &domain.Document{Version: 2, Project: &domain.DocumentProject{Code: pCode}}, true)
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	_, err = transfer.Import(target, &domain.Document{Version: domain.DocumentVersion}, true)
+	assert.IsType(&api.ErrBadRequest{}, err)
+
+	inherits := func(code domain.ObjectCode) *domain.ObjectInheritance {
+		return &domain.ObjectInheritance{ProjectCode: pCode, EnvCode: "dev", ObjectCode: code}
+	}
+	_, err = transfer.Import(target, &domain.Document{
+		Version: domain.DocumentVersion,
+		Project: &domain.DocumentProject{Code: pCode, Status: domain.ProjectStatusActive},
+		Environments: []*domain.DocumentEnvironment{{
+			Code: "dev",
+			Objects: []*domain.DocumentObject{
+				{Code: "o1", Inherits: inherits("o2")},
+				{Code: "o2", Inherits: inherits("o1")},
+			},
+		}},
+	}, true)
+	assert.IsType(&api.ErrBadRequest{}, err)
+	_, err = target.Get(pCode)
+	assert.Equal(api.ErrProjectNotFound, err)
+}
+
+func TestYAML(t *testing.T) {
+	assert := asserts.New(t)
+	obj := &domain.DocumentObject{
+		Code:        "obj1",
+		Description: "Flags: checkout",
+		Inherits:    &domain.ObjectInheritance{ProjectCode: "p0", EnvCode: "base", ObjectCode: "obj0"},
+		Parameters: []*domain.Parameter{
+			{Code: "enabled", Type: domain.ParameterBool, Value: true},
+			{Code: "limit", Type: domain.ParameterInt, Value: int64(20)},
+			{Code: "list", Type: domain.ParameterList, Value: []string{}},
+		},
+	}
+	data, err := transfer.MarshalYAML(obj)
+	assert.Nil(err)
+	assert.Equal(`code: obj1
+description: 'Flags: checkout'
+inherits:
+  project_code: p0
+  env_code: base
+  object_code: obj0
+parameters:
+- code: enabled
+  description: ""
+  type: bool
+  value: true
+- code: limit
+  description: ""
+  type: int
+  value: 20
+- code: list
+  description: ""
+  type: list
+  value: []
+`, string(data))
+
+	decoded := &domain.DocumentObject{}
+	assert.Nil(transfer.UnmarshalYAML(data, decoded))
+	assert.Equal(obj.Inherits, decoded.Inherits)
+	assert.Equal(obj.Parameters, decoded.Parameters)
+
+	assert.NotNil(transfer.UnmarshalYAML([]byte("code: [obj1"), decoded))
+}
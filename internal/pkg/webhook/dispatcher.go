@@ -0,0 +1,196 @@
+// Package webhook delivers change events to project webhooks
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Delivery request headers
+const (
+	HeaderEvent     = "X-Toggly-Event"
+	HeaderDelivery  = "X-Toggly-Delivery"
+	HeaderSignature = "X-Toggly-Signature"
+)
+
+const (
+	queueSize      = 1024
+	deliveryIDSize = 12
+	requestTimeout = 10 * time.Second
+)
+
+// Sign returns signature header value of the payload: hex HMAC-SHA256 with sha256= prefix
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher delivers events to matching webhooks asynchronously.
+// Failed delivery is retried up to attempts times, delay starts from backoff and doubles after every attempt.
+type Dispatcher struct {
+	storage  *storage.DataStorage
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+	queue    chan *domain.Event
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher returns started dispatcher
+func NewDispatcher(storage *storage.DataStorage, attempts int, backoff time.Duration) *Dispatcher {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := &Dispatcher{
+		storage:  storage,
+		client:   &http.Client{Timeout: requestTimeout},
+		attempts: attempts,
+		backoff:  backoff,
+		queue:    make(chan *domain.Event, queueSize),
+		done:     make(chan struct{}),
+	}
+	d.wg.Add(1)
+	go d.run()
+	return d
+}
+
+// Publish queues the event. The event is dropped if the queue is full or dispatcher is closed.
+func (d *Dispatcher) Publish(event *domain.Event) {
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("[ERROR] Webhook queue is full, event %s %s dropped", event.ID, event.Type)
+	}
+}
+
+// Close stops dispatching, cancels pending retries and waits for running deliveries
+func (d *Dispatcher) Close() {
+	d.once.Do(func() { close(d.done) })
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.done:
+			return
+		case event := <-d.queue:
+			d.dispatch(event)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(event *domain.Event) {
+	hooks, err := (*d.storage).ForOwner(event.Owner).Projects().For(event.ProjectCode).Webhooks().List()
+	if err != nil {
+		log.Printf("[ERROR] Can't list webhooks of project %s: %v", event.ProjectCode, err)
+		return
+	}
+	for _, hook := range hooks {
+		if !hook.Matches(event) {
+			continue
+		}
+		d.wg.Add(1)
+		go d.deliverWithRetries(hook, event)
+	}
+}
+
+func (d *Dispatcher) deliverWithRetries(hook *domain.Webhook, event *domain.Event) {
+	defer d.wg.Done()
+	delay := d.backoff
+	for attempt := 1; ; attempt++ {
+		if d.deliver(hook, event, attempt).Success || attempt == d.attempts {
+			return
+		}
+		select {
+		case <-d.done:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// Deliver sends the event to the webhook once and records the attempt
+func (d *Dispatcher) Deliver(hook *domain.Webhook, event *domain.Event) *domain.WebhookDelivery {
+	return d.deliver(hook, event, 1)
+}
+
+func (d *Dispatcher) deliver(hook *domain.Webhook, event *domain.Event, attempt int) *domain.WebhookDelivery {
+	delivery := &domain.WebhookDelivery{
+		ID:          deliveryID(),
+		Owner:       hook.Owner,
+		ProjectCode: hook.ProjectCode,
+		WebhookID:   hook.ID,
+		EventID:     event.ID,
+		EventType:   event.Type,
+		Attempt:     attempt,
+		Date:        bson.Now().In(time.UTC),
+	}
+	status, err := d.send(hook, event, delivery.ID)
+	delivery.StatusCode = status
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.Success = err == nil
+	webhooks := (*d.storage).ForOwner(hook.Owner).Projects().For(hook.ProjectCode).Webhooks()
+	if err := webhooks.SaveDelivery(delivery); err != nil {
+		log.Printf("[ERROR] Can't save delivery of webhook %s: %v", hook.ID, err)
+	}
+	return delivery
+}
+
+// send posts the event and returns response status. Non-2xx status is an error.
+func (d *Dispatcher) send(hook *domain.Webhook, event *domain.Event, id string) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(event.Type))
+	req.Header.Set(HeaderDelivery, id)
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("Unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func deliveryID() string {
+	b := make([]byte, deliveryIDSize)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("[ERROR] Can't generate delivery id: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
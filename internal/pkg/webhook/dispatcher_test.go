@@ -0,0 +1,204 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/internal/pkg/webhook"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+var (
+	_ engine.EventPublisher = (*webhook.Dispatcher)(nil)
+	_ engine.WebhookSender  = (*webhook.Dispatcher)(nil)
+)
+
+const ow = "test_owner"
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver records requests and responds with statuses in order, the last one is repeated
+type receiver struct {
+	sync.Mutex
+	statuses []int
+	requests []*request
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.Lock()
+	defer r.Unlock()
+	r.requests = append(r.requests, &request{header: req.Header, body: body})
+	status := r.statuses[0]
+	if len(r.statuses) > 1 {
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) wait(t *testing.T, count int) []*request {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.Lock()
+		n := len(r.requests)
+		r.Unlock()
+		if n >= count {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	r.Lock()
+	defer r.Unlock()
+	if len(r.requests) < count {
+		t.Fatalf("expected %d requests, received %d", count, len(r.requests))
+	}
+	return append([]*request{}, r.requests...)
+}
+
+func saveHook(t *testing.T, s storage.DataStorage, url string, events ...domain.EventType) *domain.Webhook {
+	hook := &domain.Webhook{ID: "h1", Owner: ow, ProjectCode: "p1", URL: url, Secret: "secret", Events: events}
+	if err := s.ForOwner(ow).Projects().For("p1").Webhooks().Save(hook); err != nil {
+		t.Fatal(err)
+	}
+	return hook
+}
+
+func event(id string, t domain.EventType) *domain.Event {
+	return &domain.Event{ID: id, Type: t, Owner: ow, ProjectCode: "p1", EnvCode: "dev", ObjectCode: "o1"}
+}
+
+func TestSign(t *testing.T) {
+	assert := asserts.New(t)
+	assert.Equal("sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", webhook.Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	assert := asserts.New(t)
+	rcv := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	s := memory.NewMemoryStorage()
+	saveHook(t, s, srv.URL, domain.EventObjectUpdate)
+
+	d := webhook.NewDispatcher(&s, 5, time.Millisecond)
+	d.Publish(event("e0", domain.EventObjectCreate))
+	d.Publish(event("e1", domain.EventObjectUpdate))
+	requests := rcv.wait(t, 3)
+	d.Close()
+	assert.Len(requests, 3, "successful delivery is not retried, filtered event is skipped")
+
+	for _, req := range requests {
+		assert.Equal(string(domain.EventObjectUpdate), req.header.Get(webhook.HeaderEvent))
+		assert.Equal("application/json", req.header.Get("Content-Type"))
+		assert.NotEmpty(req.header.Get(webhook.HeaderDelivery))
+		assert.Equal(webhook.Sign("secret", req.body), req.header.Get(webhook.HeaderSignature))
+		var e domain.Event
+		assert.Nil(json.Unmarshal(req.body, &e))
+		assert.Equal("e1", e.ID)
+		assert.Equal(domain.ObjectCode("o1"), e.ObjectCode)
+	}
+	assert.NotEqual(requests[0].header.Get(webhook.HeaderDelivery), requests[1].header.Get(webhook.HeaderDelivery))
+
+	deliveries, err := s.ForOwner(ow).Projects().For("p1").Webhooks().ListDeliveries("h1")
+	assert.Nil(err)
+	assert.Len(deliveries, 3)
+	for i, dl := range deliveries {
+		assert.Equal(i+1, dl.Attempt)
+		assert.Equal("e1", dl.EventID)
+		assert.Equal(domain.EventObjectUpdate, dl.EventType)
+	}
+	assert.Equal(http.StatusInternalServerError, deliveries[0].StatusCode)
+	assert.False(deliveries[0].Success)
+	assert.NotEmpty(deliveries[0].Error)
+	assert.Equal(http.StatusOK, deliveries[2].StatusCode)
+	assert.True(deliveries[2].Success)
+	assert.Empty(deliveries[2].Error)
+}
+
+func TestDispatcherAttemptsLimit(t *testing.T) {
+	assert := asserts.New(t)
+	rcv := &receiver{statuses: []int{http.StatusInternalServerError}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	s := memory.NewMemoryStorage()
+	saveHook(t, s, srv.URL)
+
+	d := webhook.NewDispatcher(&s, 2, time.Millisecond)
+	d.Publish(event("e1", domain.EventObjectDelete))
+	rcv.wait(t, 2)
+	time.Sleep(20 * time.Millisecond)
+	d.Close()
+
+	deliveries, err := s.ForOwner(ow).Projects().For("p1").Webhooks().ListDeliveries("h1")
+	assert.Nil(err)
+	assert.Len(deliveries, 2)
+}
+
+func TestDispatcherClose(t *testing.T) {
+	assert := asserts.New(t)
+	rcv := &receiver{statuses: []int{http.StatusInternalServerError}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	s := memory.NewMemoryStorage()
+	saveHook(t, s, srv.URL)
+
+	d := webhook.NewDispatcher(&s, 5, time.Hour)
+	d.Publish(event("e1", domain.EventObjectUpdate))
+	rcv.wait(t, 1)
+
+	closed := make(chan struct{})
+	go func() {
+		d.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending retry is not cancelled on close")
+	}
+	d.Publish(event("e2", domain.EventObjectUpdate))
+
+	deliveries, err := s.ForOwner(ow).Projects().For("p1").Webhooks().ListDeliveries("h1")
+	assert.Nil(err)
+	assert.Len(deliveries, 1)
+}
+
+func TestDeliver(t *testing.T) {
+	assert := asserts.New(t)
+	rcv := &receiver{statuses: []int{http.StatusNotFound}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	s := memory.NewMemoryStorage()
+	hook := saveHook(t, s, srv.URL, domain.EventObjectUpdate)
+
+	d := webhook.NewDispatcher(&s, 5, time.Millisecond)
+	defer d.Close()
+	delivery := d.Deliver(hook, event("ping", domain.EventPing))
+	assert.False(delivery.Success)
+	assert.Equal(http.StatusNotFound, delivery.StatusCode)
+	assert.Equal(1, delivery.Attempt)
+	assert.Equal(domain.EventPing, delivery.EventType)
+
+	deliveries, err := s.ForOwner(ow).Projects().For("p1").Webhooks().ListDeliveries("h1")
+	assert.Nil(err)
+	assert.Len(deliveries, 1)
+	assert.Equal(delivery.ID, deliveries[0].ID)
+
+	hook.URL = "http://127.0.0.1:1"
+	delivery = d.Deliver(hook, event("ping", domain.EventPing))
+	assert.False(delivery.Success)
+	assert.Zero(delivery.StatusCode)
+	assert.NotEmpty(delivery.Error)
+}
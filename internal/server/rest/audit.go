@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/go-chi/chi"
+)
+
+// AuditRestAPI servers owner audit log
+type AuditRestAPI struct {
+	API api.TogglyAPI
+}
+
+// Routes returns routes for audit log
+func (a *AuditRestAPI) Routes() chi.Router {
+	router := chi.NewRouter()
+	router.Group(func(g chi.Router) {
+		g.Get("/", a.list)
+	})
+	return router
+}
+
+func (a *AuditRestAPI) engine(r *http.Request) api.AuditAPI {
+	return ownerAPI(a.API, r).Audit()
+}
+
+// auditFilter reads filter from query, time range bounds are RFC 3339 dates
+func auditFilter(r *http.Request) (*domain.AuditFilter, error) {
+	q := r.URL.Query()
+	filter := &domain.AuditFilter{
+		Entity: q.Get("entity"),
+		Actor:  q.Get("actor"),
+	}
+	var err error
+	if from := q.Get("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, errors.New("Bad request: `from` is not RFC 3339 date")
+		}
+	}
+	if to := q.Get("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, errors.New("Bad request: `to` is not RFC 3339 date")
+		}
+	}
+	return filter, nil
+}
+
+func (a *AuditRestAPI) list(w http.ResponseWriter, r *http.Request) {
+	filter, err := auditFilter(r)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusBadRequest)
+		return
+	}
+	list, err := a.engine(r).List(filter)
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		}
+		switch err.(type) {
+		case *api.ErrBadRequest:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, list)
+}
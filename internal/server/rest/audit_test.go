@@ -0,0 +1,142 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/auditapi"
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestAudit(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	key, err := engine.NewTogglyAPI(&dataStorage).ForOwner(ow).Keys().Create(&api.KeyInfo{Role: domain.RoleEditor})
+	assert.Nil(err)
+
+	records := func(expected ...string) func(body []byte) {
+		return func(body []byte) {
+			var b []*domain.AuditRecord
+			assert.Nil(parseBodyTo(body, &b))
+			entities := make([]string, 0)
+			for _, rec := range b {
+				entities = append(entities, rec.Entity)
+			}
+			assert.Equal(append([]string{}, expected...), entities)
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:      "create project",
+			method:    http.MethodPost,
+			path:      "/api/v1/project",
+			body:      &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusActive},
+			status:    http.StatusOK,
+			requestId: "req-project",
+		},
+		{
+			name:   "create environment with api key",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env",
+			body:   &rest.EnvironmentCreateRequest{Code: "env1"},
+			status: http.StatusForbidden,
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = []string{key.Key}
+			},
+		},
+		{
+			name:   "create environment",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env",
+			body:   &rest.EnvironmentCreateRequest{Code: "env1"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "create object with api key",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body:   &rest.ObjectCreateRequest{Code: "obj1"},
+			status: http.StatusOK,
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = []string{key.Key}
+			},
+		},
+		{
+			name:   "audit log",
+			method: http.MethodGet,
+			path:   "/api/v1/audit",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.AuditRecord
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 3)
+				assert.Equal("/project/project1", b[0].Entity)
+				assert.Equal(domain.AuditProject, b[0].EntityType)
+				assert.Equal(domain.AuditCreate, b[0].Action)
+				assert.Equal(rest.MasterActor, b[0].Actor)
+				assert.Equal("req-project", b[0].RequestID)
+				assert.Equal(string(key.ID), b[2].Actor)
+			},
+		},
+		{
+			name:      "audit log filtered by entity",
+			method:    http.MethodGet,
+			path:      "/api/v1/audit?entity=/project/project1/env/env1",
+			status:    http.StatusOK,
+			validator: records("/project/project1/env/env1", "/project/project1/env/env1/object/obj1"),
+		},
+		{
+			name:      "audit log filtered by actor",
+			method:    http.MethodGet,
+			path:      "/api/v1/audit?actor=" + string(key.ID),
+			status:    http.StatusOK,
+			validator: records("/project/project1/env/env1/object/obj1"),
+		},
+		{
+			name:      "audit log filtered by time range",
+			method:    http.MethodGet,
+			path:      "/api/v1/audit?from=2000-01-01T00:00:00Z&to=2001-01-01T00:00:00Z",
+			status:    http.StatusOK,
+			validator: records(),
+		},
+		{
+			name:   "audit log with wrong date",
+			method: http.MethodGet,
+			path:   "/api/v1/audit?from=yesterday",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "audit log with reversed time range",
+			method: http.MethodGet,
+			path:   "/api/v1/audit?from=2001-01-01T00:00:00Z&to=2000-01-01T00:00:00Z",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "audit log with api key",
+			method: http.MethodGet,
+			path:   "/api/v1/audit",
+			status: http.StatusForbidden,
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = []string{key.Key}
+			},
+		},
+	}
+
+	router := GetRouter()
+	router.API = auditapi.NewAuditAPI(router.API, auditapi.NewStorageSink(&dataStorage))
+	rs := httptest.NewServer(router.Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
@@ -7,9 +7,9 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/go-chi/chi"
 )
 
@@ -41,18 +41,21 @@ func (a *EnvironmentRestAPI) Routes() chi.Router {
 		g.Put("/", a.updateEnvironment)
 		g.Get("/{env_code}", a.getEnvironment)
 		g.Delete("/{env_code}", a.deleteEnvironment)
+		g.Post("/{env_code}/evaluate", a.evaluateEnvironment)
 	})
 	return router
 }
 
 func (a *EnvironmentRestAPI) engine(r *http.Request) api.EnvironmentAPI {
-	return a.API.ForOwner(owner(r)).Projects().For(projectCode(r)).Environments()
+	return ownerAPI(a.API, r).Projects().For(projectCode(r)).Environments()
 }
 
 func (a *EnvironmentRestAPI) list(w http.ResponseWriter, r *http.Request) {
 	list, err := a.engine(r).List()
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, "Project not found")
 		default:
@@ -68,6 +71,8 @@ func (a *EnvironmentRestAPI) getEnvironment(w http.ResponseWriter, r *http.Reque
 	env, err := a.engine(r).Get(environmentCode(r))
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 		case api.ErrEnvironmentNotFound:
@@ -78,19 +83,42 @@ func (a *EnvironmentRestAPI) getEnvironment(w http.ResponseWriter, r *http.Reque
 		}
 		return
 	}
-	JSONResponse(w, r, env)
+	VersionedResponse(w, r, env.Version, env)
+}
+
+// ifMatch checks If-Match header against current environment version
+func (a *EnvironmentRestAPI) ifMatch(r *http.Request, code domain.EnvironmentCode) (int64, error) {
+	if !hasIfMatch(r) {
+		return 0, nil
+	}
+	env, err := a.engine(r).Get(code)
+	if err != nil {
+		return 0, err
+	}
+	return ifMatch(r, env.Version)
 }
 
 func (a *EnvironmentRestAPI) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
-	err := a.engine(r).Delete(environmentCode(r))
+	_, err := a.ifMatch(r, environmentCode(r))
+	if err == nil {
+		err = a.engine(r).Delete(environmentCode(r))
+	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
 		case api.ErrEnvironmentNotFound:
 			NotFoundResponse(w, r, ErrEnvironmentNotFound)
 		case api.ErrEnvironmentNotEmpty:
 			ErrorResponse(w, r, errors.New(ErrEnvironmentNotEmpty), http.StatusLocked)
+		case api.ErrEnvironmentProtected:
+			ErrorResponse(w, r, errors.New(ErrEnvironmentProtected), http.StatusLocked)
 		default:
 			log.Printf("[ERROR] %v", err)
 			ErrorResponse(w, r, err, http.StatusInternalServerError)
@@ -100,6 +128,42 @@ func (a *EnvironmentRestAPI) deleteEnvironment(w http.ResponseWriter, r *http.Re
 	JSONResponse(w, r, nil)
 }
 
+func (a *EnvironmentRestAPI) evaluateEnvironment(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	req := &EvaluateRequest{}
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, req); err != nil {
+			ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+			return
+		}
+	}
+	values, err := a.engine(r).For(environmentCode(r)).Objects().EvaluateAll(&domain.EvaluationContext{
+		UserID:     req.UserID,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+		case api.ErrEnvironmentNotFound:
+			NotFoundResponse(w, r, ErrEnvironmentNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, values)
+}
+
 func (a *EnvironmentRestAPI) createEnvironment(w http.ResponseWriter, r *http.Request) {
 	a.createUpdate(w, r, true)
 }
@@ -128,17 +192,29 @@ func (a *EnvironmentRestAPI) createUpdate(w http.ResponseWriter, r *http.Request
 			Protected:   env.Protected,
 		})
 	} else {
-		newEnv, err = a.engine(r).Update(&api.EnvironmentInfo{
+		info := &api.EnvironmentInfo{
 			Code:        env.Code,
 			Description: env.Description,
 			Protected:   env.Protected,
-		})
+		}
+		if info.Version, err = a.ifMatch(r, env.Code); err == nil {
+			newEnv, err = a.engine(r).Update(info)
+		}
 	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
+			return
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 			return
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+			return
 		case api.ErrEnvironmentNotFound:
 			NotFoundResponse(w, r, ErrEnvironmentNotFound)
 			return
@@ -154,5 +230,5 @@ func (a *EnvironmentRestAPI) createUpdate(w http.ResponseWriter, r *http.Request
 		}
 		return
 	}
-	JSONResponse(w, r, newEnv)
+	VersionedResponse(w, r, newEnv.Version, newEnv)
 }
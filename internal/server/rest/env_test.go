@@ -8,8 +8,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
 	asserts "github.com/stretchr/testify/assert"
 )
 
@@ -223,6 +223,29 @@ func TestRestEnvironment(t *testing.T) {
 				assert.Equal("Environment not found", b["error"])
 			},
 		},
+		{
+			name:   "Delete protected env",
+			method: http.MethodDelete,
+			path:   "/api/v1/project/project1/env/env1",
+			status: http.StatusLocked,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal(rest.ErrEnvironmentProtected, b["error"])
+			},
+		},
+		{
+			name:   "Unprotect env",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/env",
+			body: &rest.EnvironmentCreateRequest{
+				Code:        "env1",
+				Description: "Env description 1",
+				Protected:   false,
+			},
+			status: http.StatusOK,
+		},
 		{
 			name:   "delete not empty environment",
 			method: http.MethodDelete,
@@ -288,3 +311,105 @@ func TestRestEnvironment(t *testing.T) {
 	AfterTest()
 
 }
+
+func TestRestProtectedEnvironment(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	override := func(req *http.Request) {
+		req.Header.Set(rest.XTogglyOverride, "true")
+	}
+	protectedValidator := func(body []byte) {
+		var b map[string]interface{}
+		err := parseBodyTo(body, &b)
+		assert.Nil(err)
+		assert.Equal(rest.ErrEnvironmentProtected, b["error"])
+	}
+	obj := &rest.ObjectCreateRequest{
+		Code:       "obj1",
+		Parameters: []*domain.Parameter{{Code: "param1", Type: domain.ParameterBool, Value: true}},
+	}
+
+	tt := []TestCase{
+		{
+			name:      "Create object in protected env",
+			method:    http.MethodPost,
+			path:      "/api/v1/project/project1/env/env1/object",
+			body:      obj,
+			status:    http.StatusLocked,
+			validator: protectedValidator,
+		},
+		{
+			name:         "Create object in protected env with override",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         obj,
+			status:       http.StatusOK,
+			patchRequest: override,
+		},
+		{
+			name:      "Update object in protected env",
+			method:    http.MethodPut,
+			path:      "/api/v1/project/project1/env/env1/object",
+			body:      obj,
+			status:    http.StatusLocked,
+			validator: protectedValidator,
+		},
+		{
+			name:   "Evaluate object in protected env",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/evaluate",
+			status: http.StatusOK,
+		},
+		{
+			name:      "Delete object in protected env",
+			method:    http.MethodDelete,
+			path:      "/api/v1/project/project1/env/env1/object/obj1",
+			status:    http.StatusLocked,
+			validator: protectedValidator,
+		},
+		{
+			name:         "Delete object in protected env with override",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1/env/env1/object/obj1",
+			status:       http.StatusOK,
+			patchRequest: override,
+		},
+		{
+			name:         "Delete protected env with override",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1/env/env1",
+			status:       http.StatusLocked,
+			validator:    protectedValidator,
+			patchRequest: override,
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, body := range []struct {
+		path string
+		data interface{}
+	}{
+		{"/api/v1/project", &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusActive}},
+		{"/api/v1/project/project1/env", &rest.EnvironmentCreateRequest{Code: "env1", Protected: true}},
+	} {
+		data, err := json.Marshal(body.data)
+		assert.Nil(err)
+		req, err := http.NewRequest(http.MethodPost, rs.URL+body.path, bytes.NewBuffer(data))
+		assert.Nil(err)
+		req.Header = http.Header{
+			rest.XTogglyAuth:    []string{TestAuthToken},
+			rest.XTogglyOwnerID: []string{ow},
+		}
+		_, err = rs.Client().Do(req)
+		assert.Nil(err)
+	}
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
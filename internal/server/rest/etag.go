@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// etag returns entity tag of resource version
+func etag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// objectETag returns entity tag of the object. Inheritor tag has hash of the object with inherited parameters,
+// so it changes with parents as well.
+func objectETag(obj *domain.Object) string {
+	if obj.Inherits == nil {
+		return etag(obj.Version)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return etag(obj.Version)
+	}
+	return fmt.Sprintf(`"%d-%x"`, obj.Version, sha1.Sum(data))
+}
+
+// matchETag reports whether header is a wildcard or lists the entity tag.
+// Weak comparison ignores W/ prefix, strong one never matches weak tags.
+func matchETag(header string, tag string, weak bool) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		}
+		if t == "*" || t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIfMatch reports whether request is conditional on resource version
+func hasIfMatch(r *http.Request) bool {
+	return r.Header.Get("If-Match") != ""
+}
+
+// ifMatch checks If-Match header against current resource version
+// and returns the version the change is expected to be applied to
+func ifMatch(r *http.Request, version int64) (int64, error) {
+	if !matchETag(r.Header.Get("If-Match"), etag(version), false) {
+		return 0, api.ErrVersionConflict
+	}
+	return version, nil
+}
@@ -0,0 +1,326 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestVersions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	projects := engine.NewTogglyAPI(&dataStorage).ForOwner(ow).Projects()
+	_, err := projects.Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	envs := projects.For("project1").Environments()
+	_, err = envs.Create(&api.EnvironmentInfo{Code: "env1"})
+	assert.Nil(err)
+	_, err = envs.Create(&api.EnvironmentInfo{Code: "env2"})
+	assert.Nil(err)
+	_, err = envs.For("env1").Objects().Create(&api.ObjectInfo{Code: "obj1"})
+	assert.Nil(err)
+
+	header := func(name, value string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set(name, value)
+		}
+	}
+	etag := func(value string) func(h http.Header) {
+		return func(h http.Header) {
+			assert.Equal(value, h.Get("ETag"))
+		}
+	}
+	version := func(expected int64) func(body []byte) {
+		return func(body []byte) {
+			obj := &domain.Object{}
+			assert.Nil(parseBodyTo(body, obj))
+			assert.Equal(expected, obj.Version)
+		}
+	}
+	conflict := func(body []byte) {
+		var b map[string]interface{}
+		assert.Nil(parseBodyTo(body, &b))
+		assert.Equal(rest.ErrVersionConflict, b["error"])
+	}
+
+	tt := []TestCase{
+		{
+			name:      "get object returns etag",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project1/env/env1/object/obj1",
+			status:    http.StatusOK,
+			headers:   etag(`"1"`),
+			validator: version(1),
+		},
+		{
+			name:         "get object not modified",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1/env/env1/object/obj1",
+			status:       http.StatusNotModified,
+			patchRequest: header("If-None-Match", `W/"1"`),
+			headers:      etag(`"1"`),
+			validator: func(body []byte) {
+				assert.Empty(body)
+			},
+		},
+		{
+			name:         "update object with matching version",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj1", Description: "first"},
+			status:       http.StatusOK,
+			patchRequest: header("If-Match", `"1"`),
+			headers:      etag(`"2"`),
+			validator:    version(2),
+		},
+		{
+			name:         "update object with stale version",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj1", Description: "second"},
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match", `"1"`),
+			validator:    conflict,
+		},
+		{
+			name:         "update object with weak etag",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj1", Description: "second"},
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match", `W/"2"`),
+			validator:    conflict,
+		},
+		{
+			name:         "get object modified",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1/env/env1/object/obj1",
+			status:       http.StatusOK,
+			patchRequest: header("If-None-Match", `"1"`),
+			headers:      etag(`"2"`),
+			validator: func(body []byte) {
+				obj := &domain.Object{}
+				assert.Nil(parseBodyTo(body, obj))
+				assert.Equal("first", obj.Description)
+			},
+		},
+		{
+			name:         "update object with any version",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj1", Description: "second"},
+			status:       http.StatusOK,
+			patchRequest: header("If-Match", "*"),
+			validator:    version(3),
+		},
+		{
+			name:      "update object without precondition",
+			method:    http.MethodPut,
+			path:      "/api/v1/project/project1/env/env1/object",
+			body:      &rest.ObjectCreateRequest{Code: "obj1", Description: "third"},
+			status:    http.StatusOK,
+			validator: version(4),
+		},
+		{
+			name:         "update missing object with precondition",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj2"},
+			status:       http.StatusNotFound,
+			patchRequest: header("If-Match", "*"),
+		},
+		{
+			name:         "delete object with stale version",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1/env/env1/object/obj1",
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match", `"3", "2"`),
+			validator:    conflict,
+		},
+		{
+			name:         "delete object with matching version",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1/env/env1/object/obj1",
+			status:       http.StatusOK,
+			patchRequest: header("If-Match", `"3", "4"`),
+		},
+		{
+			name:         "update environment with stale version",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env",
+			body:         &rest.EnvironmentCreateRequest{Code: "env2", Description: "changed"},
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match", `"2"`),
+			validator:    conflict,
+		},
+		{
+			name:         "delete environment with matching version",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1/env/env2",
+			status:       http.StatusOK,
+			patchRequest: header("If-Match", `"1"`),
+		},
+		{
+			name:         "get project not modified",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1",
+			status:       http.StatusNotModified,
+			patchRequest: header("If-None-Match", "*"),
+			headers:      etag(`"1"`),
+		},
+		{
+			name:   "update project",
+			method: http.MethodPut,
+			path:   "/api/v1/project",
+			body: &rest.ProjectCreateRequest{
+				Code:   "project1",
+				Status: domain.ProjectStatusActive,
+			},
+			status:       http.StatusOK,
+			patchRequest: header("If-Match", `"1"`),
+			headers:      etag(`"2"`),
+		},
+		{
+			name:         "delete project with stale version",
+			method:       http.MethodDelete,
+			path:         "/api/v1/project/project1",
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match", `"1"`),
+			validator:    conflict,
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
+
+func TestRestInheritorVersions(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	projects := engine.NewTogglyAPI(&dataStorage).ForOwner(ow).Projects()
+	_, err := projects.Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	_, err = projects.For("project1").Environments().Create(&api.EnvironmentInfo{Code: "env1"})
+	assert.Nil(err)
+	objApi := projects.For("project1").Environments().For("env1").Objects()
+	param := func(value bool) []*domain.Parameter {
+		return []*domain.Parameter{{Code: "enabled", Type: domain.ParameterBool, Value: value}}
+	}
+	_, err = objApi.Create(&api.ObjectInfo{Code: "parent", Parameters: param(false)})
+	assert.Nil(err)
+	_, err = objApi.Create(&api.ObjectInfo{
+		Code:     "child",
+		Inherits: &domain.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "parent"},
+	})
+	assert.Nil(err)
+
+	var tag string
+	saveTag := func(h http.Header) {
+		assert.NotEqual(tag, h.Get("ETag"))
+		tag = h.Get("ETag")
+	}
+	header := func(name string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set(name, tag)
+		}
+	}
+	enabled := func(expected bool) func(body []byte) {
+		return func(body []byte) {
+			obj := &domain.Object{}
+			assert.Nil(parseBodyTo(body, obj))
+			assert.Equal(expected, obj.Parameters[0].Value)
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:      "get inheritor",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project1/env/env1/object/child",
+			status:    http.StatusOK,
+			headers:   saveTag,
+			validator: enabled(false),
+		},
+		{
+			name:         "get inheritor not modified",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1/env/env1/object/child",
+			status:       http.StatusNotModified,
+			patchRequest: header("If-None-Match"),
+		},
+		{
+			name:   "get inheritor modified by parent",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1/object/child",
+			before: func(rs *httptest.Server) {
+				_, err := objApi.Update(&api.ObjectInfo{Code: "parent", Parameters: param(true)})
+				assert.Nil(err)
+			},
+			status:       http.StatusOK,
+			patchRequest: header("If-None-Match"),
+			headers:      saveTag,
+			validator:    enabled(true),
+		},
+		{
+			name:   "update inheritor with matching etag",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body: &rest.ObjectCreateRequest{
+				Code:     "child",
+				Inherits: &domain.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "parent"},
+			},
+			status:       http.StatusOK,
+			patchRequest: header("If-Match"),
+			headers:      saveTag,
+		},
+		{
+			name:   "update inheritor with tag read before parent change",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/env/env1/object",
+			before: func(rs *httptest.Server) {
+				_, err := objApi.Update(&api.ObjectInfo{Code: "parent", Parameters: param(false)})
+				assert.Nil(err)
+			},
+			body: &rest.ObjectCreateRequest{
+				Code:     "child",
+				Inherits: &domain.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "parent"},
+			},
+			status:       http.StatusPreconditionFailed,
+			patchRequest: header("If-Match"),
+		},
+		{
+			name:   "update inheritor with version",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body: &rest.ObjectCreateRequest{
+				Code:     "child",
+				Inherits: &domain.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "parent"},
+			},
+			status:       http.StatusPreconditionFailed,
+			patchRequest: func(req *http.Request) { req.Header.Set("If-Match", `"2"`) },
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
@@ -0,0 +1,144 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestObjectHistory(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	owner := engine.NewTogglyAPI(&dataStorage).ForOwner(ow)
+	_, err := owner.Projects().Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	assert.Nil(err)
+	_, err = owner.Projects().For("project1").Environments().Create(&api.EnvironmentInfo{Code: "env1"})
+	assert.Nil(err)
+	key, err := owner.Keys().Create(&api.KeyInfo{Role: domain.RoleAdmin})
+	assert.Nil(err)
+	viewer, err := owner.Keys().Create(&api.KeyInfo{Role: domain.RoleReadOnly})
+	assert.Nil(err)
+
+	useKey := func(k *domain.APIKey) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header[rest.XTogglyAuth] = []string{k.Key}
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:   "history of missing object",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/history",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "create object",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body:   &rest.ObjectCreateRequest{Code: "obj1", Description: "first"},
+			status: http.StatusOK,
+		},
+		{
+			name:         "update object with api key",
+			method:       http.MethodPut,
+			path:         "/api/v1/project/project1/env/env1/object",
+			body:         &rest.ObjectCreateRequest{Code: "obj1", Description: "second"},
+			status:       http.StatusOK,
+			patchRequest: useKey(key),
+		},
+		{
+			name:   "history",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/history",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.ObjectRevision
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 2)
+				assert.Equal(domain.RevisionCreate, b[0].Action)
+				assert.Equal(rest.MasterActor, b[0].Actor)
+				assert.Equal(domain.RevisionUpdate, b[1].Action)
+				assert.Equal(string(key.ID), b[1].Actor)
+				assert.Equal("second", b[1].Object.Description)
+				assert.Len(b[1].Diff, 1)
+				assert.Equal("description", b[1].Diff[0].Field)
+			},
+		},
+		{
+			name:         "history with viewer key",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1/env/env1/object/obj1/history",
+			status:       http.StatusOK,
+			patchRequest: useKey(viewer),
+		},
+		{
+			name:         "revert with viewer key",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/env/env1/object/obj1/revert/1",
+			status:       http.StatusForbidden,
+			patchRequest: useKey(viewer),
+		},
+		{
+			name:   "revert to wrong revision",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/revert/first",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "revert to missing revision",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/revert/5",
+			status: http.StatusNotFound,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Equal(rest.ErrRevisionNotFound, b["error"])
+			},
+		},
+		{
+			name:   "revert",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/revert/1",
+			status: http.StatusOK,
+			headers: func(h http.Header) {
+				assert.Equal(`"3"`, h.Get("ETag"))
+			},
+			validator: func(body []byte) {
+				obj := &domain.Object{}
+				assert.Nil(parseBodyTo(body, obj))
+				assert.Equal("first", obj.Description)
+				assert.Equal(int64(3), obj.Version)
+			},
+		},
+		{
+			name:   "history after revert",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/history",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.ObjectRevision
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 3)
+				assert.Equal(domain.RevisionRevert, b[2].Action)
+				assert.Equal(int64(1), b[2].RevertedTo)
+			},
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
@@ -7,6 +7,13 @@ import (
 	"github.com/go-chi/render"
 )
 
+const (
+	// ErrForbidden error
+	ErrForbidden string = "Forbidden"
+	// ErrVersionConflict error
+	ErrVersionConflict string = "Version conflict"
+)
+
 // ErrorResponse creates {error: message} json body and responds with error code
 func ErrorResponse(w http.ResponseWriter, r *http.Request, err error, code int) {
 	render.Status(r, code)
@@ -28,3 +35,32 @@ func UnauthorizedResponse(w http.ResponseWriter, r *http.Request) {
 	render.Status(r, http.StatusUnauthorized)
 	render.PlainText(w, r, "")
 }
+
+// ForbiddenResponse creates {error: message} json body and responds with 403 code
+func ForbiddenResponse(w http.ResponseWriter, r *http.Request) {
+	ErrorResponse(w, r, errors.New(ErrForbidden), http.StatusForbidden)
+}
+
+// VersionedResponse sets ETag header and creates json body.
+// Responds with 304 code and empty body to GET request if If-None-Match header matches the version.
+func VersionedResponse(w http.ResponseWriter, r *http.Request, version int64, data interface{}) {
+	TaggedResponse(w, r, etag(version), data)
+}
+
+// TaggedResponse sets ETag header and creates json body.
+// Responds with 304 code and empty body to GET request if If-None-Match header matches the tag.
+func TaggedResponse(w http.ResponseWriter, r *http.Request, tag string, data interface{}) {
+	w.Header().Set("ETag", tag)
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if header := r.Header.Get("If-None-Match"); header != "" && matchETag(header, tag, true) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	JSONResponse(w, r, data)
+}
+
+// PreconditionFailedResponse creates {error: message} json body and responds with 412 code
+func PreconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	ErrorResponse(w, r, errors.New(ErrVersionConflict), http.StatusPreconditionFailed)
+}
@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/go-chi/chi"
+)
+
+const (
+	// ErrKeyNotFound error
+	ErrKeyNotFound string = "API key not found"
+)
+
+// KeyCreateRequest type
+type KeyCreateRequest struct {
+	Description     string
+	Role            domain.Role
+	ProjectCode     domain.ProjectCode
+	EnvironmentCode domain.EnvironmentCode
+}
+
+// KeyRestAPI servers owner api keys
+type KeyRestAPI struct {
+	API api.TogglyAPI
+}
+
+// Routes returns routes for api keys
+func (a *KeyRestAPI) Routes() chi.Router {
+	router := chi.NewRouter()
+	router.Group(func(g chi.Router) {
+		g.Get("/", a.list)
+		g.Post("/", a.createKey)
+		g.Get("/{key_id}", a.getKey)
+		g.Delete("/{key_id}", a.revokeKey)
+	})
+	return router
+}
+
+func (a *KeyRestAPI) engine(r *http.Request) api.KeyAPI {
+	return ownerAPI(a.API, r).Keys()
+}
+
+func (a *KeyRestAPI) list(w http.ResponseWriter, r *http.Request) {
+	list, err := a.engine(r).List()
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, list)
+}
+
+func (a *KeyRestAPI) getKey(w http.ResponseWriter, r *http.Request) {
+	key, err := a.engine(r).Get(keyID(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrKeyNotFound:
+			NotFoundResponse(w, r, ErrKeyNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, key)
+}
+
+func (a *KeyRestAPI) revokeKey(w http.ResponseWriter, r *http.Request) {
+	err := a.engine(r).Revoke(keyID(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrKeyNotFound:
+			NotFoundResponse(w, r, ErrKeyNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, nil)
+}
+
+func (a *KeyRestAPI) createKey(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	req := &KeyCreateRequest{}
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, req); err != nil {
+			ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+			return
+		}
+	}
+	var key *domain.APIKey
+	key, err = a.engine(r).Create(&api.KeyInfo{
+		Description:     req.Description,
+		Role:            req.Role,
+		ProjectCode:     req.ProjectCode,
+		EnvironmentCode: req.EnvironmentCode,
+	})
+	if err != nil {
+		if err == api.ErrForbidden {
+			ForbiddenResponse(w, r)
+			return
+		}
+		switch err.(type) {
+		case *api.ErrBadRequest:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, key)
+}
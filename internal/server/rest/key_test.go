@@ -0,0 +1,213 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestKeys(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	var key *domain.APIKey
+
+	useKey := func(req *http.Request) {
+		req.Header[rest.XTogglyAuth] = []string{key.Key}
+		req.Header[rest.XTogglyOwnerID] = []string{"another_owner"}
+	}
+
+	tt := []TestCase{
+		{
+			name:   "empty list",
+			method: http.MethodGet,
+			path:   "/api/v1/key",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.APIKey
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Empty(b)
+			},
+		},
+		{
+			name:   "create key",
+			method: http.MethodPost,
+			path:   "/api/v1/key",
+			body:   &rest.KeyCreateRequest{Description: "ci", Role: domain.RoleAdmin},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				assert.Nil(parseBodyTo(body, &key))
+				assert.NotEmpty(key.ID)
+				assert.NotEmpty(key.Key)
+				assert.Equal(ow, key.OwnerID)
+				assert.Equal("ci", key.Description)
+			},
+		},
+		{
+			name:   "list keys does not expose secret",
+			method: http.MethodGet,
+			path:   "/api/v1/key",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []map[string]interface{}
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 1)
+				assert.Equal(string(key.ID), b[0]["id"])
+				assert.NotContains(b[0], "key")
+				assert.NotContains(b[0], "hash")
+			},
+		},
+		{
+			name:         "create project with api key",
+			method:       http.MethodPost,
+			path:         "/api/v1/project",
+			body:         &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusActive},
+			status:       http.StatusOK,
+			patchRequest: func(req *http.Request) { useKey(req) },
+			validator: func(body []byte) {
+				var p *domain.Project
+				assert.Nil(parseBodyTo(body, &p))
+				assert.Equal(ow, p.OwnerID)
+			},
+		},
+		{
+			name:   "project belongs to the key owner",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1",
+			status: http.StatusOK,
+		},
+		{
+			name:   "key not found",
+			method: http.MethodGet,
+			path:   "/api/v1/key/unknown",
+			status: http.StatusNotFound,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Equal(rest.ErrKeyNotFound, b["error"])
+			},
+		},
+		{
+			name:         "revoke key",
+			method:       http.MethodDelete,
+			path:         "/api/v1/key/",
+			status:       http.StatusOK,
+			patchRequest: func(req *http.Request) { req.URL.Path += string(key.ID) },
+		},
+		{
+			name:         "revoked key is rejected",
+			method:       http.MethodGet,
+			path:         "/api/v1/project",
+			status:       http.StatusUnauthorized,
+			cType:        "text/plain",
+			patchRequest: func(req *http.Request) { useKey(req) },
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
+
+func TestRestForbidden(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	var key *domain.APIKey
+
+	useKey := func(req *http.Request) {
+		req.Header[rest.XTogglyAuth] = []string{key.Key}
+	}
+
+	forbidden := func(body []byte) {
+		var b map[string]interface{}
+		assert.Nil(parseBodyTo(body, &b))
+		assert.Equal(rest.ErrForbidden, b["error"])
+	}
+
+	tt := []TestCase{
+		{
+			name:   "create project",
+			method: http.MethodPost,
+			path:   "/api/v1/project",
+			body:   &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusActive},
+			status: http.StatusOK,
+		},
+		{
+			name:   "create key with unknown role",
+			method: http.MethodPost,
+			path:   "/api/v1/key",
+			body:   &rest.KeyCreateRequest{Role: "owner"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create read-only key",
+			method: http.MethodPost,
+			path:   "/api/v1/key",
+			body:   &rest.KeyCreateRequest{Role: domain.RoleReadOnly, ProjectCode: "project1"},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				assert.Nil(parseBodyTo(body, &key))
+			},
+		},
+		{
+			name:         "read project",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project1",
+			status:       http.StatusOK,
+			patchRequest: useKey,
+		},
+		{
+			name:         "update project",
+			method:       http.MethodPut,
+			path:         "/api/v1/project",
+			body:         &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusDisabled},
+			status:       http.StatusForbidden,
+			patchRequest: useKey,
+			validator:    forbidden,
+		},
+		{
+			name:         "create env",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/env",
+			body:         &rest.EnvironmentCreateRequest{Code: "dev"},
+			status:       http.StatusForbidden,
+			patchRequest: useKey,
+			validator:    forbidden,
+		},
+		{
+			name:         "list keys",
+			method:       http.MethodGet,
+			path:         "/api/v1/key",
+			status:       http.StatusForbidden,
+			patchRequest: useKey,
+			validator:    forbidden,
+		},
+		{
+			name:         "read another project",
+			method:       http.MethodGet,
+			path:         "/api/v1/project/project2/segment",
+			status:       http.StatusForbidden,
+			patchRequest: useKey,
+			validator:    forbidden,
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
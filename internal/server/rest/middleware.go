@@ -2,10 +2,13 @@ package rest
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log"
 	"net/http"
 
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/go-chi/chi/middleware"
 )
 
@@ -25,6 +28,7 @@ const (
 	XTogglyRequestID string = "X-Toggly-Request-Id"
 	XTogglyAuth      string = "X-Toggly-Auth"
 	XTogglyOwnerID   string = "X-Toggly-Owner-Id"
+	XTogglyOverride  string = "X-Toggly-Override-Protection"
 	XServiceName     string = "X-Service-Name"
 	XServiceVersion  string = "X-Service-Version"
 )
@@ -62,21 +66,62 @@ func RequestIDCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
-// OwnerCtx adds auth data to context
-func OwnerCtx(next http.Handler) http.Handler {
+// RequestIDFromContext returns request id, it is empty if RequestIDCtx is not used
+func RequestIDFromContext(r *http.Request) string {
+	rid, _ := r.Context().Value(CtxValueRequestID).(string)
+	return rid
+}
 
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		owner := r.Header.Get(http.CanonicalHeaderKey(XTogglyOwnerID))
-		if owner == "" {
-			log.Print("[WARN] Header X-Toggly-Owner-Id missed.")
-			NotFoundResponse(w, r, "Owner not found")
-			return
+// MasterActor is the actor of requests authenticated with the master key
+const MasterActor = "master"
+
+// KeyFromContext returns api key used to authenticate request, it is nil for the master key
+func KeyFromContext(r *http.Request) *domain.APIKey {
+	key, _ := r.Context().Value(CtxValueAuth).(*domain.APIKey)
+	return key
+}
+
+// AuthCtx authenticates request by X-Toggly-Auth header and adds owner to context.
+// API key is resolved to its owner. Master key grants access to the owner
+// specified by X-Toggly-Owner-Id header.
+func AuthCtx(togglyAPI api.TogglyAPI, masterKey string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(http.CanonicalHeaderKey(XTogglyAuth))
+			if token == "" {
+				log.Print("[WARN] Header X-Toggly-Auth missed.")
+				UnauthorizedResponse(w, r)
+				return
+			}
+			var owner string
+			var key *domain.APIKey
+			if masterKey != "" && subtle.ConstantTimeCompare([]byte(token), []byte(masterKey)) == 1 {
+				owner = r.Header.Get(http.CanonicalHeaderKey(XTogglyOwnerID))
+				if owner == "" {
+					log.Print("[WARN] Header X-Toggly-Owner-Id missed.")
+					NotFoundResponse(w, r, "Owner not found")
+					return
+				}
+			} else {
+				var err error
+				if key, err = togglyAPI.Authenticate(token); err != nil {
+					if err == api.ErrUnauthorized {
+						UnauthorizedResponse(w, r)
+						return
+					}
+					log.Printf("[ERROR] %v", err)
+					ErrorResponse(w, r, err, http.StatusInternalServerError)
+					return
+				}
+				owner = key.OwnerID
+			}
+			ctx := r.Context()
+			ctx = context.WithValue(ctx, CtxValueOwner, owner)
+			ctx = context.WithValue(ctx, CtxValueAuth, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		}
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, CtxValueOwner, owner)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		return http.HandlerFunc(fn)
 	}
-	return http.HandlerFunc(fn)
 }
 
 // ServiceInfo adds service information to the response header
@@ -7,9 +7,9 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/go-chi/chi"
 )
 
@@ -18,6 +18,10 @@ const (
 	ErrObjectNotFound string = "Object not found"
 	// ErrObjectHasInheritors error
 	ErrObjectHasInheritors string = "Object has inheritors"
+	// ErrEnvironmentProtected error
+	ErrEnvironmentProtected string = "Environment is protected"
+	// ErrRevisionNotFound error
+	ErrRevisionNotFound string = "Revision not found"
 )
 
 // ObjectCreateRequest type
@@ -28,6 +32,12 @@ type ObjectCreateRequest struct {
 	Parameters  []*domain.Parameter
 }
 
+// EvaluateRequest type
+type EvaluateRequest struct {
+	UserID     string            `json:"user_id"`
+	Attributes map[string]string `json:"attributes"`
+}
+
 // ObjectRestAPI servers objects
 type ObjectRestAPI struct {
 	API api.TogglyAPI
@@ -42,19 +52,28 @@ func (a *ObjectRestAPI) Routes() chi.Router {
 		g.Put("/", a.updateObject)
 		g.Get("/{object_code}", a.getObject)
 		g.Get("/{object_code}/inheritors", a.getObjectInheritors)
+		g.Get("/{object_code}/history", a.getObjectHistory)
+		g.Post("/{object_code}/revert/{revision}", a.revertObject)
+		g.Post("/{object_code}/evaluate", a.evaluateObject)
 		g.Delete("/{object_code}", a.deleteObject)
 	})
 	return router
 }
 
 func (a *ObjectRestAPI) engine(r *http.Request) api.ObjectAPI {
-	return a.API.ForOwner(owner(r)).Projects().For(projectCode(r)).Environments().For(environmentCode(r)).Objects()
+	forEnv := ownerAPI(a.API, r).Projects().For(projectCode(r)).Environments().For(environmentCode(r))
+	if overrideProtection(r) {
+		forEnv = forEnv.OverrideProtection()
+	}
+	return forEnv.Objects()
 }
 
 func (a *ObjectRestAPI) list(w http.ResponseWriter, r *http.Request) {
 	list, err := a.engine(r).List()
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, "Project not found")
 		case api.ErrEnvironmentNotFound:
@@ -72,6 +91,8 @@ func (a *ObjectRestAPI) getObject(w http.ResponseWriter, r *http.Request) {
 	obj, err := a.engine(r).Get(objectCode(r))
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 		case api.ErrEnvironmentNotFound:
@@ -84,31 +105,165 @@ func (a *ObjectRestAPI) getObject(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	JSONResponse(w, r, obj)
+	TaggedResponse(w, r, objectETag(obj), obj)
+}
+
+// ifMatch checks If-Match header against current object entity tag,
+// inheritor tag changes with parents, so its version alone doesn't match
+func (a *ObjectRestAPI) ifMatch(r *http.Request, code domain.ObjectCode) (int64, error) {
+	if !hasIfMatch(r) {
+		return 0, nil
+	}
+	obj, err := a.engine(r).Get(code)
+	if err != nil {
+		return 0, err
+	}
+	if !matchETag(r.Header.Get("If-Match"), objectETag(obj), false) {
+		return 0, api.ErrVersionConflict
+	}
+	return obj.Version, nil
 }
 
 func (a *ObjectRestAPI) getObjectInheritors(w http.ResponseWriter, r *http.Request) {
 	list, err := a.engine(r).InheritorsFlatList(objectCode(r))
 	if err != nil {
-		log.Printf("[ERROR] %v", err)
-		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
 		return
 	}
 	JSONResponse(w, r, list)
 }
 
+func (a *ObjectRestAPI) getObjectHistory(w http.ResponseWriter, r *http.Request) {
+	list, err := a.engine(r).History(objectCode(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrEnvironmentNotFound:
+			NotFoundResponse(w, r, ErrEnvironmentNotFound)
+		case api.ErrObjectNotFound:
+			NotFoundResponse(w, r, ErrObjectNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, list)
+}
+
+func (a *ObjectRestAPI) revertObject(w http.ResponseWriter, r *http.Request) {
+	rev, err := revision(r)
+	if err != nil {
+		ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+		return
+	}
+	obj, err := a.engine(r).Revert(objectCode(r), rev)
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+			return
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+			return
+		case api.ErrEnvironmentNotFound:
+			NotFoundResponse(w, r, ErrEnvironmentNotFound)
+			return
+		case api.ErrRevisionNotFound:
+			NotFoundResponse(w, r, ErrRevisionNotFound)
+			return
+		case api.ErrObjectParentNotExists, api.ErrObjectInheritorTypeMismatch:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+			return
+		case api.ErrEnvironmentProtected:
+			ErrorResponse(w, r, errors.New(ErrEnvironmentProtected), http.StatusLocked)
+			return
+		}
+		switch err.(type) {
+		case *api.ErrBadRequest, *api.ErrObjectParameter:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	TaggedResponse(w, r, objectETag(obj), obj)
+}
+
+func (a *ObjectRestAPI) evaluateObject(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	req := &EvaluateRequest{}
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, req); err != nil {
+			ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+			return
+		}
+	}
+	values, err := a.engine(r).Evaluate(objectCode(r), &domain.EvaluationContext{
+		UserID:     req.UserID,
+		Attributes: req.Attributes,
+	})
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+		case api.ErrEnvironmentNotFound:
+			NotFoundResponse(w, r, ErrEnvironmentNotFound)
+		case api.ErrObjectNotFound:
+			NotFoundResponse(w, r, ErrObjectNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, values)
+}
+
 func (a *ObjectRestAPI) deleteObject(w http.ResponseWriter, r *http.Request) {
-	err := a.engine(r).Delete(objectCode(r))
+	version, err := a.ifMatch(r, objectCode(r))
+	if err == nil {
+		err = a.engine(r).Delete(objectCode(r), version)
+	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
 		case api.ErrEnvironmentNotFound:
 			NotFoundResponse(w, r, ErrEnvironmentNotFound)
 		case api.ErrObjectNotFound:
 			NotFoundResponse(w, r, ErrObjectNotFound)
 		case api.ErrObjectHasInheritors:
 			ErrorResponse(w, r, errors.New(ErrObjectHasInheritors), http.StatusLocked)
+		case api.ErrEnvironmentProtected:
+			ErrorResponse(w, r, errors.New(ErrEnvironmentProtected), http.StatusLocked)
 		default:
 			log.Printf("[ERROR] %v", err)
 			ErrorResponse(w, r, err, http.StatusInternalServerError)
@@ -147,18 +302,30 @@ func (a *ObjectRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, cre
 			Parameters:  obj.Parameters,
 		})
 	} else {
-		newObj, err = a.engine(r).Update(&api.ObjectInfo{
+		info := &api.ObjectInfo{
 			Code:        obj.Code,
 			Description: obj.Description,
 			Inherits:    obj.Inherits,
 			Parameters:  obj.Parameters,
-		})
+		}
+		if info.Version, err = a.ifMatch(r, obj.Code); err == nil {
+			newObj, err = a.engine(r).Update(info)
+		}
 	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
+			return
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 			return
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+			return
 		case api.ErrEnvironmentNotFound:
 			NotFoundResponse(w, r, ErrEnvironmentNotFound)
 			return
@@ -171,6 +338,9 @@ func (a *ObjectRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, cre
 		case api.ErrObjectInheritorTypeMismatch:
 			ErrorResponse(w, r, err, http.StatusBadRequest)
 			return
+		case api.ErrEnvironmentProtected:
+			ErrorResponse(w, r, errors.New(ErrEnvironmentProtected), http.StatusLocked)
+			return
 		}
 		switch err.(type) {
 		case *api.ErrBadRequest:
@@ -185,5 +355,5 @@ func (a *ObjectRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, cre
 		}
 		return
 	}
-	JSONResponse(w, r, newObj)
+	TaggedResponse(w, r, objectETag(newObj), newObj)
 }
@@ -7,8 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
 	asserts "github.com/stretchr/testify/assert"
 )
 
@@ -183,6 +183,50 @@ func TestRestObject(t *testing.T) {
 				assert.Equal("Object parrent does not exists", b["error"])
 			},
 		},
+		{
+			name:   "Create object bad request: parameter value",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body: &rest.ObjectCreateRequest{
+				Code: "obj1",
+				Parameters: []*domain.Parameter{
+					&domain.Parameter{
+						Code:  "param1",
+						Type:  domain.ParameterBool,
+						Value: "banana",
+					},
+				},
+			},
+			status: http.StatusBadRequest,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal("Object parameter `param1` error: Value `banana` is not valid bool", b["error"])
+			},
+		},
+		{
+			name:   "Create object bad request: enum allowed values",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body: &rest.ObjectCreateRequest{
+				Code: "obj1",
+				Parameters: []*domain.Parameter{
+					&domain.Parameter{
+						Code:  "param1",
+						Type:  domain.ParameterEnum,
+						Value: "red",
+					},
+				},
+			},
+			status: http.StatusBadRequest,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal("Object parameter `param1` error: Enum allowed values not specified", b["error"])
+			},
+		},
 		{
 			name:   "Create object",
 			method: http.MethodPost,
@@ -273,6 +317,61 @@ func TestRestObject(t *testing.T) {
 			},
 			status: http.StatusOK,
 		},
+		{
+			name:   "Evaluate object not found",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj123/evaluate",
+			body:   &rest.EvaluateRequest{UserID: "user1"},
+			status: http.StatusNotFound,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal("Object not found", b["error"])
+			},
+		},
+		{
+			name:   "Evaluate object",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj2/evaluate",
+			body:   &rest.EvaluateRequest{UserID: "user1", Attributes: map[string]string{"country": "US"}},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal(map[string]interface{}{"param1": true, "param2": "value"}, b)
+			},
+		},
+		{
+			name:   "Evaluate environment not found",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env2/evaluate",
+			body:   &rest.EvaluateRequest{UserID: "user1"},
+			status: http.StatusNotFound,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal("Environment not found", b["error"])
+			},
+		},
+		{
+			name:   "Evaluate environment",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/evaluate",
+			body:   &rest.EvaluateRequest{UserID: "user1"},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal(map[string]interface{}{
+					"obj1": map[string]interface{}{"param1": true},
+					"obj2": map[string]interface{}{"param1": true, "param2": "value"},
+				}, b)
+			},
+		},
 		{
 			name:   "Update object bad request",
 			method: http.MethodPut,
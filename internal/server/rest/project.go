@@ -7,10 +7,10 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/Toggly/core/internal/api"
 	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
 
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/pkg/domain"
 
 	"github.com/go-chi/chi"
 )
@@ -18,6 +18,8 @@ import (
 const (
 	// ErrProjectNotFound error
 	ErrProjectNotFound string = "Project not found"
+	// ErrProjectDisabled error
+	ErrProjectDisabled string = "Project is disabled"
 	// ErrProjectNotEmpty error
 	ErrProjectNotEmpty string = "Project not empty"
 )
@@ -43,19 +45,26 @@ func (a *ProjectRestAPI) Routes() chi.Router {
 		group.Put("/", a.updateProject)
 		group.Get("/{project_code}", a.getProject)
 		group.Delete("/{project_code}", a.deleteProject)
+		group.Get("/{project_code}/export", a.exportProject)
+		group.Post("/{project_code}/import", a.importProject)
 	})
 	return router
 }
 
 func (a *ProjectRestAPI) engine(r *http.Request) api.ProjectAPI {
-	return a.API.ForOwner(owner(r)).Projects()
+	return ownerAPI(a.API, r).Projects()
 }
 
 func (a *ProjectRestAPI) list(w http.ResponseWriter, r *http.Request) {
 	list, err := a.engine(r).List()
 	if err != nil {
-		log.Printf("[ERROR] %v", err)
-		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
 		return
 	}
 	JSONResponse(w, r, list)
@@ -65,6 +74,8 @@ func (a *ProjectRestAPI) getProject(w http.ResponseWriter, r *http.Request) {
 	proj, err := a.engine(r).Get(projectCode(r))
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 		default:
@@ -73,13 +84,32 @@ func (a *ProjectRestAPI) getProject(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	JSONResponse(w, r, proj)
+	VersionedResponse(w, r, proj.Version, proj)
+}
+
+// ifMatch checks If-Match header against current project version
+func (a *ProjectRestAPI) ifMatch(r *http.Request, code domain.ProjectCode) (int64, error) {
+	if !hasIfMatch(r) {
+		return 0, nil
+	}
+	proj, err := a.engine(r).Get(code)
+	if err != nil {
+		return 0, err
+	}
+	return ifMatch(r, proj.Version)
 }
 
 func (a *ProjectRestAPI) deleteProject(w http.ResponseWriter, r *http.Request) {
-	err := a.engine(r).Delete(projectCode(r))
+	_, err := a.ifMatch(r, projectCode(r))
+	if err == nil {
+		err = a.engine(r).Delete(projectCode(r))
+	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 		case api.ErrProjectNotEmpty:
@@ -121,14 +151,23 @@ func (a *ProjectRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, cr
 			Status:      proj.Status,
 		})
 	} else {
-		p, err = a.engine(r).Update(&api.ProjectInfo{
+		info := &api.ProjectInfo{
 			Code:        proj.Code,
 			Description: proj.Description,
 			Status:      proj.Status,
-		})
+		}
+		if info.Version, err = a.ifMatch(r, proj.Code); err == nil {
+			p, err = a.engine(r).Update(info)
+		}
 	}
 	if err != nil {
 		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
+			return
 		case api.ErrProjectNotFound:
 			NotFoundResponse(w, r, ErrProjectNotFound)
 			return
@@ -144,5 +183,5 @@ func (a *ProjectRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, cr
 		}
 		return
 	}
-	JSONResponse(w, r, p)
+	VersionedResponse(w, r, p.Version, p)
 }
@@ -8,8 +8,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/Toggly/core/internal/domain"
 	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
 	asserts "github.com/stretchr/testify/assert"
 )
 
@@ -162,6 +162,30 @@ func TestRestProject(t *testing.T) {
 				assert.Equal(domain.ProjectStatusDisabled, b.Status)
 			},
 		},
+		{
+			name:   "create env in disabled project",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env",
+			body:   &rest.EnvironmentCreateRequest{Code: "env1"},
+			status: http.StatusLocked,
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal(rest.ErrProjectDisabled, b["error"])
+			},
+		},
+		{
+			name:   "enable project",
+			method: http.MethodPut,
+			path:   "/api/v1/project",
+			body: &rest.ProjectCreateRequest{
+				Code:        "project1",
+				Description: "Project 1 Updated",
+				Status:      domain.ProjectStatusActive,
+			},
+			status: http.StatusOK,
+		},
 		{
 			name:   "delete project not found",
 			method: http.MethodDelete,
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
-	"github.com/Toggly/core/internal/api"
-	"github.com/Toggly/core/internal/domain"
+	"github.com/Toggly/core/internal/pkg/authzapi"
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 )
@@ -18,13 +21,18 @@ import (
 type APIRouter struct {
 	Version    string
 	API        api.TogglyAPI
+	Events     *eventbus.Bus
 	Port       int
 	BasePath   string
+	MasterKey  string
 	httpServer *http.Server
 	lock       sync.Mutex
 	IsDebug    bool
 }
 
+// streamHeartbeat is the interval of comments keeping idle streams open
+const streamHeartbeat = 30 * time.Second
+
 // Run rest api
 func (r *APIRouter) Run() {
 	routes := r.Router()
@@ -60,8 +68,6 @@ func (r *APIRouter) Router() chi.Router {
 	router := chi.NewRouter()
 	router.Use(middleware.RealIP)
 	router.Use(middleware.Recoverer)
-	router.Use(middleware.Throttle(1000))
-	router.Use(middleware.Timeout(60 * time.Second))
 	router.Use(middleware.Heartbeat("/ping"))
 	router.Use(ServiceInfo("Toggly", r.Version))
 	router.Route(r.BasePath, r.versions)
@@ -79,17 +85,48 @@ func (r *APIRouter) versions(router chi.Router) {
 func (r *APIRouter) v1(router chi.Router) {
 	router.Use(middleware.Logger)
 	router.Use(RequestIDCtx)
-	router.Use(OwnerCtx)
+	router.Use(AuthCtx(r.API, r.MasterKey))
 	router.Use(VersionCtx("v1"))
-	router.Mount("/project", (&ProjectRestAPI{API: r.API}).Routes())
-	router.Mount("/project/{project_code}/env", (&EnvironmentRestAPI{API: r.API}).Routes())
-	router.Mount("/project/{project_code}/env/{env_code}/object", (&ObjectRestAPI{API: r.API}).Routes())
+	// streams are long-living, they are not limited by the request throttling and timeout
+	if r.Events != nil {
+		stream := &StreamRestAPI{API: r.API, Bus: r.Events, Heartbeat: streamHeartbeat}
+		router.Get("/project/{project_code}/env/{env_code}/stream", stream.Stream)
+	}
+	resources := chi.NewRouter()
+	resources.Use(middleware.Throttle(1000))
+	resources.Use(middleware.Timeout(60 * time.Second))
+	resources.Mount("/key", (&KeyRestAPI{API: r.API}).Routes())
+	resources.Mount("/audit", (&AuditRestAPI{API: r.API}).Routes())
+	resources.Mount("/project", (&ProjectRestAPI{API: r.API}).Routes())
+	resources.Mount("/project/{project_code}/env", (&EnvironmentRestAPI{API: r.API}).Routes())
+	resources.Mount("/project/{project_code}/segment", (&SegmentRestAPI{API: r.API}).Routes())
+	resources.Mount("/project/{project_code}/webhook", (&WebhookRestAPI{API: r.API}).Routes())
+	resources.Mount("/project/{project_code}/env/{env_code}/object", (&ObjectRestAPI{API: r.API}).Routes())
+	router.Mount("/", resources)
 }
 
 func owner(r *http.Request) string {
 	return OwnerFromContext(r)
 }
 
+// actor returns id of the api key used for the request
+func actor(r *http.Request) string {
+	if key := KeyFromContext(r); key != nil {
+		return string(key.ID)
+	}
+	return MasterActor
+}
+
+// ownerAPI returns owner api restricted by permissions of the request api key
+func ownerAPI(togglyAPI api.TogglyAPI, r *http.Request) api.OwnerAPI {
+	togglyAPI = togglyAPI.WithActor(actor(r)).WithRequestID(RequestIDFromContext(r))
+	return authzapi.NewAuthorizedAPI(togglyAPI, KeyFromContext(r)).ForOwner(owner(r))
+}
+
+func overrideProtection(r *http.Request) bool {
+	return r.Header.Get(XTogglyOverride) == "true"
+}
+
 func projectCode(r *http.Request) domain.ProjectCode {
 	return domain.ProjectCode(chi.URLParam(r, "project_code"))
 }
@@ -98,6 +135,22 @@ func environmentCode(r *http.Request) domain.EnvironmentCode {
 	return domain.EnvironmentCode(chi.URLParam(r, "env_code"))
 }
 
+func segmentCode(r *http.Request) domain.SegmentCode {
+	return domain.SegmentCode(chi.URLParam(r, "segment_code"))
+}
+
+func keyID(r *http.Request) domain.APIKeyID {
+	return domain.APIKeyID(chi.URLParam(r, "key_id"))
+}
+
+func webhookID(r *http.Request) domain.WebhookID {
+	return domain.WebhookID(chi.URLParam(r, "webhook_id"))
+}
+
 func objectCode(r *http.Request) domain.ObjectCode {
 	return domain.ObjectCode(chi.URLParam(r, "object_code"))
 }
+
+func revision(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "revision"), 10, 64)
+}
@@ -10,13 +10,12 @@ import (
 	"testing"
 
 	"github.com/Toggly/core/internal/pkg/engine"
-	"github.com/Toggly/core/internal/pkg/storage/mongo"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
 	"github.com/Toggly/core/internal/server/rest"
-	"github.com/globalsign/mgo"
 	asserts "github.com/stretchr/testify/assert"
 )
 
-const MongoTestUrl = "mongodb://localhost:27017/toggly_test_rest"
 const TestAuthToken = "TestToken"
 const ow = "test_owner"
 
@@ -30,24 +29,26 @@ type TestCase struct {
 	before       func(rs *httptest.Server)
 	after        func(rs *httptest.Server)
 	validator    func(body []byte)
+	headers      func(h http.Header)
 	patchRequest func(req *http.Request)
 	skip         bool
 	body         interface{}
 }
 
+var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+
 func GetRouter() *rest.APIRouter {
-	dataStorage, _ := mongo.NewMongoStorage(MongoTestUrl)
 	return &rest.APIRouter{
-		Version:  "test",
-		API:      engine.NewTogglyAPI(&dataStorage),
-		BasePath: "/api",
-		IsDebug:  false,
+		Version:   "test",
+		API:       &engine.Engine{Storage: &dataStorage, DryRunStorage: memory.NewMemoryStorage},
+		BasePath:  "/api",
+		MasterKey: TestAuthToken,
+		IsDebug:   false,
 	}
 }
 
 func DropDB() {
-	session, _ := mgo.Dial(MongoTestUrl)
-	session.DB("").DropDatabase()
+	dataStorage = memory.NewMemoryStorage()
 }
 
 func BeforeTest() {
@@ -119,7 +120,12 @@ func runTestCase(t *testing.T, rs *httptest.Server, tc TestCase) {
 		if tc.cType == "" {
 			tc.cType = "application/json"
 		}
-		assert.Contains(r.Header[http.CanonicalHeaderKey("Content-Type")][0], tc.cType)
+		if tc.status != http.StatusNotModified {
+			assert.Contains(r.Header[http.CanonicalHeaderKey("Content-Type")][0], tc.cType)
+		}
+		if tc.headers != nil {
+			tc.headers(r.Header)
+		}
 		if tc.validator != nil {
 			tc.validator(responceBody)
 		}
@@ -149,6 +155,26 @@ func TestRestRequestHeaders(t *testing.T) {
 				assert.Equal("Owner not found", b["error"])
 			},
 		},
+		{
+			name:   "auth header missed",
+			method: http.MethodGet,
+			path:   "/api/v1",
+			status: http.StatusUnauthorized,
+			cType:  "text/plain",
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = nil
+			},
+		},
+		{
+			name:   "unknown api key",
+			method: http.MethodGet,
+			path:   "/api/v1",
+			status: http.StatusUnauthorized,
+			cType:  "text/plain",
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = []string{"unknown"}
+			},
+		},
 		{
 			name:   "owner ok",
 			method: http.MethodGet,
@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/go-chi/chi"
+)
+
+const (
+	// ErrSegmentNotFound error
+	ErrSegmentNotFound string = "Segment not found"
+	// ErrSegmentInUse error
+	ErrSegmentInUse string = "Segment is in use"
+)
+
+// SegmentCreateRequest type
+type SegmentCreateRequest struct {
+	Code        domain.SegmentCode
+	Description string
+	Include     []string
+	Exclude     []string
+	Rules       []*domain.SegmentRule
+}
+
+// SegmentRestAPI servers segments
+type SegmentRestAPI struct {
+	API api.TogglyAPI
+}
+
+// Routes returns routes for segments
+func (a *SegmentRestAPI) Routes() chi.Router {
+	router := chi.NewRouter()
+	router.Group(func(g chi.Router) {
+		g.Get("/", a.list)
+		g.Post("/", a.createSegment)
+		g.Put("/", a.updateSegment)
+		g.Get("/{segment_code}", a.getSegment)
+		g.Delete("/{segment_code}", a.deleteSegment)
+	})
+	return router
+}
+
+func (a *SegmentRestAPI) engine(r *http.Request) api.SegmentAPI {
+	return ownerAPI(a.API, r).Projects().For(projectCode(r)).Segments()
+}
+
+func (a *SegmentRestAPI) list(w http.ResponseWriter, r *http.Request) {
+	list, err := a.engine(r).List()
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, list)
+}
+
+func (a *SegmentRestAPI) getSegment(w http.ResponseWriter, r *http.Request) {
+	segment, err := a.engine(r).Get(segmentCode(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrSegmentNotFound:
+			NotFoundResponse(w, r, ErrSegmentNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, segment)
+}
+
+func (a *SegmentRestAPI) deleteSegment(w http.ResponseWriter, r *http.Request) {
+	err := a.engine(r).Delete(segmentCode(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+		case api.ErrSegmentNotFound:
+			NotFoundResponse(w, r, ErrSegmentNotFound)
+		case api.ErrSegmentInUse:
+			ErrorResponse(w, r, errors.New(ErrSegmentInUse), http.StatusLocked)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, nil)
+}
+
+func (a *SegmentRestAPI) createSegment(w http.ResponseWriter, r *http.Request) {
+	a.createUpdate(w, r, true)
+}
+
+func (a *SegmentRestAPI) updateSegment(w http.ResponseWriter, r *http.Request) {
+	a.createUpdate(w, r, false)
+}
+
+func (a *SegmentRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, create bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	req := &SegmentCreateRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+		return
+	}
+	info := &api.SegmentInfo{
+		Code:        req.Code,
+		Description: req.Description,
+		Include:     req.Include,
+		Exclude:     req.Exclude,
+		Rules:       req.Rules,
+	}
+	var segment *domain.Segment
+	if create {
+		segment, err = a.engine(r).Create(info)
+	} else {
+		segment, err = a.engine(r).Update(info)
+	}
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+			return
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+			return
+		case api.ErrSegmentNotFound:
+			NotFoundResponse(w, r, ErrSegmentNotFound)
+			return
+		}
+		switch err.(type) {
+		case *api.ErrBadRequest:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		case *storage.UniqueIndexError:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		default:
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, segment)
+}
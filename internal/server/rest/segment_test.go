@@ -0,0 +1,186 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestSegment(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	doRequest := func(rs *httptest.Server, method, path string, body interface{}) {
+		data, err := json.Marshal(body)
+		assert.Nil(err)
+		req, err := http.NewRequest(method, rs.URL+path, bytes.NewBuffer(data))
+		assert.Nil(err)
+		req.Header = http.Header{
+			rest.XTogglyAuth:    []string{TestAuthToken},
+			rest.XTogglyOwnerID: []string{ow},
+		}
+		_, err = rs.Client().Do(req)
+		assert.Nil(err)
+	}
+
+	errorValidator := func(msg string) func(body []byte) {
+		return func(body []byte) {
+			var b map[string]interface{}
+			err := parseBodyTo(body, &b)
+			assert.Nil(err)
+			assert.Equal(msg, b["error"])
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:   "empty list",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/segment",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.Segment
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Empty(b)
+			},
+		},
+		{
+			name:      "list but project not found",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project2/segment",
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrProjectNotFound),
+		},
+		{
+			name:      "segment not found",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project1/segment/beta",
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrSegmentNotFound),
+		},
+		{
+			name:      "update but segment not found",
+			method:    http.MethodPut,
+			path:      "/api/v1/project/project1/segment",
+			body:      &rest.SegmentCreateRequest{Code: "beta"},
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrSegmentNotFound),
+		},
+		{
+			name:      "create without code",
+			method:    http.MethodPost,
+			path:      "/api/v1/project/project1/segment",
+			body:      &rest.SegmentCreateRequest{},
+			status:    http.StatusBadRequest,
+			validator: errorValidator("Bad request: Segment code not specified"),
+		},
+		{
+			name:   "create segment",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/segment",
+			body: &rest.SegmentCreateRequest{
+				Code:    "beta",
+				Include: []string{"u1"},
+				Rules: []*domain.SegmentRule{
+					{Conditions: []*domain.RuleCondition{{Attribute: "plan", Operator: domain.RuleOpEquals, Values: []string{"beta"}}}},
+				},
+			},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				seg := &domain.Segment{}
+				err := parseBodyTo(body, seg)
+				assert.Nil(err)
+				assert.Equal(domain.SegmentCode("beta"), seg.Code)
+				assert.Equal(domain.ProjectCode("project1"), seg.ProjectCode)
+				assert.Equal(ow, seg.OwnerID)
+				assert.Equal([]string{"u1"}, seg.Include)
+				assert.Len(seg.Rules, 1)
+			},
+		},
+		{
+			name:   "create duplicate segment",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/segment",
+			body:   &rest.SegmentCreateRequest{Code: "beta"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "update segment",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/segment",
+			body:   &rest.SegmentCreateRequest{Code: "beta", Description: "Beta testers", Include: []string{"u1", "u2"}},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				seg := &domain.Segment{}
+				err := parseBodyTo(body, seg)
+				assert.Nil(err)
+				assert.Equal("Beta testers", seg.Description)
+				assert.Equal([]string{"u1", "u2"}, seg.Include)
+				assert.Empty(seg.Rules)
+			},
+		},
+		{
+			name:   "evaluate object with segment rule",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object/obj1/evaluate",
+			body:   &rest.EvaluateRequest{UserID: "u2"},
+			status: http.StatusOK,
+			before: func(rs *httptest.Server) {
+				doRequest(rs, http.MethodPost, "/api/v1/project/project1/env/env1/object", &rest.ObjectCreateRequest{
+					Code: "obj1",
+					Parameters: []*domain.Parameter{
+						{Code: "new_ui", Type: domain.ParameterBool, Value: false, Rules: []*domain.Rule{{Segment: "beta", Value: true}}},
+					},
+				})
+			},
+			validator: func(body []byte) {
+				var b map[string]interface{}
+				err := parseBodyTo(body, &b)
+				assert.Nil(err)
+				assert.Equal(map[string]interface{}{"new_ui": true}, b)
+			},
+		},
+		{
+			name:      "delete segment in use",
+			method:    http.MethodDelete,
+			path:      "/api/v1/project/project1/segment/beta",
+			status:    http.StatusLocked,
+			validator: errorValidator(rest.ErrSegmentInUse),
+			after: func(rs *httptest.Server) {
+				doRequest(rs, http.MethodDelete, "/api/v1/project/project1/env/env1/object/obj1", nil)
+			},
+		},
+		{
+			name:   "delete segment",
+			method: http.MethodDelete,
+			path:   "/api/v1/project/project1/segment/beta",
+			status: http.StatusOK,
+		},
+		{
+			name:      "delete segment not found",
+			method:    http.MethodDelete,
+			path:      "/api/v1/project/project1/segment/beta",
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrSegmentNotFound),
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	doRequest(rs, http.MethodPost, "/api/v1/project", &rest.ProjectCreateRequest{Code: "project1", Status: domain.ProjectStatusActive})
+	doRequest(rs, http.MethodPost, "/api/v1/project/project1/env", &rest.EnvironmentCreateRequest{Code: "env1"})
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
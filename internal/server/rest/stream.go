@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// EventReset is sent to the stream when events after Last-Event-ID are lost, client should reload objects
+const EventReset = "reset"
+
+// StreamRestAPI streams environment object changes as server-sent events
+type StreamRestAPI struct {
+	API       api.TogglyAPI
+	Bus       *eventbus.Bus
+	Heartbeat time.Duration
+}
+
+// objectEvents selects object changes of the environment
+func objectEvents(owner string, project domain.ProjectCode, env domain.EnvironmentCode) eventbus.Filter {
+	return func(e *domain.Event) bool {
+		return e.Owner == owner && e.ProjectCode == project && e.EnvCode == env &&
+			strings.HasPrefix(string(e.Type), "object.")
+	}
+}
+
+// Stream sends object change events of the environment until client disconnects.
+// Stream is resumed from Last-Event-ID header, reset event is sent instead of the missed events if some of them are lost.
+func (a *StreamRestAPI) Stream(w http.ResponseWriter, r *http.Request) {
+	_, err := ownerAPI(a.API, r).Projects().For(projectCode(r)).Environments().Get(environmentCode(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		case api.ErrEnvironmentNotFound:
+			NotFoundResponse(w, r, ErrEnvironmentNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		ErrorResponse(w, r, fmt.Errorf("Streaming unsupported"), http.StatusInternalServerError)
+		return
+	}
+
+	lastID, known := a.lastEventID(r.Header.Get("Last-Event-ID"))
+	sub, replay, complete := a.Bus.Subscribe(objectEvents(owner(r), projectCode(r), environmentCode(r)), lastID)
+	defer sub.Close()
+	complete = complete && known
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if complete {
+		for _, msg := range replay {
+			a.writeEvent(w, msg)
+		}
+	} else {
+		fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: {}\n\n", a.Bus.Epoch, sub.Start, EventReset)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(a.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-sub.C():
+			if !ok {
+				return
+			}
+			a.writeEvent(w, msg)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// lastEventID parses `<bus epoch>-<message id>` sent by client, false is returned for IDs of another bus
+func (a *StreamRestAPI) lastEventID(value string) (uint64, bool) {
+	if value == "" {
+		return 0, true
+	}
+	parts := strings.SplitN(value, "-", 2)
+	if len(parts) != 2 || parts[0] != a.Bus.Epoch {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+func (a *StreamRestAPI) writeEvent(w http.ResponseWriter, msg *eventbus.Message) {
+	data, err := json.Marshal(msg.Event)
+	if err != nil {
+		log.Printf("[ERROR] Can't encode event %s: %v", msg.Event.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %s-%d\nevent: %s\ndata: %s\n\n", a.Bus.Epoch, msg.ID, msg.Event.Type, data)
+}
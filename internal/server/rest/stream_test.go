@@ -0,0 +1,164 @@
+package rest_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+type sse struct {
+	id    string
+	event string
+	data  string
+}
+
+// openStream connects to the stream, the connection is closed by returned function
+func openStream(t *testing.T, rs *httptest.Server, path, lastID string) (*http.Response, *bufio.Reader, func()) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, err := http.NewRequest(http.MethodGet, rs.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set(rest.XTogglyAuth, TestAuthToken)
+	req.Header.Set(rest.XTogglyOwnerID, ow)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := rs.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, bufio.NewReader(resp.Body), func() {
+		cancel()
+		resp.Body.Close()
+	}
+}
+
+// readEvent reads the next event skipping comments
+func readEvent(t *testing.T, r *bufio.Reader) *sse {
+	e := &sse{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && e.event != "":
+			return e
+		case strings.HasPrefix(line, "id: "):
+			e.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			e.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestRestStream(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	bus := eventbus.New(100, 10)
+	togglyAPI := &engine.Engine{Storage: &dataStorage, Events: bus}
+	pApi := togglyAPI.ForOwner(ow).Projects()
+	pApi.Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	envApi := pApi.For("project1").Environments()
+	envApi.Create(&api.EnvironmentInfo{Code: "env1"})
+	envApi.Create(&api.EnvironmentInfo{Code: "env2"})
+	param := func(value bool) []*domain.Parameter {
+		return []*domain.Parameter{{Code: "enabled", Type: domain.ParameterBool, Value: value}}
+	}
+	objApi := envApi.For("env1").Objects()
+	_, err := objApi.Create(&api.ObjectInfo{Code: "base", Parameters: param(false)})
+	assert.Nil(err)
+	_, err = envApi.For("env2").Objects().Create(&api.ObjectInfo{
+		Code:     "child",
+		Inherits: &domain.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "base"},
+	})
+	assert.Nil(err)
+
+	router := GetRouter()
+	router.API = togglyAPI
+	router.Events = bus
+	rs := httptest.NewServer(router.Router())
+	defer rs.Close()
+
+	tt := []TestCase{
+		{
+			name:   "environment routes are served along with stream",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1",
+			status: http.StatusOK,
+		},
+		{
+			name:   "objects are served along with stream",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env1/object/base",
+			status: http.StatusOK,
+		},
+		{
+			name:   "stream of unknown environment",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/env/env3/stream",
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	resp, env2, closeEnv2 := openStream(t, rs, "/api/v1/project/project1/env/env2/stream", "")
+	defer closeEnv2()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	_, err = objApi.Update(&api.ObjectInfo{Code: "base", Parameters: param(true)})
+	assert.Nil(err)
+
+	e := readEvent(t, env2)
+	assert.Equal(string(domain.EventObjectUpdate), e.event)
+	assert.Equal(bus.Epoch+"-7", e.id)
+	var event struct {
+		ObjectCode domain.ObjectCode         `json:"object_code"`
+		Parent     *domain.ObjectInheritance `json:"parent"`
+		Data       struct{ Parameters []*domain.Parameter }
+	}
+	assert.Nil(json.Unmarshal([]byte(e.data), &event))
+	assert.Equal(domain.ObjectCode("child"), event.ObjectCode)
+	assert.Equal(domain.ObjectCode("base"), event.Parent.ObjectCode)
+	assert.Equal(true, event.Data.Parameters[0].Value, "inheritor has recomputed value")
+
+	_, resumed, closeResumed := openStream(t, rs, "/api/v1/project/project1/env/env1/stream", bus.Epoch+"-1")
+	defer closeResumed()
+	e = readEvent(t, resumed)
+	assert.Equal(bus.Epoch+"-4", e.id)
+	assert.Equal(string(domain.EventObjectCreate), e.event)
+	e = readEvent(t, resumed)
+	assert.Equal(bus.Epoch+"-6", e.id)
+	assert.Equal(string(domain.EventObjectUpdate), e.event)
+
+	for _, lastID := range []string{bus.Epoch + "-100", eventbus.New(1, 1).Epoch + "-1", "1"} {
+		_, reset, closeReset := openStream(t, rs, "/api/v1/project/project1/env/env1/stream", lastID)
+		e = readEvent(t, reset)
+		closeReset()
+		assert.Equal(rest.EventReset, e.event, lastID)
+		assert.Equal(bus.Epoch+"-7", e.id)
+	}
+
+	AfterTest()
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/transfer"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// ContentTypeYAML is the content type of YAML documents
+const ContentTypeYAML = "application/x-yaml"
+
+// isYAML reports whether the header value requests YAML document
+func isYAML(header string) bool {
+	return strings.Contains(header, "yaml")
+}
+
+// exportProject responds with project document, YAML is returned for `format=yaml` query or YAML Accept header
+func (a *ProjectRestAPI) exportProject(w http.ResponseWriter, r *http.Request) {
+	doc, err := a.engine(r).Export(projectCode(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+		case api.ErrProjectNotFound:
+			NotFoundResponse(w, r, ErrProjectNotFound)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	if r.URL.Query().Get("format") != "yaml" && !isYAML(r.Header.Get("Accept")) {
+		JSONResponse(w, r, doc)
+		return
+	}
+	data, err := transfer.MarshalYAML(doc)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentTypeYAML)
+	w.Write(data)
+}
+
+// importProject applies JSON or YAML document to the project, `dry_run=true` query only checks it.
+// Objects of protected environments are changed with protection override header only.
+// Project code of the document defaults to the path one.
+func (a *ProjectRestAPI) importProject(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	doc := &domain.Document{}
+	if isYAML(r.Header.Get("Content-Type")) {
+		err = transfer.UnmarshalYAML(body, doc)
+	} else {
+		err = json.Unmarshal(body, doc)
+	}
+	if err != nil {
+		ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+		return
+	}
+	if doc.Project != nil && doc.Project.Code == "" {
+		doc.Project.Code = projectCode(r)
+	}
+	if doc.Project != nil && doc.Project.Code != projectCode(r) {
+		ErrorResponse(w, r, api.NewBadRequestError("Document project code doesn't match the path"), http.StatusBadRequest)
+		return
+	}
+	changes, err := a.engine(r).Import(doc, r.URL.Query().Get("dry_run") == "true", overrideProtection(r))
+	if err != nil {
+		switch err {
+		case api.ErrForbidden:
+			ForbiddenResponse(w, r)
+			return
+		case api.ErrVersionConflict:
+			PreconditionFailedResponse(w, r)
+			return
+		case api.ErrProjectDisabled:
+			ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+			return
+		case api.ErrEnvironmentProtected:
+			ErrorResponse(w, r, errors.New(ErrEnvironmentProtected), http.StatusLocked)
+			return
+		case api.ErrObjectParentNotExists, api.ErrObjectInheritorTypeMismatch:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+			return
+		}
+		switch err.(type) {
+		case *api.ErrBadRequest, *api.ErrObjectParameter, *storage.UniqueIndexError:
+			ErrorResponse(w, r, err, http.StatusBadRequest)
+		default:
+			log.Printf("[ERROR] %v", err)
+			ErrorResponse(w, r, err, http.StatusInternalServerError)
+		}
+		return
+	}
+	JSONResponse(w, r, changes)
+}
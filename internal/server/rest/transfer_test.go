@@ -0,0 +1,207 @@
+package rest_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const projectDocument = `version: 1
+project:
+  code: project1
+  description: Project 1
+  status: active
+environments:
+- code: dev
+  description: Development
+  protected: false
+  objects:
+  - code: obj1
+    description: ""
+    parameters:
+    - code: enabled
+      type: bool
+      value: true
+`
+
+const protectedDocument = `version: 1
+project:
+  code: project1
+  description: Project 1
+  status: active
+environments:
+- code: prod
+  description: Production
+  protected: true
+  objects:
+  - code: obj1
+    parameters:
+    - code: enabled
+      type: bool
+      value: false
+`
+
+// yamlBody replaces request body with YAML document
+func yamlBody(doc string) func(req *http.Request) {
+	return func(req *http.Request) {
+		req.Body = ioutil.NopCloser(bytes.NewBufferString(doc))
+		req.ContentLength = int64(len(doc))
+		req.Header.Set("Content-Type", rest.ContentTypeYAML)
+	}
+}
+
+func TestRestTransfer(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	changes := func(expected ...domain.ImportAction) func(body []byte) {
+		return func(body []byte) {
+			var list []*domain.ImportChange
+			assert.Nil(parseBodyTo(body, &list))
+			actions := make([]domain.ImportAction, 0)
+			for _, c := range list {
+				actions = append(actions, c.Action)
+			}
+			assert.Equal(expected, actions)
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:   "export project not found",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/export",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "import bad request",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/import",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "import unsupported version",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/import",
+			body:   &domain.Document{Version: 2, Project: &domain.DocumentProject{Status: domain.ProjectStatusActive}},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:         "import project code mismatch",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project2/import",
+			status:       http.StatusBadRequest,
+			body:         &domain.Document{},
+			patchRequest: yamlBody(projectDocument),
+		},
+		{
+			name:         "import dry run",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/import?dry_run=true",
+			status:       http.StatusOK,
+			body:         &domain.Document{},
+			patchRequest: yamlBody(projectDocument),
+			validator:    changes(domain.ImportCreate, domain.ImportCreate, domain.ImportCreate),
+		},
+		{
+			name:   "project is not created by dry run",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1",
+			status: http.StatusNotFound,
+		},
+		{
+			name:         "import",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/import",
+			status:       http.StatusOK,
+			body:         &domain.Document{},
+			patchRequest: yamlBody(projectDocument),
+			validator:    changes(domain.ImportCreate, domain.ImportCreate, domain.ImportCreate),
+		},
+		{
+			name:   "export",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/export",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				doc := &domain.Document{}
+				assert.Nil(parseBodyTo(body, doc))
+				assert.Equal(domain.DocumentVersion, doc.Version)
+				assert.Equal("Project 1", doc.Project.Description)
+				assert.Len(doc.Environments[0].Objects, 1)
+			},
+		},
+		{
+			name:   "export yaml",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/export?format=yaml",
+			status: http.StatusOK,
+			cType:  rest.ContentTypeYAML,
+			validator: func(body []byte) {
+				assert.Equal(`version: 1
+project:
+  code: project1
+  description: Project 1
+  status: active
+segments: []
+environments:
+- code: dev
+  description: Development
+  protected: false
+  objects:
+  - code: obj1
+    description: ""
+    parameters:
+    - code: enabled
+      description: ""
+      type: bool
+      value: true
+`, string(body))
+			},
+		},
+		{
+			name:         "import unchanged",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/import",
+			status:       http.StatusOK,
+			body:         &domain.Document{},
+			patchRequest: yamlBody(projectDocument),
+			validator:    changes(domain.ImportUnchanged, domain.ImportUnchanged, domain.ImportUnchanged),
+		},
+		{
+			name:         "import to protected environment",
+			method:       http.MethodPost,
+			path:         "/api/v1/project/project1/import",
+			status:       http.StatusLocked,
+			body:         &domain.Document{},
+			patchRequest: yamlBody(protectedDocument),
+		},
+		{
+			name:   "import to protected environment with override",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/import",
+			status: http.StatusOK,
+			body:   &domain.Document{},
+			patchRequest: func(req *http.Request) {
+				yamlBody(protectedDocument)(req)
+				req.Header.Set(rest.XTogglyOverride, "true")
+			},
+			validator: changes(domain.ImportUnchanged, domain.ImportUnchanged, domain.ImportCreate),
+		},
+	}
+
+	rs := httptest.NewServer(GetRouter().Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	"github.com/go-chi/chi"
+)
+
+// ErrWebhookNotFound error
+const ErrWebhookNotFound string = "Webhook not found"
+
+// WebhookCreateRequest type. ID is required on update.
+type WebhookCreateRequest struct {
+	ID          domain.WebhookID         `json:"id"`
+	URL         string                   `json:"url"`
+	Secret      string                   `json:"secret"`
+	Events      []domain.EventType       `json:"events"`
+	EnvCodes    []domain.EnvironmentCode `json:"env_codes"`
+	ObjectCodes []domain.ObjectCode      `json:"object_codes"`
+}
+
+// WebhookRestAPI servers project webhooks
+type WebhookRestAPI struct {
+	API api.TogglyAPI
+}
+
+// Routes returns routes for webhooks
+func (a *WebhookRestAPI) Routes() chi.Router {
+	router := chi.NewRouter()
+	router.Group(func(g chi.Router) {
+		g.Get("/", a.list)
+		g.Post("/", a.createWebhook)
+		g.Put("/", a.updateWebhook)
+		g.Get("/{webhook_id}", a.getWebhook)
+		g.Delete("/{webhook_id}", a.deleteWebhook)
+		g.Get("/{webhook_id}/deliveries", a.deliveries)
+		g.Post("/{webhook_id}/test", a.test)
+	})
+	return router
+}
+
+func (a *WebhookRestAPI) engine(r *http.Request) api.WebhookAPI {
+	return ownerAPI(a.API, r).Projects().For(projectCode(r)).Webhooks()
+}
+
+// errorResponse writes response of webhook api error
+func (a *WebhookRestAPI) errorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case api.ErrForbidden:
+		ForbiddenResponse(w, r)
+		return
+	case api.ErrProjectNotFound:
+		NotFoundResponse(w, r, ErrProjectNotFound)
+		return
+	case api.ErrProjectDisabled:
+		ErrorResponse(w, r, errors.New(ErrProjectDisabled), http.StatusLocked)
+		return
+	case api.ErrWebhookNotFound:
+		NotFoundResponse(w, r, ErrWebhookNotFound)
+		return
+	}
+	switch err.(type) {
+	case *api.ErrBadRequest:
+		ErrorResponse(w, r, err, http.StatusBadRequest)
+	case *storage.UniqueIndexError:
+		ErrorResponse(w, r, err, http.StatusBadRequest)
+	default:
+		log.Printf("[ERROR] %v", err)
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+	}
+}
+
+func (a *WebhookRestAPI) list(w http.ResponseWriter, r *http.Request) {
+	list, err := a.engine(r).List()
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, list)
+}
+
+func (a *WebhookRestAPI) getWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := a.engine(r).Get(webhookID(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, hook)
+}
+
+func (a *WebhookRestAPI) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := a.engine(r).Delete(webhookID(r)); err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, nil)
+}
+
+func (a *WebhookRestAPI) deliveries(w http.ResponseWriter, r *http.Request) {
+	list, err := a.engine(r).Deliveries(webhookID(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, list)
+}
+
+func (a *WebhookRestAPI) test(w http.ResponseWriter, r *http.Request) {
+	delivery, err := a.engine(r).Test(webhookID(r))
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, delivery)
+}
+
+func (a *WebhookRestAPI) createWebhook(w http.ResponseWriter, r *http.Request) {
+	a.createUpdate(w, r, true)
+}
+
+func (a *WebhookRestAPI) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	a.createUpdate(w, r, false)
+}
+
+func (a *WebhookRestAPI) createUpdate(w http.ResponseWriter, r *http.Request, create bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		ErrorResponse(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	req := &WebhookCreateRequest{}
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		ErrorResponse(w, r, errors.New("Bad request"), http.StatusBadRequest)
+		return
+	}
+	info := &api.WebhookInfo{
+		ID:          req.ID,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		EnvCodes:    req.EnvCodes,
+		ObjectCodes: req.ObjectCodes,
+	}
+	var hook *domain.Webhook
+	if create {
+		hook, err = a.engine(r).Create(info)
+	} else {
+		hook, err = a.engine(r).Update(info)
+	}
+	if err != nil {
+		a.errorResponse(w, r, err)
+		return
+	}
+	JSONResponse(w, r, hook)
+}
@@ -0,0 +1,216 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/webhook"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func TestRestWebhook(t *testing.T) {
+	assert := asserts.New(t)
+	BeforeTest()
+
+	var lock sync.Mutex
+	received := make([]string, 0)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		received = append(received, r.Header.Get(webhook.HeaderEvent))
+		lock.Unlock()
+	}))
+	defer receiver.Close()
+
+	dispatcher := webhook.NewDispatcher(&dataStorage, 1, time.Millisecond)
+	defer dispatcher.Close()
+	togglyAPI := &engine.Engine{Storage: &dataStorage, Events: dispatcher, Webhooks: dispatcher}
+	owAPI := togglyAPI.ForOwner(ow)
+	owAPI.Projects().Create(&api.ProjectInfo{Code: "project1", Status: domain.ProjectStatusActive})
+	owAPI.Projects().For("project1").Environments().Create(&api.EnvironmentInfo{Code: "env1"})
+	hook, err := owAPI.Projects().For("project1").Webhooks().Create(&api.WebhookInfo{
+		URL:    receiver.URL,
+		Events: []domain.EventType{domain.EventObjectCreate},
+	})
+	assert.Nil(err)
+	key, err := owAPI.Keys().Create(&api.KeyInfo{Role: domain.RoleEditor, ProjectCode: "project1"})
+	assert.Nil(err)
+	hookPath := "/api/v1/project/project1/webhook/" + string(hook.ID)
+
+	errorValidator := func(msg string) func(body []byte) {
+		return func(body []byte) {
+			var b map[string]interface{}
+			assert.Nil(parseBodyTo(body, &b))
+			assert.Equal(msg, b["error"])
+		}
+	}
+
+	tt := []TestCase{
+		{
+			name:   "list",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/webhook",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.Webhook
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 1)
+				assert.Equal(hook.ID, b[0].ID)
+				assert.Empty(b[0].Secret)
+			},
+		},
+		{
+			name:      "list but project not found",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project2/webhook",
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrProjectNotFound),
+		},
+		{
+			name:   "list with editor key",
+			method: http.MethodGet,
+			path:   "/api/v1/project/project1/webhook",
+			status: http.StatusForbidden,
+			patchRequest: func(req *http.Request) {
+				req.Header[rest.XTogglyAuth] = []string{key.Key}
+			},
+		},
+		{
+			name:   "create with wrong url",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/webhook",
+			body:   &rest.WebhookCreateRequest{URL: "example.com"},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/webhook",
+			body: &rest.WebhookCreateRequest{
+				URL:      "https://example.com/hook",
+				Secret:   "s1",
+				Events:   []domain.EventType{domain.EventObjectUpdate},
+				EnvCodes: []domain.EnvironmentCode{"env1"},
+			},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b *domain.Webhook
+				assert.Nil(parseBodyTo(body, &b))
+				assert.NotEmpty(b.ID)
+				assert.Equal("s1", b.Secret)
+				assert.Equal([]domain.EnvironmentCode{"env1"}, b.EnvCodes)
+			},
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			path:   hookPath,
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b *domain.Webhook
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Equal(receiver.URL, b.URL)
+				assert.Empty(b.Secret)
+			},
+		},
+		{
+			name:      "get not found",
+			method:    http.MethodGet,
+			path:      "/api/v1/project/project1/webhook/unknown",
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrWebhookNotFound),
+		},
+		{
+			name:   "update",
+			method: http.MethodPut,
+			path:   "/api/v1/project/project1/webhook",
+			body: &rest.WebhookCreateRequest{
+				ID:     hook.ID,
+				URL:    receiver.URL,
+				Events: []domain.EventType{domain.EventObjectCreate, domain.EventObjectDelete},
+			},
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b *domain.Webhook
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b.Events, 2)
+			},
+		},
+		{
+			name:   "test delivery",
+			method: http.MethodPost,
+			path:   hookPath + "/test",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b *domain.WebhookDelivery
+				assert.Nil(parseBodyTo(body, &b))
+				assert.True(b.Success)
+				assert.Equal(http.StatusOK, b.StatusCode)
+				assert.Equal(domain.EventPing, b.EventType)
+			},
+		},
+		{
+			name:   "object change is delivered",
+			method: http.MethodPost,
+			path:   "/api/v1/project/project1/env/env1/object",
+			body:   &rest.ObjectCreateRequest{Code: "obj1"},
+			status: http.StatusOK,
+			after: func(rs *httptest.Server) {
+				hooks := dataStorage.ForOwner(ow).Projects().For("project1").Webhooks()
+				deadline := time.Now().Add(5 * time.Second)
+				for time.Now().Before(deadline) {
+					if list, _ := hooks.ListDeliveries(hook.ID); len(list) == 2 {
+						break
+					}
+					time.Sleep(5 * time.Millisecond)
+				}
+				lock.Lock()
+				defer lock.Unlock()
+				assert.Equal([]string{string(domain.EventPing), string(domain.EventObjectCreate)}, received)
+			},
+		},
+		{
+			name:   "deliveries",
+			method: http.MethodGet,
+			path:   hookPath + "/deliveries",
+			status: http.StatusOK,
+			validator: func(body []byte) {
+				var b []*domain.WebhookDelivery
+				assert.Nil(parseBodyTo(body, &b))
+				assert.Len(b, 2)
+				assert.Equal(domain.EventPing, b[0].EventType)
+				assert.Equal(domain.EventObjectCreate, b[1].EventType)
+			},
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   hookPath,
+			status: http.StatusOK,
+		},
+		{
+			name:      "delete not found",
+			method:    http.MethodDelete,
+			path:      hookPath,
+			status:    http.StatusNotFound,
+			validator: errorValidator(rest.ErrWebhookNotFound),
+		},
+	}
+
+	router := GetRouter()
+	router.API = togglyAPI
+	rs := httptest.NewServer(router.Router())
+	defer rs.Close()
+
+	for _, tc := range tt {
+		runTestCase(t, rs, tc)
+	}
+
+	AfterTest()
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Toggly/core/pkg/domain"
+)
+
+var (
+	// ErrUnauthorized error
+	ErrUnauthorized = errors.New("Unauthorized")
+	// ErrForbidden error
+	ErrForbidden = errors.New("Forbidden")
+	// ErrKeyNotFound error
+	ErrKeyNotFound = errors.New("API key not found")
+	// ErrVersionConflict error
+	ErrVersionConflict = errors.New("Version conflict")
+
+	// ErrProjectNotFound error
+	ErrProjectNotFound = errors.New("Project not found")
+	// ErrProjectNotEmpty error
+	ErrProjectNotEmpty = errors.New("Project not empty")
+	// ErrProjectDisabled error
+	ErrProjectDisabled = errors.New("Project is disabled")
+
+	// ErrEnvironmentNotFound error
+	ErrEnvironmentNotFound = errors.New("Environment not found")
+	// ErrEnvironmentNotEmpty error
+	ErrEnvironmentNotEmpty = errors.New("Environment not empty")
+	// ErrEnvironmentProtected error
+	ErrEnvironmentProtected = errors.New("Environment is protected")
+
+	// ErrSegmentNotFound error
+	ErrSegmentNotFound = errors.New("Segment not found")
+	// ErrSegmentInUse error
+	ErrSegmentInUse = errors.New("Segment is in use")
+
+	// ErrObjectNotFound error
+	ErrObjectNotFound = errors.New("Object not found")
+	// ErrObjectHasInheritors error
+	ErrObjectHasInheritors = errors.New("Object has inheritors")
+	// ErrObjectParentNotExists error
+	ErrObjectParentNotExists = errors.New("Object parrent does not exists")
+	// ErrObjectInheritorTypeMismatch error
+	ErrObjectInheritorTypeMismatch = errors.New("Object inheritor parameter type mismatch")
+	// ErrRevisionNotFound error
+	ErrRevisionNotFound = errors.New("Revision not found")
+
+	// ErrWebhookNotFound error
+	ErrWebhookNotFound = errors.New("Webhook not found")
+)
+
+// ErrBadRequest type
+type ErrBadRequest struct {
+	Description string
+}
+
+func (e *ErrBadRequest) Error() string {
+	return fmt.Sprintf("Bad request: %s", e.Description)
+}
+
+// NewBadRequestError returns ErrBadRequest error
+func NewBadRequestError(description string) *ErrBadRequest {
+	return &ErrBadRequest{Description: description}
+}
+
+// ErrObjectParameter type
+type ErrObjectParameter struct {
+	Name        string
+	Description string
+}
+
+func (e *ErrObjectParameter) Error() string {
+	return fmt.Sprintf("Object parameter `%s` error: %s", e.Name, e.Description)
+}
+
+// NewObjectParameterError returns ErrBadRequest error
+func NewObjectParameterError(name, description string) *ErrObjectParameter {
+	return &ErrObjectParameter{
+		Name:        name,
+		Description: description,
+	}
+}
+
+// TogglyAPI interface. WithActor returns API recording changes on behalf of the actor,
+// WithRequestID returns API recording changes as made by the request.
+type TogglyAPI interface {
+	ForOwner(owner string) OwnerAPI
+	Authenticate(key string) (*domain.APIKey, error)
+	WithActor(actor string) TogglyAPI
+	WithRequestID(requestID string) TogglyAPI
+}
+
+// OwnerAPI interface
+type OwnerAPI interface {
+	Projects() ProjectAPI
+	Keys() KeyAPI
+	Audit() AuditAPI
+}
+
+// AuditAPI interface
+type AuditAPI interface {
+	List(filter *domain.AuditFilter) ([]*domain.AuditRecord, error)
+}
+
+// KeyInfo type
+type KeyInfo struct {
+	Description     string
+	Role            domain.Role
+	ProjectCode     domain.ProjectCode
+	EnvironmentCode domain.EnvironmentCode
+}
+
+// KeyAPI interface
+type KeyAPI interface {
+	List() ([]*domain.APIKey, error)
+	Get(id domain.APIKeyID) (*domain.APIKey, error)
+	Create(info *KeyInfo) (*domain.APIKey, error)
+	Revoke(id domain.APIKeyID) error
+}
+
+// ProjectInfo type. Non-zero Version is the expected current project version.
+type ProjectInfo struct {
+	Code        domain.ProjectCode
+	Description string
+	Status      domain.ProjectStatus
+	Version     int64
+}
+
+// ProjectAPI interface. Import creates or updates entities of the document, dry run checks the document
+// and returns the same changes without applying them. Objects of protected environments are changed by import
+// with override only.
+type ProjectAPI interface {
+	List() ([]*domain.Project, error)
+	Get(code domain.ProjectCode) (*domain.Project, error)
+	Create(info *ProjectInfo) (*domain.Project, error)
+	Update(info *ProjectInfo) (*domain.Project, error)
+	Delete(code domain.ProjectCode) error
+	Export(code domain.ProjectCode) (*domain.Document, error)
+	Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error)
+	For(code domain.ProjectCode) ForProjectAPI
+}
+
+// ForProjectAPI interface
+type ForProjectAPI interface {
+	Environments() EnvironmentAPI
+	Segments() SegmentAPI
+	Webhooks() WebhookAPI
+}
+
+// WebhookInfo type. ID is ignored on creation, empty Secret is generated on creation
+// and kept on update.
+type WebhookInfo struct {
+	ID          domain.WebhookID
+	URL         string
+	Secret      string
+	Events      []domain.EventType
+	EnvCodes    []domain.EnvironmentCode
+	ObjectCodes []domain.ObjectCode
+}
+
+// WebhookAPI interface
+type WebhookAPI interface {
+	List() ([]*domain.Webhook, error)
+	Get(id domain.WebhookID) (*domain.Webhook, error)
+	Create(info *WebhookInfo) (*domain.Webhook, error)
+	Update(info *WebhookInfo) (*domain.Webhook, error)
+	Delete(id domain.WebhookID) error
+	Deliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error)
+	Test(id domain.WebhookID) (*domain.WebhookDelivery, error)
+}
+
+// SegmentInfo type
+type SegmentInfo struct {
+	Code        domain.SegmentCode
+	Description string
+	Include     []string
+	Exclude     []string
+	Rules       []*domain.SegmentRule
+}
+
+// SegmentAPI interface
+type SegmentAPI interface {
+	List() ([]*domain.Segment, error)
+	Get(code domain.SegmentCode) (*domain.Segment, error)
+	Create(info *SegmentInfo) (*domain.Segment, error)
+	Update(info *SegmentInfo) (*domain.Segment, error)
+	Delete(code domain.SegmentCode) error
+}
+
+// EnvironmentInfo type. Non-zero Version is the expected current environment version.
+type EnvironmentInfo struct {
+	Code        domain.EnvironmentCode
+	Description string
+	Protected   bool
+	Version     int64
+}
+
+// EnvironmentAPI interface
+type EnvironmentAPI interface {
+	List() ([]*domain.Environment, error)
+	Get(code domain.EnvironmentCode) (*domain.Environment, error)
+	Create(info *EnvironmentInfo) (*domain.Environment, error)
+	Update(info *EnvironmentInfo) (*domain.Environment, error)
+	Delete(code domain.EnvironmentCode) error
+	For(code domain.EnvironmentCode) ForObjectAPI
+}
+
+// ForObjectAPI interface
+type ForObjectAPI interface {
+	Objects() ObjectAPI
+	OverrideProtection() ForObjectAPI
+}
+
+// ObjectInfo type. Non-zero Version is the expected current object version.
+type ObjectInfo struct {
+	Code        domain.ObjectCode
+	Description string
+	Inherits    *domain.ObjectInheritance
+	Parameters  []*domain.Parameter
+	Version     int64
+}
+
+// ObjectAPI interface. Non-zero version of Delete is the expected current object version.
+type ObjectAPI interface {
+	List() ([]*domain.Object, error)
+	Get(code domain.ObjectCode) (*domain.Object, error)
+	Create(info *ObjectInfo) (*domain.Object, error)
+	Update(info *ObjectInfo) (*domain.Object, error)
+	Delete(code domain.ObjectCode, version int64) error
+	InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error)
+	History(code domain.ObjectCode) ([]*domain.ObjectRevision, error)
+	Revert(code domain.ObjectCode, revision int64) (*domain.Object, error)
+	Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error)
+	EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error)
+}
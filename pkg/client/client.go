@@ -0,0 +1,225 @@
+// Package client is the Go client of Toggly REST API.
+// Client implements project, environment and object API interfaces of the server engine,
+// Reader keeps an in-memory snapshot of environment objects up to date.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/Toggly/core/pkg/api"
+)
+
+// Request headers
+const (
+	HeaderAuth     = "X-Toggly-Auth"
+	HeaderOwnerID  = "X-Toggly-Owner-Id"
+	HeaderOverride = "X-Toggly-Override-Protection"
+)
+
+// Error is returned for unsuccessful responses which don't match any API error
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("Toggly API error %d: %s", e.StatusCode, e.Message)
+}
+
+// apiErrors are restored from response messages
+var apiErrors = []error{
+	api.ErrProjectNotFound,
+	api.ErrProjectNotEmpty,
+	api.ErrProjectDisabled,
+	api.ErrEnvironmentNotFound,
+	api.ErrEnvironmentNotEmpty,
+	api.ErrEnvironmentProtected,
+	api.ErrSegmentNotFound,
+	api.ErrSegmentInUse,
+	api.ErrObjectNotFound,
+	api.ErrObjectHasInheritors,
+	api.ErrObjectParentNotExists,
+	api.ErrObjectInheritorTypeMismatch,
+	api.ErrRevisionNotFound,
+	api.ErrWebhookNotFound,
+}
+
+// parameterError matches message of api.ErrObjectParameter
+var parameterError = regexp.MustCompile("^Object parameter `(.*)` error: (.*)$")
+
+// Client calls Toggly REST API. BaseURL includes base path and API version, e.g. `http://localhost:8080/api/v1`.
+// Owner is required for the master key only.
+type Client struct {
+	BaseURL    string
+	Key        string
+	Owner      string
+	HTTPClient *http.Client
+	lock       sync.Mutex
+	tags       map[string]string
+}
+
+// New returns client authenticated by the API key
+func New(baseURL, key string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Key: key, HTTPClient: http.DefaultClient}
+}
+
+// Projects returns project API
+func (c *Client) Projects() api.ProjectAPI {
+	return &projectAPI{client: c}
+}
+
+// path joins escaped path segments
+func path(segments ...interface{}) string {
+	parts := make([]string, len(segments))
+	for i, s := range segments {
+		parts[i] = url.PathEscape(fmt.Sprint(s))
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// request describes API call, nil out skips response decoding, non-nil tag receives ETag of the response
+type request struct {
+	method  string
+	path    string
+	body    interface{}
+	out     interface{}
+	tag     *string
+	headers map[string]string
+}
+
+func (c *Client) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(HeaderAuth, c.Key)
+	if c.Owner != "" {
+		req.Header.Set(HeaderOwnerID, c.Owner)
+	}
+	return req, nil
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) do(r *request) error {
+	var body io.Reader
+	if r.body != nil {
+		data, err := json.Marshal(r.body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := c.newRequest(r.method, r.path, body)
+	if err != nil {
+		return err
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(resp.StatusCode, data)
+	}
+	if r.tag != nil {
+		*r.tag = resp.Header.Get("ETag")
+	}
+	if r.out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, r.out)
+}
+
+// responseError restores API error from the response
+func responseError(status int, body []byte) error {
+	switch status {
+	case http.StatusUnauthorized:
+		return api.ErrUnauthorized
+	case http.StatusForbidden:
+		return api.ErrForbidden
+	case http.StatusPreconditionFailed:
+		return api.ErrVersionConflict
+	}
+	res := struct {
+		Error string `json:"error"`
+	}{}
+	if err := json.Unmarshal(body, &res); err != nil || res.Error == "" {
+		return &Error{StatusCode: status, Message: strings.TrimSpace(string(body))}
+	}
+	for _, e := range apiErrors {
+		if e.Error() == res.Error {
+			return e
+		}
+	}
+	if status == http.StatusBadRequest {
+		if strings.HasPrefix(res.Error, "Bad request: ") {
+			return api.NewBadRequestError(strings.TrimPrefix(res.Error, "Bad request: "))
+		}
+		if m := parameterError.FindStringSubmatch(res.Error); m != nil {
+			return api.NewObjectParameterError(m[1], m[2])
+		}
+	}
+	return &Error{StatusCode: status, Message: res.Error}
+}
+
+// ifMatch returns If-Match header for non-zero version
+func ifMatch(version int64) map[string]string {
+	if version == 0 {
+		return nil
+	}
+	return map[string]string{"If-Match": fmt.Sprintf(`"%d"`, version)}
+}
+
+// setTag keeps the last entity tag of the resource, empty tag removes it
+func (c *Client) setTag(path, tag string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if tag == "" {
+		delete(c.tags, path)
+		return
+	}
+	if c.tags == nil {
+		c.tags = make(map[string]string)
+	}
+	c.tags[path] = tag
+}
+
+// ifMatchTag returns If-Match header with the kept entity tag of the resource if the tag has the version.
+// Inheritor object tag changes with parents, so the version alone matches only objects without parents.
+func (c *Client) ifMatchTag(path string, version int64) map[string]string {
+	if version == 0 {
+		return nil
+	}
+	c.lock.Lock()
+	tag := c.tags[path]
+	c.lock.Unlock()
+	if strings.HasPrefix(tag, fmt.Sprintf(`"%d-`, version)) {
+		return map[string]string{"If-Match": tag}
+	}
+	return ifMatch(version)
+}
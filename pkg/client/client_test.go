@@ -0,0 +1,226 @@
+package client_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/internal/pkg/engine"
+	"github.com/Toggly/core/internal/pkg/eventbus"
+	"github.com/Toggly/core/internal/pkg/storage"
+	"github.com/Toggly/core/internal/pkg/storage/memory"
+	"github.com/Toggly/core/internal/server/rest"
+	"github.com/Toggly/core/pkg/client"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+const masterKey = "TestToken"
+
+var (
+	_ client.ProjectAPI     = (&client.Client{}).Projects()
+	_ client.EnvironmentAPI = (&client.Client{}).Projects().For("p").Environments()
+	_ client.ObjectAPI      = (&client.Client{}).Projects().For("p").Environments().For("e").Objects()
+)
+
+// newServer returns test server keeping history of the events for streams
+func newServer(history int) (*httptest.Server, *client.Client) {
+	var dataStorage storage.DataStorage = memory.NewMemoryStorage()
+	bus := eventbus.New(history, 100)
+	router := &rest.APIRouter{
+		Version:   "test",
+		API:       &engine.Engine{Storage: &dataStorage, Events: bus, DryRunStorage: memory.NewMemoryStorage},
+		Events:    bus,
+		BasePath:  "/api",
+		MasterKey: masterKey,
+	}
+	rs := httptest.NewServer(router.Router())
+	c := client.New(rs.URL+"/api/v1", masterKey)
+	c.Owner = "ow1"
+	return rs, c
+}
+
+// eventually waits for the condition to become true
+func eventually(cond func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func boolParam(value bool) []*client.Parameter {
+	return []*client.Parameter{{Code: "enabled", Type: client.ParameterBool, Value: value}}
+}
+
+func TestProjects(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(100)
+	defer rs.Close()
+
+	projects := c.Projects()
+	proj, err := projects.Create(&client.ProjectInfo{Code: "project1", Description: "Description", Status: client.ProjectStatusActive})
+	assert.Nil(err)
+	assert.Equal(client.ProjectCode("project1"), proj.Code)
+	assert.Equal("ow1", proj.OwnerID)
+
+	_, err = projects.Update(&client.ProjectInfo{Code: "project1", Description: "New", Status: client.ProjectStatusActive, Version: proj.Version + 1})
+	assert.Equal(client.ErrVersionConflict, err)
+	proj, err = projects.Update(&client.ProjectInfo{Code: "project1", Description: "New", Status: client.ProjectStatusActive, Version: proj.Version})
+	assert.Nil(err)
+	assert.Equal("New", proj.Description)
+
+	list, err := projects.List()
+	assert.Nil(err)
+	assert.Len(list, 1)
+	_, err = projects.Get("project2")
+	assert.Equal(client.ErrProjectNotFound, err)
+
+	env, err := projects.For("project1").Environments().Create(&client.EnvironmentInfo{Code: "env1", Protected: true})
+	assert.Nil(err)
+	assert.True(env.Protected)
+	envs, err := projects.For("project1").Environments().List()
+	assert.Nil(err)
+	assert.Len(envs, 1)
+	assert.Equal(client.ErrProjectNotEmpty, projects.Delete("project1"))
+
+	seg, err := projects.For("project1").Segments().Create(&client.SegmentInfo{Code: "beta", Include: []string{"user1"}})
+	assert.Nil(err)
+	assert.Equal([]string{"user1"}, seg.Include)
+	_, err = projects.For("project1").Segments().Get("alpha")
+	assert.Equal(client.ErrSegmentNotFound, err)
+
+	hook, err := projects.For("project1").Webhooks().Create(&client.WebhookInfo{URL: "http://localhost/hook"})
+	assert.Nil(err)
+	assert.NotEmpty(hook.Secret)
+	hooks, err := projects.For("project1").Webhooks().List()
+	assert.Nil(err)
+	assert.Len(hooks, 1)
+	assert.Empty(hooks[0].Secret)
+
+	unauthorized := client.New(rs.URL+"/api/v1", "wrong")
+	_, err = unauthorized.Projects().List()
+	assert.Equal(client.ErrUnauthorized, err)
+}
+
+func TestObjects(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(100)
+	defer rs.Close()
+
+	c.Projects().Create(&client.ProjectInfo{Code: "project1", Status: client.ProjectStatusActive})
+	envs := c.Projects().For("project1").Environments()
+	envs.Create(&client.EnvironmentInfo{Code: "env1"})
+	envs.Create(&client.EnvironmentInfo{Code: "env2", Protected: true})
+	objects := envs.For("env1").Objects()
+
+	obj, err := objects.Create(&client.ObjectInfo{Code: "obj1", Parameters: []*client.Parameter{
+		{Code: "enabled", Type: client.ParameterBool, Value: false},
+		{Code: "limit", Type: client.ParameterInt, Value: 10},
+	}})
+	assert.Nil(err)
+	assert.Equal(int64(10), obj.Parameters[1].Value)
+
+	_, err = objects.Create(&client.ObjectInfo{Code: "obj2", Parameters: []*client.Parameter{
+		{Code: "limit", Type: client.ParameterInt, Value: "ten"},
+	}})
+	_, ok := err.(*client.ErrObjectParameter)
+	assert.True(ok, "%v", err)
+
+	obj, err = objects.Update(&client.ObjectInfo{Code: "obj1", Parameters: boolParam(true), Version: obj.Version})
+	assert.Nil(err)
+	obj, err = objects.Get("obj1")
+	assert.Nil(err)
+	assert.Equal(true, obj.Parameters[0].Value)
+
+	history, err := objects.History("obj1")
+	assert.Nil(err)
+	assert.Len(history, 2)
+	obj, err = objects.Revert("obj1", 1)
+	assert.Nil(err)
+	assert.Len(obj.Parameters, 2)
+
+	_, err = objects.Create(&client.ObjectInfo{Code: "base", Parameters: boolParam(false)})
+	assert.Nil(err)
+	inheritor := &client.ObjectInfo{Code: "inheritor", Inherits: &client.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "base"}}
+	_, err = objects.Create(inheritor)
+	assert.Nil(err)
+	read, err := objects.Get("inheritor")
+	assert.Nil(err)
+	inheritor.Version = read.Version
+	read, err = objects.Update(inheritor)
+	assert.Nil(err, "entity tag of the read inheritor is sent")
+	_, err = objects.Update(&client.ObjectInfo{Code: "base", Parameters: boolParam(true)})
+	assert.Nil(err)
+	inheritor.Version = read.Version
+	_, err = objects.Update(inheritor)
+	assert.Equal(client.ErrVersionConflict, err, "inheritor is changed by parent")
+	read, err = objects.Get("inheritor")
+	assert.Nil(err)
+	inheritor.Version = read.Version
+	_, err = objects.Update(inheritor)
+	assert.Nil(err)
+
+	values, err := objects.Evaluate("obj1", &client.EvaluationContext{UserID: "user1"})
+	assert.Nil(err)
+	assert.Equal(false, values["enabled"])
+	all, err := objects.EvaluateAll(&client.EvaluationContext{})
+	assert.Nil(err)
+	assert.Contains(all, client.ObjectCode("obj1"))
+
+	child := &client.ObjectInfo{Code: "child", Inherits: &client.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "obj1"}}
+	_, err = envs.For("env2").Objects().Create(child)
+	assert.Equal(client.ErrEnvironmentProtected, err)
+	_, err = envs.For("env2").OverrideProtection().Objects().Create(child)
+	assert.Nil(err)
+	inheritors, err := objects.InheritorsFlatList("obj1")
+	assert.Nil(err)
+	assert.Len(inheritors, 1)
+	assert.Equal(client.ErrObjectHasInheritors, objects.Delete("obj1", 0))
+
+	assert.Nil(envs.For("env2").OverrideProtection().Objects().Delete("child", 0))
+	assert.Nil(objects.Delete("obj1", 0))
+	_, err = objects.Get("obj1")
+	assert.Equal(client.ErrObjectNotFound, err)
+	_, err = envs.For("env3").Objects().List()
+	assert.Equal(client.ErrEnvironmentNotFound, err)
+}
+
+func TestTransfer(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(0)
+	defer rs.Close()
+	projects := c.Projects()
+
+	doc := &client.Document{
+		Version: 1,
+		Project: &client.DocumentProject{Code: "p1", Status: client.ProjectStatusActive},
+		Environments: []*client.DocumentEnvironment{{
+			Code:      "dev",
+			Protected: true,
+			Objects:   []*client.DocumentObject{{Code: "obj1", Parameters: boolParam(true)}},
+		}},
+	}
+	_, err := projects.Import(doc, true, false)
+	assert.Equal(client.ErrEnvironmentProtected, err)
+	changes, err := projects.Import(doc, true, true)
+	assert.Nil(err)
+	assert.Len(changes, 3)
+	_, err = projects.Export("p1")
+	assert.Equal(client.ErrProjectNotFound, err)
+
+	changes, err = projects.Import(doc, false, true)
+	assert.Nil(err)
+	assert.Equal(&client.ImportChange{Entity: "object", EnvCode: "dev", Code: "obj1", Action: client.ImportCreate}, changes[2])
+
+	exported, err := projects.Export("p1")
+	assert.Nil(err)
+	assert.Equal(true, exported.Environments[0].Objects[0].Parameters[0].Value)
+
+	doc.Version = 2
+	_, err = projects.Import(doc, false, false)
+	assert.IsType(&client.ErrBadRequest{}, err)
+	_, err = projects.Import(&client.Document{}, false, false)
+	assert.IsType(&client.ErrBadRequest{}, err)
+}
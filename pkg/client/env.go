@@ -0,0 +1,85 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type environmentRequest struct {
+	Code        domain.EnvironmentCode `json:"code"`
+	Description string                 `json:"description"`
+	Protected   bool                   `json:"protected"`
+}
+
+type environmentAPI struct {
+	client      *Client
+	projectCode domain.ProjectCode
+}
+
+func (a *environmentAPI) path(segments ...interface{}) string {
+	return path(append([]interface{}{"project", a.projectCode, "env"}, segments...)...)
+}
+
+func (a *environmentAPI) List() ([]*domain.Environment, error) {
+	list := make([]*domain.Environment, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *environmentAPI) Get(code domain.EnvironmentCode) (*domain.Environment, error) {
+	env := &domain.Environment{}
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(code), out: env}); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (a *environmentAPI) Create(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	return a.save(http.MethodPost, info)
+}
+
+func (a *environmentAPI) Update(info *api.EnvironmentInfo) (*domain.Environment, error) {
+	return a.save(http.MethodPut, info)
+}
+
+func (a *environmentAPI) save(method string, info *api.EnvironmentInfo) (*domain.Environment, error) {
+	env := &domain.Environment{}
+	err := a.client.do(&request{
+		method:  method,
+		path:    a.path(),
+		body:    &environmentRequest{Code: info.Code, Description: info.Description, Protected: info.Protected},
+		out:     env,
+		headers: ifMatch(info.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+func (a *environmentAPI) Delete(code domain.EnvironmentCode) error {
+	return a.client.do(&request{method: http.MethodDelete, path: a.path(code)})
+}
+
+func (a *environmentAPI) For(code domain.EnvironmentCode) api.ForObjectAPI {
+	return &forObjectAPI{client: a.client, projectCode: a.projectCode, envCode: code}
+}
+
+type forObjectAPI struct {
+	client             *Client
+	projectCode        domain.ProjectCode
+	envCode            domain.EnvironmentCode
+	overrideProtection bool
+}
+
+func (a *forObjectAPI) Objects() api.ObjectAPI {
+	return &objectAPI{client: a.client, projectCode: a.projectCode, envCode: a.envCode, overrideProtection: a.overrideProtection}
+}
+
+func (a *forObjectAPI) OverrideProtection() api.ForObjectAPI {
+	return &forObjectAPI{client: a.client, projectCode: a.projectCode, envCode: a.envCode, overrideProtection: true}
+}
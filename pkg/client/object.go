@@ -0,0 +1,143 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type objectRequest struct {
+	Code        domain.ObjectCode         `json:"code"`
+	Description string                    `json:"description"`
+	Inherits    *domain.ObjectInheritance `json:"inherits"`
+	Parameters  []*domain.Parameter       `json:"parameters"`
+}
+
+type objectAPI struct {
+	client             *Client
+	projectCode        domain.ProjectCode
+	envCode            domain.EnvironmentCode
+	overrideProtection bool
+}
+
+func (a *objectAPI) path(segments ...interface{}) string {
+	return path(append([]interface{}{"project", a.projectCode, "env", a.envCode, "object"}, segments...)...)
+}
+
+// change adds protection override header to changing request
+func (a *objectAPI) change(r *request) error {
+	if a.overrideProtection {
+		if r.headers == nil {
+			r.headers = make(map[string]string)
+		}
+		r.headers[HeaderOverride] = "true"
+	}
+	return a.client.do(r)
+}
+
+func (a *objectAPI) List() ([]*domain.Object, error) {
+	list := make([]*domain.Object, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *objectAPI) Get(code domain.ObjectCode) (*domain.Object, error) {
+	obj := &domain.Object{}
+	var tag string
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(code), out: obj, tag: &tag}); err != nil {
+		return nil, err
+	}
+	a.client.setTag(a.path(code), tag)
+	return obj, nil
+}
+
+func (a *objectAPI) Create(info *api.ObjectInfo) (*domain.Object, error) {
+	return a.save(http.MethodPost, info)
+}
+
+func (a *objectAPI) Update(info *api.ObjectInfo) (*domain.Object, error) {
+	return a.save(http.MethodPut, info)
+}
+
+func (a *objectAPI) save(method string, info *api.ObjectInfo) (*domain.Object, error) {
+	obj := &domain.Object{}
+	var tag string
+	err := a.change(&request{
+		method: method,
+		path:   a.path(),
+		body: &objectRequest{
+			Code:        info.Code,
+			Description: info.Description,
+			Inherits:    info.Inherits,
+			Parameters:  info.Parameters,
+		},
+		out:     obj,
+		tag:     &tag,
+		headers: a.client.ifMatchTag(a.path(info.Code), info.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.client.setTag(a.path(info.Code), tag)
+	return obj, nil
+}
+
+func (a *objectAPI) Delete(code domain.ObjectCode, version int64) error {
+	r := &request{method: http.MethodDelete, path: a.path(code), headers: a.client.ifMatchTag(a.path(code), version)}
+	if err := a.change(r); err != nil {
+		return err
+	}
+	a.client.setTag(a.path(code), "")
+	return nil
+}
+
+func (a *objectAPI) InheritorsFlatList(code domain.ObjectCode) ([]*domain.Object, error) {
+	list := make([]*domain.Object, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(code, "inheritors"), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *objectAPI) History(code domain.ObjectCode) ([]*domain.ObjectRevision, error) {
+	list := make([]*domain.ObjectRevision, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(code, "history"), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *objectAPI) Revert(code domain.ObjectCode, revision int64) (*domain.Object, error) {
+	obj := &domain.Object{}
+	var tag string
+	if err := a.change(&request{method: http.MethodPost, path: a.path(code, "revert", revision), out: obj, tag: &tag}); err != nil {
+		return nil, err
+	}
+	a.client.setTag(a.path(code), tag)
+	return obj, nil
+}
+
+func (a *objectAPI) Evaluate(code domain.ObjectCode, ctx *domain.EvaluationContext) (domain.ObjectValues, error) {
+	values := make(domain.ObjectValues)
+	if err := a.client.do(&request{method: http.MethodPost, path: a.path(code, "evaluate"), body: ctx, out: &values}); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (a *objectAPI) EvaluateAll(ctx *domain.EvaluationContext) (domain.EnvironmentValues, error) {
+	values := make(domain.EnvironmentValues)
+	err := a.client.do(&request{
+		method: http.MethodPost,
+		path:   path("project", a.projectCode, "env", a.envCode, "evaluate"),
+		body:   ctx,
+		out:    &values,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
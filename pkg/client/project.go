@@ -0,0 +1,109 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type projectRequest struct {
+	Code        domain.ProjectCode   `json:"code"`
+	Description string               `json:"description"`
+	Status      domain.ProjectStatus `json:"status"`
+}
+
+type projectAPI struct {
+	client *Client
+}
+
+func (a *projectAPI) List() ([]*domain.Project, error) {
+	list := make([]*domain.Project, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: path("project"), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *projectAPI) Get(code domain.ProjectCode) (*domain.Project, error) {
+	proj := &domain.Project{}
+	if err := a.client.do(&request{method: http.MethodGet, path: path("project", code), out: proj}); err != nil {
+		return nil, err
+	}
+	return proj, nil
+}
+
+func (a *projectAPI) Create(info *api.ProjectInfo) (*domain.Project, error) {
+	return a.save(http.MethodPost, info)
+}
+
+func (a *projectAPI) Update(info *api.ProjectInfo) (*domain.Project, error) {
+	return a.save(http.MethodPut, info)
+}
+
+func (a *projectAPI) save(method string, info *api.ProjectInfo) (*domain.Project, error) {
+	proj := &domain.Project{}
+	err := a.client.do(&request{
+		method:  method,
+		path:    path("project"),
+		body:    &projectRequest{Code: info.Code, Description: info.Description, Status: info.Status},
+		out:     proj,
+		headers: ifMatch(info.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proj, nil
+}
+
+func (a *projectAPI) Delete(code domain.ProjectCode) error {
+	return a.client.do(&request{method: http.MethodDelete, path: path("project", code)})
+}
+
+func (a *projectAPI) Export(code domain.ProjectCode) (*domain.Document, error) {
+	doc := &domain.Document{}
+	if err := a.client.do(&request{method: http.MethodGet, path: path("project", code, "export"), out: doc}); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (a *projectAPI) Import(doc *domain.Document, dryRun, override bool) ([]*domain.ImportChange, error) {
+	if doc.Project == nil {
+		return nil, api.NewBadRequestError("Project is not defined")
+	}
+	p := path("project", doc.Project.Code, "import")
+	if dryRun {
+		p += "?dry_run=true"
+	}
+	changes := make([]*domain.ImportChange, 0)
+	r := &request{method: http.MethodPost, path: p, body: doc, out: &changes}
+	if override {
+		r.headers = map[string]string{HeaderOverride: "true"}
+	}
+	if err := a.client.do(r); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (a *projectAPI) For(code domain.ProjectCode) api.ForProjectAPI {
+	return &forProjectAPI{client: a.client, projectCode: code}
+}
+
+type forProjectAPI struct {
+	client      *Client
+	projectCode domain.ProjectCode
+}
+
+func (a *forProjectAPI) Environments() api.EnvironmentAPI {
+	return &environmentAPI{client: a.client, projectCode: a.projectCode}
+}
+
+func (a *forProjectAPI) Segments() api.SegmentAPI {
+	return &segmentAPI{client: a.client, projectCode: a.projectCode}
+}
+
+func (a *forProjectAPI) Webhooks() api.WebhookAPI {
+	return &webhookAPI{client: a.client, projectCode: a.projectCode}
+}
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// defaultRetryDelay is used if ReaderOptions.RetryDelay is not set
+const defaultRetryDelay = 5 * time.Second
+
+// errStreamClosed is reported when server closes the stream
+var errStreamClosed = errors.New("Stream closed")
+
+// ReaderOptions configures Reader. Zero Poll interval keeps snapshot up to date by the environment stream,
+// otherwise objects are reloaded with the interval. RetryDelay is the delay before reconnecting the lost stream.
+// Background refresh errors are passed to OnError.
+type ReaderOptions struct {
+	Poll       time.Duration
+	RetryDelay time.Duration
+	OnError    func(err error)
+}
+
+// Reader keeps in-memory snapshot of environment objects. Getters return parameter values of the snapshot,
+// rules and rollouts are not applied, use object evaluation API for values of the particular user.
+type Reader struct {
+	client      *Client
+	projectCode domain.ProjectCode
+	envCode     domain.EnvironmentCode
+	opts        ReaderOptions
+	lock        sync.RWMutex
+	objects     map[domain.ObjectCode]*domain.Object
+	lastID      string
+	ctx         context.Context
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewReader loads objects of the environment and refreshes them in background until the reader is closed
+func (c *Client) NewReader(project domain.ProjectCode, env domain.EnvironmentCode, opts ReaderOptions) (*Reader, error) {
+	if opts.RetryDelay == 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Reader{
+		client:      c,
+		projectCode: project,
+		envCode:     env,
+		opts:        opts,
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	if opts.Poll > 0 {
+		if err := r.load(); err != nil {
+			cancel()
+			return nil, err
+		}
+		go r.poll()
+		return r, nil
+	}
+	// stream is connected first, so changes made while objects are loading are not missed
+	resp, err := r.connect()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err = r.load(); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+	go r.follow(resp)
+	return r, nil
+}
+
+// Close stops background refresh
+func (r *Reader) Close() {
+	r.cancel()
+	<-r.done
+}
+
+// Object returns object of the snapshot, returned object must not be modified
+func (r *Reader) Object(code domain.ObjectCode) (*domain.Object, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	obj, ok := r.objects[code]
+	return obj, ok
+}
+
+// Value returns parameter value of the object
+func (r *Reader) Value(object domain.ObjectCode, param domain.ParameterCode) (interface{}, bool) {
+	obj, ok := r.Object(object)
+	if !ok {
+		return nil, false
+	}
+	for _, p := range obj.Parameters {
+		if p.Code == param {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Bool returns value of bool parameter, def is returned if parameter is missing or has another type
+func (r *Reader) Bool(object domain.ObjectCode, param domain.ParameterCode, def bool) bool {
+	if v, ok := r.Value(object, param); ok {
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	}
+	return def
+}
+
+// String returns value of string, enum or semver parameter, def is returned if parameter is missing or has another type
+func (r *Reader) String(object domain.ObjectCode, param domain.ParameterCode, def string) string {
+	if v, ok := r.Value(object, param); ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return def
+}
+
+// Int returns value of int parameter, def is returned if parameter is missing or has another type
+func (r *Reader) Int(object domain.ObjectCode, param domain.ParameterCode, def int64) int64 {
+	if v, ok := r.Value(object, param); ok {
+		if i, ok := v.(int64); ok {
+			return i
+		}
+	}
+	return def
+}
+
+// Float returns value of float parameter, def is returned if parameter is missing or has another type
+func (r *Reader) Float(object domain.ObjectCode, param domain.ParameterCode, def float64) float64 {
+	if v, ok := r.Value(object, param); ok {
+		if f, ok := v.(float64); ok {
+			return f
+		}
+	}
+	return def
+}
+
+// Duration returns value of duration parameter, def is returned if parameter is missing or has another type
+func (r *Reader) Duration(object domain.ObjectCode, param domain.ParameterCode, def time.Duration) time.Duration {
+	if v, ok := r.Value(object, param); ok {
+		if s, ok := v.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return d
+			}
+		}
+	}
+	return def
+}
+
+// List returns value of list parameter, def is returned if parameter is missing or has another type
+func (r *Reader) List(object domain.ObjectCode, param domain.ParameterCode, def []string) []string {
+	if v, ok := r.Value(object, param); ok {
+		if l, ok := v.([]string); ok {
+			return l
+		}
+	}
+	return def
+}
+
+func (r *Reader) objectAPI() *objectAPI {
+	return &objectAPI{client: r.client, projectCode: r.projectCode, envCode: r.envCode}
+}
+
+// load replaces the snapshot with objects of the environment
+func (r *Reader) load() error {
+	list, err := r.objectAPI().List()
+	if err != nil {
+		return err
+	}
+	objects := make(map[domain.ObjectCode]*domain.Object, len(list))
+	for _, obj := range list {
+		objects[obj.Code] = obj
+	}
+	r.lock.Lock()
+	r.objects = objects
+	r.lock.Unlock()
+	return nil
+}
+
+func (r *Reader) report(err error) {
+	if r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// wait returns false if the reader is closed during the delay
+func (r *Reader) wait(delay time.Duration) bool {
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+func (r *Reader) poll() {
+	defer close(r.done)
+	for r.wait(r.opts.Poll) {
+		if err := r.load(); err != nil {
+			r.report(err)
+		}
+	}
+}
+
+// connect opens the environment stream resuming from the last received event
+func (r *Reader) connect() (*http.Response, error) {
+	req, err := r.client.newRequest(http.MethodGet, path("project", r.projectCode, "env", r.envCode, "stream"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(r.ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	if r.lastID != "" {
+		req.Header.Set("Last-Event-ID", r.lastID)
+	}
+	resp, err := r.client.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, responseError(resp.StatusCode, body)
+	}
+	return resp, nil
+}
+
+// follow applies stream events and reconnects lost stream until the reader is closed.
+// Objects are reloaded if the stream can't be resumed.
+func (r *Reader) follow(resp *http.Response) {
+	defer close(r.done)
+	for {
+		err := r.read(resp.Body)
+		resp.Body.Close()
+		if r.ctx.Err() != nil {
+			return
+		}
+		r.report(err)
+		for {
+			if !r.wait(r.opts.RetryDelay) {
+				return
+			}
+			resumed := r.lastID != ""
+			if resp, err = r.connect(); err != nil {
+				r.report(err)
+				continue
+			}
+			if !resumed {
+				if err = r.load(); err != nil {
+					resp.Body.Close()
+					r.report(err)
+					continue
+				}
+			}
+			break
+		}
+	}
+}
+
+// read applies server-sent events until the stream is broken
+func (r *Reader) read(body io.Reader) error {
+	reader := bufio.NewReader(body)
+	var id, event, data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return errStreamClosed
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if event == "" {
+				continue
+			}
+			if err := r.apply(event, data); err != nil {
+				// snapshot is reloaded after reconnection
+				r.lastID = ""
+				return err
+			}
+			r.lastID = id
+			event, data = "", ""
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+// apply updates the snapshot by the stream event
+func (r *Reader) apply(eventType, data string) error {
+	if eventType == "reset" {
+		return r.load()
+	}
+	event := struct {
+		ObjectCode domain.ObjectCode `json:"object_code"`
+		Data       *domain.Object    `json:"data"`
+	}{}
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	switch domain.EventType(eventType) {
+	case domain.EventObjectDelete:
+		delete(r.objects, event.ObjectCode)
+	case domain.EventObjectCreate, domain.EventObjectUpdate, domain.EventObjectRevert:
+		if event.Data != nil {
+			r.objects[event.ObjectCode] = event.Data
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package client_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Toggly/core/pkg/client"
+	asserts "github.com/stretchr/testify/assert"
+)
+
+func newEnvironments(c *client.Client) client.EnvironmentAPI {
+	c.Projects().Create(&client.ProjectInfo{Code: "project1", Status: client.ProjectStatusActive})
+	envs := c.Projects().For("project1").Environments()
+	envs.Create(&client.EnvironmentInfo{Code: "env1"})
+	envs.Create(&client.EnvironmentInfo{Code: "env2"})
+	envs.For("env1").Objects().Create(&client.ObjectInfo{Code: "base", Parameters: []*client.Parameter{
+		{Code: "enabled", Type: client.ParameterBool, Value: false},
+		{Code: "limit", Type: client.ParameterInt, Value: 10},
+		{Code: "ratio", Type: client.ParameterFloat, Value: 0.5},
+		{Code: "name", Type: client.ParameterString, Value: "base"},
+		{Code: "timeout", Type: client.ParameterDuration, Value: "1m30s"},
+		{Code: "hosts", Type: client.ParameterList, Value: []string{"a", "b"}},
+	}})
+	envs.For("env2").Objects().Create(&client.ObjectInfo{
+		Code:     "child",
+		Inherits: &client.ObjectInheritance{ProjectCode: "project1", EnvCode: "env1", ObjectCode: "base"},
+	})
+	return envs
+}
+
+func TestReaderGetters(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(100)
+	defer rs.Close()
+	newEnvironments(c)
+
+	r, err := c.NewReader("project1", "env1", client.ReaderOptions{Poll: time.Hour})
+	assert.Nil(err)
+	defer r.Close()
+
+	assert.False(r.Bool("base", "enabled", true))
+	assert.Equal(int64(10), r.Int("base", "limit", 0))
+	assert.Equal(0.5, r.Float("base", "ratio", 0))
+	assert.Equal("base", r.String("base", "name", ""))
+	assert.Equal(90*time.Second, r.Duration("base", "timeout", 0))
+	assert.Equal([]string{"a", "b"}, r.List("base", "hosts", nil))
+
+	assert.True(r.Bool("base", "missing", true), "missing parameter")
+	assert.True(r.Bool("missing", "enabled", true), "missing object")
+	assert.Equal(int64(5), r.Int("base", "name", 5), "type mismatch")
+	_, ok := r.Object("missing")
+	assert.False(ok)
+}
+
+func TestReaderPoll(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(100)
+	defer rs.Close()
+	envs := newEnvironments(c)
+
+	r, err := c.NewReader("project1", "env2", client.ReaderOptions{Poll: 10 * time.Millisecond})
+	assert.Nil(err)
+	defer r.Close()
+	assert.False(r.Bool("child", "enabled", true))
+
+	envs.For("env1").Objects().Update(&client.ObjectInfo{Code: "base", Parameters: boolParam(true)})
+	assert.True(eventually(func() bool { return r.Bool("child", "enabled", false) }))
+
+	_, err = c.NewReader("project1", "env3", client.ReaderOptions{Poll: time.Second})
+	assert.Equal(client.ErrEnvironmentNotFound, err)
+}
+
+func TestReaderStream(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(100)
+	defer rs.Close()
+	envs := newEnvironments(c)
+
+	var lock sync.Mutex
+	errs := make([]error, 0)
+	r, err := c.NewReader("project1", "env2", client.ReaderOptions{
+		RetryDelay: 10 * time.Millisecond,
+		OnError: func(err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	assert.Nil(err)
+	defer r.Close()
+	assert.Equal(int64(10), r.Int("child", "limit", 0))
+
+	objects := envs.For("env1").Objects()
+	objects.Update(&client.ObjectInfo{Code: "base", Parameters: []*client.Parameter{
+		{Code: "enabled", Type: client.ParameterBool, Value: true},
+		{Code: "limit", Type: client.ParameterInt, Value: 20},
+	}})
+	assert.True(eventually(func() bool { return r.Int("child", "limit", 0) == 20 }), "inheritor is updated")
+	assert.True(r.Bool("child", "enabled", false))
+
+	rs.CloseClientConnections()
+	// closed connections are not reused for the change
+	writer := client.New(rs.URL+"/api/v1", masterKey)
+	writer.Owner = "ow1"
+	writer.HTTPClient = &http.Client{Transport: &http.Transport{}}
+	_, err = writer.Projects().For("project1").Environments().For("env1").Objects().Update(&client.ObjectInfo{Code: "base", Parameters: []*client.Parameter{
+		{Code: "enabled", Type: client.ParameterBool, Value: true},
+		{Code: "limit", Type: client.ParameterInt, Value: 30},
+	}})
+	assert.Nil(err)
+	assert.True(eventually(func() bool { return r.Int("child", "limit", 0) == 30 }), "stream is resumed")
+	lock.Lock()
+	assert.NotEmpty(errs, "lost stream is reported")
+	lock.Unlock()
+
+	envs.For("env2").Objects().Create(&client.ObjectInfo{Code: "own", Parameters: boolParam(true)})
+	assert.True(eventually(func() bool { return r.Bool("own", "enabled", false) }), "object is created")
+	envs.For("env2").Objects().Delete("own", 0)
+	assert.True(eventually(func() bool {
+		_, ok := r.Object("own")
+		return !ok
+	}), "object is deleted")
+
+	_, err = c.NewReader("project1", "env3", client.ReaderOptions{})
+	assert.Equal(client.ErrEnvironmentNotFound, err)
+}
+
+func TestReaderReset(t *testing.T) {
+	assert := asserts.New(t)
+	rs, c := newServer(1)
+	defer rs.Close()
+	newEnvironments(c)
+
+	r, err := c.NewReader("project1", "env1", client.ReaderOptions{RetryDelay: 50 * time.Millisecond})
+	assert.Nil(err)
+	defer r.Close()
+
+	rs.CloseClientConnections()
+	writer := client.New(rs.URL+"/api/v1", masterKey)
+	writer.Owner = "ow1"
+	writer.HTTPClient = &http.Client{Transport: &http.Transport{}}
+	objects := writer.Projects().For("project1").Environments().For("env1").Objects()
+	for _, code := range []client.ObjectCode{"obj1", "obj2"} {
+		_, err = objects.Create(&client.ObjectInfo{Code: code, Parameters: boolParam(true)})
+		assert.Nil(err)
+	}
+	assert.True(eventually(func() bool {
+		return r.Bool("obj1", "enabled", false) && r.Bool("obj2", "enabled", false)
+	}), "objects are reloaded after missed events")
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type segmentRequest struct {
+	Code        domain.SegmentCode    `json:"code"`
+	Description string                `json:"description"`
+	Include     []string              `json:"include"`
+	Exclude     []string              `json:"exclude"`
+	Rules       []*domain.SegmentRule `json:"rules"`
+}
+
+type segmentAPI struct {
+	client      *Client
+	projectCode domain.ProjectCode
+}
+
+func (a *segmentAPI) path(segments ...interface{}) string {
+	return path(append([]interface{}{"project", a.projectCode, "segment"}, segments...)...)
+}
+
+func (a *segmentAPI) List() ([]*domain.Segment, error) {
+	list := make([]*domain.Segment, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *segmentAPI) Get(code domain.SegmentCode) (*domain.Segment, error) {
+	seg := &domain.Segment{}
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(code), out: seg}); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+func (a *segmentAPI) Create(info *api.SegmentInfo) (*domain.Segment, error) {
+	return a.save(http.MethodPost, info)
+}
+
+func (a *segmentAPI) Update(info *api.SegmentInfo) (*domain.Segment, error) {
+	return a.save(http.MethodPut, info)
+}
+
+func (a *segmentAPI) save(method string, info *api.SegmentInfo) (*domain.Segment, error) {
+	seg := &domain.Segment{}
+	err := a.client.do(&request{
+		method: method,
+		path:   a.path(),
+		body: &segmentRequest{
+			Code:        info.Code,
+			Description: info.Description,
+			Include:     info.Include,
+			Exclude:     info.Exclude,
+			Rules:       info.Rules,
+		},
+		out: seg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+func (a *segmentAPI) Delete(code domain.SegmentCode) error {
+	return a.client.do(&request{method: http.MethodDelete, path: a.path(code)})
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+// Aliases of api and domain types, so most of the client code needs only this package
+type (
+	Project              = domain.Project
+	ProjectCode          = domain.ProjectCode
+	ProjectStatus        = domain.ProjectStatus
+	Environment          = domain.Environment
+	EnvironmentCode      = domain.EnvironmentCode
+	Segment              = domain.Segment
+	SegmentCode          = domain.SegmentCode
+	SegmentRule          = domain.SegmentRule
+	Object               = domain.Object
+	ObjectCode           = domain.ObjectCode
+	ObjectInheritance    = domain.ObjectInheritance
+	ObjectRevision       = domain.ObjectRevision
+	Parameter            = domain.Parameter
+	ParameterCode        = domain.ParameterCode
+	ParameterType        = domain.ParameterType
+	ParameterConstraints = domain.ParameterConstraints
+	Rule                 = domain.Rule
+	RuleCondition        = domain.RuleCondition
+	RuleOperator         = domain.RuleOperator
+	Rollout              = domain.Rollout
+	Variation            = domain.Variation
+	EvaluationContext    = domain.EvaluationContext
+	ObjectValues         = domain.ObjectValues
+	EnvironmentValues    = domain.EnvironmentValues
+	Webhook              = domain.Webhook
+	WebhookID            = domain.WebhookID
+	WebhookDelivery      = domain.WebhookDelivery
+	Event                = domain.Event
+	EventType            = domain.EventType
+	Document             = domain.Document
+	DocumentProject      = domain.DocumentProject
+	DocumentSegment      = domain.DocumentSegment
+	DocumentEnvironment  = domain.DocumentEnvironment
+	DocumentObject       = domain.DocumentObject
+	ImportChange         = domain.ImportChange
+	ImportAction         = domain.ImportAction
+
+	ProjectInfo     = api.ProjectInfo
+	EnvironmentInfo = api.EnvironmentInfo
+	SegmentInfo     = api.SegmentInfo
+	ObjectInfo      = api.ObjectInfo
+	WebhookInfo     = api.WebhookInfo
+
+	ProjectAPI     = api.ProjectAPI
+	ForProjectAPI  = api.ForProjectAPI
+	EnvironmentAPI = api.EnvironmentAPI
+	SegmentAPI     = api.SegmentAPI
+	WebhookAPI     = api.WebhookAPI
+	ForObjectAPI   = api.ForObjectAPI
+	ObjectAPI      = api.ObjectAPI
+
+	ErrBadRequest      = api.ErrBadRequest
+	ErrObjectParameter = api.ErrObjectParameter
+)
+
+// Project statuses
+const (
+	ProjectStatusActive   = domain.ProjectStatusActive
+	ProjectStatusDisabled = domain.ProjectStatusDisabled
+)
+
+// Parameter types
+const (
+	ParameterBool     = domain.ParameterBool
+	ParameterString   = domain.ParameterString
+	ParameterInt      = domain.ParameterInt
+	ParameterEnum     = domain.ParameterEnum
+	ParameterFloat    = domain.ParameterFloat
+	ParameterJSON     = domain.ParameterJSON
+	ParameterList     = domain.ParameterList
+	ParameterDuration = domain.ParameterDuration
+	ParameterSemver   = domain.ParameterSemver
+)
+
+// Import actions
+const (
+	ImportCreate    = domain.ImportCreate
+	ImportUpdate    = domain.ImportUpdate
+	ImportUnchanged = domain.ImportUnchanged
+)
+
+// API errors restored from responses
+var (
+	ErrUnauthorized                = api.ErrUnauthorized
+	ErrForbidden                   = api.ErrForbidden
+	ErrVersionConflict             = api.ErrVersionConflict
+	ErrProjectNotFound             = api.ErrProjectNotFound
+	ErrProjectNotEmpty             = api.ErrProjectNotEmpty
+	ErrProjectDisabled             = api.ErrProjectDisabled
+	ErrEnvironmentNotFound         = api.ErrEnvironmentNotFound
+	ErrEnvironmentNotEmpty         = api.ErrEnvironmentNotEmpty
+	ErrEnvironmentProtected        = api.ErrEnvironmentProtected
+	ErrSegmentNotFound             = api.ErrSegmentNotFound
+	ErrSegmentInUse                = api.ErrSegmentInUse
+	ErrObjectNotFound              = api.ErrObjectNotFound
+	ErrObjectHasInheritors         = api.ErrObjectHasInheritors
+	ErrObjectParentNotExists       = api.ErrObjectParentNotExists
+	ErrObjectInheritorTypeMismatch = api.ErrObjectInheritorTypeMismatch
+	ErrRevisionNotFound            = api.ErrRevisionNotFound
+	ErrWebhookNotFound             = api.ErrWebhookNotFound
+)
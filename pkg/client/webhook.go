@@ -0,0 +1,91 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/Toggly/core/pkg/api"
+	"github.com/Toggly/core/pkg/domain"
+)
+
+type webhookRequest struct {
+	ID          domain.WebhookID         `json:"id"`
+	URL         string                   `json:"url"`
+	Secret      string                   `json:"secret"`
+	Events      []domain.EventType       `json:"events"`
+	EnvCodes    []domain.EnvironmentCode `json:"env_codes"`
+	ObjectCodes []domain.ObjectCode      `json:"object_codes"`
+}
+
+type webhookAPI struct {
+	client      *Client
+	projectCode domain.ProjectCode
+}
+
+func (a *webhookAPI) path(segments ...interface{}) string {
+	return path(append([]interface{}{"project", a.projectCode, "webhook"}, segments...)...)
+}
+
+func (a *webhookAPI) List() ([]*domain.Webhook, error) {
+	list := make([]*domain.Webhook, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *webhookAPI) Get(id domain.WebhookID) (*domain.Webhook, error) {
+	hook := &domain.Webhook{}
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(id), out: hook}); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (a *webhookAPI) Create(info *api.WebhookInfo) (*domain.Webhook, error) {
+	return a.save(http.MethodPost, info)
+}
+
+func (a *webhookAPI) Update(info *api.WebhookInfo) (*domain.Webhook, error) {
+	return a.save(http.MethodPut, info)
+}
+
+func (a *webhookAPI) save(method string, info *api.WebhookInfo) (*domain.Webhook, error) {
+	hook := &domain.Webhook{}
+	err := a.client.do(&request{
+		method: method,
+		path:   a.path(),
+		body: &webhookRequest{
+			ID:          info.ID,
+			URL:         info.URL,
+			Secret:      info.Secret,
+			Events:      info.Events,
+			EnvCodes:    info.EnvCodes,
+			ObjectCodes: info.ObjectCodes,
+		},
+		out: hook,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (a *webhookAPI) Delete(id domain.WebhookID) error {
+	return a.client.do(&request{method: http.MethodDelete, path: a.path(id)})
+}
+
+func (a *webhookAPI) Deliveries(id domain.WebhookID) ([]*domain.WebhookDelivery, error) {
+	list := make([]*domain.WebhookDelivery, 0)
+	if err := a.client.do(&request{method: http.MethodGet, path: a.path(id, "deliveries"), out: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (a *webhookAPI) Test(id domain.WebhookID) (*domain.WebhookDelivery, error) {
+	delivery := &domain.WebhookDelivery{}
+	if err := a.client.do(&request{method: http.MethodPost, path: a.path(id, "test"), out: delivery}); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+)
+
+// APIKeyID type
+type APIKeyID string
+
+// APIKey represents an owner api key.
+// Only the key hash is stored, the key itself is returned once on creation.
+// Key can be scoped to a project or to a single project environment.
+type APIKey struct {
+	ID              APIKeyID        `json:"id" bson:"id"`
+	OwnerID         string          `json:"owner" bson:"owner"`
+	Description     string          `json:"description"`
+	Role            Role            `json:"role"`
+	ProjectCode     ProjectCode     `json:"project_code,omitempty" bson:"project_code,omitempty"`
+	EnvironmentCode EnvironmentCode `json:"env_code,omitempty" bson:"env_code,omitempty"`
+	Hash            string          `json:"-" bson:"hash"`
+	Key             string          `json:"key,omitempty" bson:"-"`
+	RegDate         time.Time       `json:"reg_date" bson:"reg_date"`
+}
+
+// Allows returns true if the key grants the permission for the project environment.
+// Empty environment code means project level resource, empty project code means owner level resource.
+// Resources above the key scope are readable only, so scoped keys can list their project or environment.
+func (k *APIKey) Allows(p Permission, project ProjectCode, env EnvironmentCode) bool {
+	if !k.Role.Grants(p) {
+		return false
+	}
+	if k.ProjectCode == "" {
+		return true
+	}
+	if project != k.ProjectCode {
+		return project == "" && p <= PermissionRead
+	}
+	if k.EnvironmentCode == "" {
+		return true
+	}
+	if env != k.EnvironmentCode {
+		return env == "" && p <= PermissionRead
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditEntity type
+type AuditEntity string
+
+// Audit entities enum
+const (
+	AuditProject     AuditEntity = "project"
+	AuditEnvironment AuditEntity = "environment"
+	AuditSegment     AuditEntity = "segment"
+	AuditObject      AuditEntity = "object"
+	AuditKey         AuditEntity = "key"
+	AuditWebhook     AuditEntity = "webhook"
+)
+
+// AuditAction type
+type AuditAction string
+
+// Audit actions enum
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+	AuditRevert AuditAction = "revert"
+)
+
+// AuditRecord is an immutable record of mutating api call.
+// Entity is the entity path, e.g. `/project/p1/env/e1/object/o1`.
+// Before and After keep entity the way api returns it.
+type AuditRecord struct {
+	ID         string      `json:"id"`
+	Owner      string      `json:"owner"`
+	Actor      string      `json:"actor"`
+	RequestID  string      `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	Entity     string      `json:"entity"`
+	EntityType AuditEntity `json:"entity_type" bson:"entity_type"`
+	Action     AuditAction `json:"action"`
+	Date       time.Time   `json:"date"`
+	Before     interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After      interface{} `json:"after,omitempty" bson:"after,omitempty"`
+}
+
+// AuditFilter restricts audit records. Entity matches the entity path and everything below it,
+// zero From and To leave the time range open.
+type AuditFilter struct {
+	Entity string
+	Actor  string
+	From   time.Time
+	To     time.Time
+}
+
+// Match reports whether the record satisfies the filter
+func (f *AuditFilter) Match(rec *AuditRecord) bool {
+	if f.Entity != "" && rec.Entity != f.Entity && !strings.HasPrefix(rec.Entity, strings.TrimSuffix(f.Entity, "/")+"/") {
+		return false
+	}
+	if f.Actor != "" && rec.Actor != f.Actor {
+		return false
+	}
+	if !f.From.IsZero() && rec.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && rec.Date.After(f.To) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package domain
+
+// DocumentVersion is the version of project document format
+const DocumentVersion = 1
+
+// Document describes project configuration, it is used to move project between installations.
+// Objects keep own parameters, inherited ones are not included.
+type Document struct {
+	Version      int                    `json:"version"`
+	Project      *DocumentProject       `json:"project"`
+	Segments     []*DocumentSegment     `json:"segments"`
+	Environments []*DocumentEnvironment `json:"environments"`
+}
+
+// DocumentProject describes project of the document
+type DocumentProject struct {
+	Code        ProjectCode   `json:"code"`
+	Description string        `json:"description"`
+	Status      ProjectStatus `json:"status"`
+}
+
+// DocumentSegment describes project segment
+type DocumentSegment struct {
+	Code        SegmentCode    `json:"code"`
+	Description string         `json:"description"`
+	Include     []string       `json:"include,omitempty"`
+	Exclude     []string       `json:"exclude,omitempty"`
+	Rules       []*SegmentRule `json:"rules,omitempty"`
+}
+
+// DocumentEnvironment describes environment with its objects
+type DocumentEnvironment struct {
+	Code        EnvironmentCode   `json:"code"`
+	Description string            `json:"description"`
+	Protected   bool              `json:"protected"`
+	Objects     []*DocumentObject `json:"objects"`
+}
+
+// DocumentObject describes object with own parameters
+type DocumentObject struct {
+	Code        ObjectCode         `json:"code"`
+	Description string             `json:"description"`
+	Inherits    *ObjectInheritance `json:"inherits,omitempty"`
+	Parameters  []*Parameter       `json:"parameters"`
+}
+
+// ImportAction type
+type ImportAction string
+
+// Import actions enum
+const (
+	ImportCreate    ImportAction = "create"
+	ImportUpdate    ImportAction = "update"
+	ImportUnchanged ImportAction = "unchanged"
+)
+
+// ImportChange describes entity change made by import. EnvCode is set for objects.
+type ImportChange struct {
+	Entity  AuditEntity     `json:"entity"`
+	EnvCode EnvironmentCode `json:"env_code,omitempty"`
+	Code    string          `json:"code"`
+	Action  ImportAction    `json:"action"`
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// EventType type
+type EventType string
+
+// Event types enum
+const (
+	EventProjectCreate     EventType = "project.create"
+	EventProjectUpdate     EventType = "project.update"
+	EventProjectDelete     EventType = "project.delete"
+	EventEnvironmentCreate EventType = "environment.create"
+	EventEnvironmentUpdate EventType = "environment.update"
+	EventEnvironmentDelete EventType = "environment.delete"
+	EventSegmentCreate     EventType = "segment.create"
+	EventSegmentUpdate     EventType = "segment.update"
+	EventSegmentDelete     EventType = "segment.delete"
+	EventObjectCreate      EventType = "object.create"
+	EventObjectUpdate      EventType = "object.update"
+	EventObjectDelete      EventType = "object.delete"
+	EventObjectRevert      EventType = "object.revert"
+	EventPing              EventType = "ping"
+)
+
+// EventTypes lists event types available for subscription
+var EventTypes = []EventType{
+	EventProjectCreate, EventProjectUpdate, EventProjectDelete,
+	EventEnvironmentCreate, EventEnvironmentUpdate, EventEnvironmentDelete,
+	EventSegmentCreate, EventSegmentUpdate, EventSegmentDelete,
+	EventObjectCreate, EventObjectUpdate, EventObjectDelete, EventObjectRevert,
+}
+
+// Valid reports whether event type is available for subscription
+func (t EventType) Valid() bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event describes successful change. Data is the entity after the change, it is missing for delete.
+// Inheritors of changed object get object.update event with Parent referring to the changed object.
+type Event struct {
+	ID          string             `json:"id"`
+	Type        EventType          `json:"type"`
+	Owner       string             `json:"owner"`
+	ProjectCode ProjectCode        `json:"project_code"`
+	EnvCode     EnvironmentCode    `json:"env_code,omitempty"`
+	ObjectCode  ObjectCode         `json:"object_code,omitempty"`
+	SegmentCode SegmentCode        `json:"segment_code,omitempty"`
+	Parent      *ObjectInheritance `json:"parent,omitempty"`
+	Actor       string             `json:"actor,omitempty"`
+	Date        time.Time          `json:"date"`
+	Data        interface{}        `json:"data,omitempty"`
+}
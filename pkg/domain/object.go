@@ -12,6 +12,7 @@ type Object struct {
 	Description string             `json:"description"`
 	Inherits    *ObjectInheritance `json:"inherits"`
 	Parameters  []*Parameter       `json:"parameters"`
+	Version     int64              `json:"version"`
 }
 
 // ObjectInheritance type
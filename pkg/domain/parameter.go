@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/globalsign/mgo/bson"
+)
+
+// ParameterType type
+type ParameterType string
+
+// Parameter types enum
+const (
+	ParameterBool     ParameterType = "bool"
+	ParameterString   ParameterType = "string"
+	ParameterInt      ParameterType = "int"
+	ParameterEnum     ParameterType = "enum"
+	ParameterFloat    ParameterType = "float"
+	ParameterJSON     ParameterType = "json"
+	ParameterList     ParameterType = "list"
+	ParameterDuration ParameterType = "duration"
+	ParameterSemver   ParameterType = "semver"
+)
+
+// ParameterCode type
+type ParameterCode string
+
+// Parameter represents a flag data structure
+type Parameter struct {
+	Code          ParameterCode         `json:"code"`
+	Description   string                `json:"description"`
+	Type          ParameterType         `json:"type"`
+	Value         interface{}           `json:"value"`
+	AllowedValues []string              `json:"allowed_values,omitempty" bson:"allowed_values,omitempty"`
+	Schema        json.RawMessage       `json:"schema,omitempty" bson:"schema,omitempty"`
+	Constraints   *ParameterConstraints `json:"constraints,omitempty" bson:"constraints,omitempty"`
+	Rules         []*Rule               `json:"rules,omitempty" bson:"rules,omitempty"`
+	Rollout       *Rollout              `json:"rollout,omitempty" bson:"rollout,omitempty"`
+	// ClearRules and ClearRollout drop inherited rules and rollout. They are set by empty
+	// `rules` list and null `rollout` in JSON.
+	ClearRules   bool `json:"-" bson:"clear_rules,omitempty"`
+	ClearRollout bool `json:"-" bson:"clear_rollout,omitempty"`
+}
+
+// ParameterConstraints restricts parameter values.
+// Min and Max are applicable for int and float parameters,
+// Pattern, MinLength and MaxLength are applicable for string parameters.
+type ParameterConstraints struct {
+	Min       *float64 `json:"min,omitempty" bson:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty" bson:"max,omitempty"`
+	Pattern   string   `json:"pattern,omitempty" bson:"pattern,omitempty"`
+	MinLength *int     `json:"min_length,omitempty" bson:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty" bson:"max_length,omitempty"`
+}
+
+type parameter Parameter
+
+// GetBSON stores json values as strings, so documents with any keys can be saved
+func (p Parameter) GetBSON() (interface{}, error) {
+	if p.Type != ParameterJSON {
+		return parameter(p), nil
+	}
+	var err error
+	if p.Value, err = jsonString(p.Value); err != nil {
+		return nil, err
+	}
+	rules := make([]*Rule, len(p.Rules))
+	for i, r := range p.Rules {
+		rule := *r
+		if rule.Value, err = jsonString(r.Value); err != nil {
+			return nil, err
+		}
+		rules[i] = &rule
+	}
+	p.Rules = rules
+	return parameter(p), nil
+}
+
+// SetBSON restores parameter value type
+func (p *Parameter) SetBSON(raw bson.Raw) error {
+	if err := raw.Unmarshal((*parameter)(p)); err != nil {
+		return err
+	}
+	if p.Type == ParameterJSON {
+		var err error
+		if p.Value, err = fromJSONString(p.Value); err != nil {
+			return err
+		}
+		for _, r := range p.Rules {
+			if r.Value, err = fromJSONString(r.Value); err != nil {
+				return err
+			}
+		}
+	}
+	p.restoreTypes()
+	return nil
+}
+
+// UnmarshalJSON restores parameter value type. Empty `rules` list and null `rollout` clear inherited ones.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*parameter)(p)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	_, hasRules := fields["rules"]
+	_, hasRollout := fields["rollout"]
+	p.ClearRules = hasRules && len(p.Rules) == 0
+	p.ClearRollout = hasRollout && p.Rollout == nil
+	p.restoreTypes()
+	return nil
+}
+
+// MarshalJSON writes cleared rules as empty list and cleared rollout as null
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	if !p.ClearRules && !p.ClearRollout {
+		return json.Marshal(parameter(p))
+	}
+	out := struct {
+		parameter
+		Rules   *[]*Rule        `json:"rules,omitempty"`
+		Rollout json.RawMessage `json:"rollout,omitempty"`
+	}{parameter: parameter(p)}
+	if p.ClearRules {
+		out.Rules = &[]*Rule{}
+	} else if len(p.Rules) > 0 {
+		out.Rules = &p.Rules
+	}
+	if p.ClearRollout {
+		out.Rollout = json.RawMessage("null")
+	} else if p.Rollout != nil {
+		var err error
+		if out.Rollout, err = json.Marshal(p.Rollout); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(out)
+}
+
+func (p *Parameter) restoreTypes() {
+	p.Value = typedValue(p.Type, p.Value)
+	for _, r := range p.Rules {
+		if r != nil {
+			r.Value = typedValue(p.Type, r.Value)
+		}
+	}
+	if p.Rollout != nil {
+		for _, v := range p.Rollout.Variations {
+			if v != nil {
+				v.Value = typedValue(p.Type, v.Value)
+			}
+		}
+	}
+}
+
+func jsonString(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func fromJSONString(v interface{}) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	var res interface{}
+	err := json.Unmarshal([]byte(s), &res)
+	return res, err
+}
+
+// typedValue converts decoded value to the Go type of parameter.
+// Value is returned as is if conversion is not possible.
+func typedValue(t ParameterType, value interface{}) interface{} {
+	switch t {
+	case ParameterInt:
+		switch v := value.(type) {
+		case int:
+			return int64(v)
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+				return int64(v)
+			}
+		}
+	case ParameterFloat:
+		switch v := value.(type) {
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+	case ParameterList:
+		if list, ok := value.([]interface{}); ok {
+			res := make([]string, len(list))
+			for i, item := range list {
+				s, ok := item.(string)
+				if !ok {
+					return value
+				}
+				res[i] = s
+			}
+			return res
+		}
+	}
+	return value
+}
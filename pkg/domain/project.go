@@ -23,4 +23,5 @@ type Project struct {
 	Description string        `json:"description"`
 	RegDate     time.Time     `json:"reg_date" bson:"reg_date"`
 	Status      ProjectStatus `json:"status"`
+	Version     int64         `json:"version"`
 }
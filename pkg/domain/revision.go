@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// RevisionAction type
+type RevisionAction string
+
+// Revision actions enum
+const (
+	RevisionCreate RevisionAction = "create"
+	RevisionUpdate RevisionAction = "update"
+	RevisionDelete RevisionAction = "delete"
+	RevisionRevert RevisionAction = "revert"
+)
+
+// ObjectRevision is an immutable record of object change.
+// Object keeps the object state after the change, or before it for delete.
+type ObjectRevision struct {
+	Owner       string          `json:"owner"`
+	ProjectCode ProjectCode     `json:"project_code" bson:"project_code"`
+	EnvCode     EnvironmentCode `json:"env_code" bson:"env_code"`
+	ObjectCode  ObjectCode      `json:"object_code" bson:"object_code"`
+	Revision    int64           `json:"revision"`
+	Action      RevisionAction  `json:"action"`
+	RevertedTo  int64           `json:"reverted_to,omitempty" bson:"reverted_to,omitempty"`
+	Actor       string          `json:"actor"`
+	Date        time.Time       `json:"date"`
+	Object      *Object         `json:"object"`
+	Diff        []*Change       `json:"diff"`
+}
+
+// Change describes changed object field. Parameters are compared one by one as `parameters.<code>` fields.
+type Change struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty" bson:"old,omitempty"`
+	New   interface{} `json:"new,omitempty" bson:"new,omitempty"`
+}
@@ -0,0 +1,42 @@
+package domain
+
+// Role type
+type Role string
+
+// Role enum
+const (
+	RoleEvaluate Role = "evaluate"
+	RoleReadOnly Role = "read-only"
+	RoleEditor   Role = "editor"
+	RoleAdmin    Role = "admin"
+)
+
+// Permission type
+type Permission int
+
+// Permission enum. Each permission includes the previous ones.
+const (
+	PermissionEvaluate Permission = iota
+	PermissionRead
+	PermissionWrite
+	PermissionAdmin
+)
+
+var rolePermissions = map[Role]Permission{
+	RoleEvaluate: PermissionEvaluate,
+	RoleReadOnly: PermissionRead,
+	RoleEditor:   PermissionWrite,
+	RoleAdmin:    PermissionAdmin,
+}
+
+// Valid returns true for known roles
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Grants returns true if the role has the permission
+func (r Role) Grants(p Permission) bool {
+	granted, ok := rolePermissions[r]
+	return ok && p <= granted
+}
@@ -0,0 +1,15 @@
+package domain
+
+// Rollout splits users between parameter values by weights
+type Rollout struct {
+	Attribute  string       `json:"attribute,omitempty" bson:"attribute,omitempty"`
+	Salt       string       `json:"salt,omitempty" bson:"salt,omitempty"`
+	Variations []*Variation `json:"variations"`
+}
+
+// Variation is a parameter value assigned to a share of users.
+// Weight is a percentage, weights of rollout variations sum up to 100.
+type Variation struct {
+	Value  interface{} `json:"value"`
+	Weight float64     `json:"weight"`
+}
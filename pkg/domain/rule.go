@@ -0,0 +1,56 @@
+package domain
+
+// RuleOperator type
+type RuleOperator string
+
+// Rule operators enum
+const (
+	RuleOpEquals    RuleOperator = "equals"
+	RuleOpNotEquals RuleOperator = "not_equals"
+	RuleOpIn        RuleOperator = "in"
+	RuleOpNotIn     RuleOperator = "not_in"
+	RuleOpRegex     RuleOperator = "regex"
+	RuleOpSemverEq  RuleOperator = "semver_eq"
+	RuleOpSemverGt  RuleOperator = "semver_gt"
+	RuleOpSemverGte RuleOperator = "semver_gte"
+	RuleOpSemverLt  RuleOperator = "semver_lt"
+	RuleOpSemverLte RuleOperator = "semver_lte"
+)
+
+// ContextUserID is the attribute name refers to EvaluationContext.UserID
+const ContextUserID = "user_id"
+
+// RuleCondition compares context attribute with values
+type RuleCondition struct {
+	Attribute string       `json:"attribute"`
+	Operator  RuleOperator `json:"operator"`
+	Values    []string     `json:"values"`
+}
+
+// Rule sets parameter value if the user is in the segment and all conditions are met
+type Rule struct {
+	Segment    SegmentCode      `json:"segment,omitempty" bson:"segment,omitempty"`
+	Conditions []*RuleCondition `json:"conditions"`
+	Value      interface{}      `json:"value"`
+}
+
+// EvaluationContext describes the user parameters are evaluated for
+type EvaluationContext struct {
+	UserID     string            `json:"user_id"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Attribute returns context attribute value
+func (c *EvaluationContext) Attribute(name string) (string, bool) {
+	if name == ContextUserID && c.UserID != "" {
+		return c.UserID, true
+	}
+	v, ok := c.Attributes[name]
+	return v, ok
+}
+
+// ObjectValues contains evaluated object parameter values
+type ObjectValues map[ParameterCode]interface{}
+
+// EnvironmentValues contains evaluated parameter values of environment objects
+type EnvironmentValues map[ObjectCode]ObjectValues
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// SegmentCode type
+type SegmentCode string
+
+// Segment represents a reusable group of users within a project
+type Segment struct {
+	OwnerID     string         `json:"owner" bson:"owner"`
+	ProjectCode ProjectCode    `json:"project_code" bson:"project_code"`
+	Code        SegmentCode    `json:"code"`
+	Description string         `json:"description"`
+	Include     []string       `json:"include,omitempty" bson:"include,omitempty"`
+	Exclude     []string       `json:"exclude,omitempty" bson:"exclude,omitempty"`
+	Rules       []*SegmentRule `json:"rules,omitempty" bson:"rules,omitempty"`
+	RegDate     time.Time      `json:"reg_date" bson:"reg_date"`
+}
+
+// SegmentRule matches users if all conditions are met
+type SegmentRule struct {
+	Conditions []*RuleCondition `json:"conditions"`
+}
@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+// WebhookID type
+type WebhookID string
+
+// Webhook is a project events subscription. Empty filter matches all values,
+// environment and object filters skip events without environment or object.
+// Secret signs delivered payloads, it is returned only once on creation.
+type Webhook struct {
+	ID          WebhookID         `json:"id"`
+	Owner       string            `json:"owner"`
+	ProjectCode ProjectCode       `json:"project_code" bson:"project_code"`
+	URL         string            `json:"url"`
+	Secret      string            `json:"secret,omitempty"`
+	Events      []EventType       `json:"events,omitempty" bson:"events,omitempty"`
+	EnvCodes    []EnvironmentCode `json:"env_codes,omitempty" bson:"env_codes,omitempty"`
+	ObjectCodes []ObjectCode      `json:"object_codes,omitempty" bson:"object_codes,omitempty"`
+	RegDate     time.Time         `json:"reg_date" bson:"reg_date"`
+}
+
+// Matches reports whether the event passes webhook filters
+func (w *Webhook) Matches(e *Event) bool {
+	if e.Owner != w.Owner || e.ProjectCode != w.ProjectCode {
+		return false
+	}
+	if len(w.Events) > 0 && !containsEvent(w.Events, e.Type) {
+		return false
+	}
+	if len(w.EnvCodes) > 0 && !containsEnv(w.EnvCodes, e.EnvCode) {
+		return false
+	}
+	if len(w.ObjectCodes) > 0 && !containsObject(w.ObjectCodes, e.ObjectCode) {
+		return false
+	}
+	return true
+}
+
+func containsEvent(list []EventType, t EventType) bool {
+	for _, v := range list {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+func containsEnv(list []EnvironmentCode, code EnvironmentCode) bool {
+	for _, v := range list {
+		if v == code {
+			return true
+		}
+	}
+	return false
+}
+
+func containsObject(list []ObjectCode, code ObjectCode) bool {
+	for _, v := range list {
+		if v == code {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is a record of event delivery attempt
+type WebhookDelivery struct {
+	ID          string      `json:"id"`
+	Owner       string      `json:"owner"`
+	ProjectCode ProjectCode `json:"project_code" bson:"project_code"`
+	WebhookID   WebhookID   `json:"webhook_id" bson:"webhook_id"`
+	EventID     string      `json:"event_id" bson:"event_id"`
+	EventType   EventType   `json:"event_type" bson:"event_type"`
+	Attempt     int         `json:"attempt"`
+	Date        time.Time   `json:"date"`
+	StatusCode  int         `json:"status_code,omitempty" bson:"status_code,omitempty"`
+	Error       string      `json:"error,omitempty" bson:"error,omitempty"`
+	Success     bool        `json:"success"`
+}